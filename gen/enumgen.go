@@ -100,6 +100,35 @@ func main() {
 			{Key: "a6"},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "card_size",
+		Desc: "holds a standard card dimension",
+		Values: []enumValue{
+			{
+				Key:    "poker",
+				String: "Poker (2.5 x 3.5 in)",
+			},
+			{
+				Key:    "bridge",
+				String: "Bridge (2.25 x 3.5 in)",
+			},
+			{
+				Key:    "tarot",
+				String: "Tarot (2.75 x 4.75 in)",
+			},
+			{
+				Key:    "index_3x5",
+				Name:   "Index3x5",
+				String: "Index (3 x 5 in)",
+			},
+			{
+				Key:    "index_4x6",
+				Name:   "Index4x6",
+				String: "Index (4 x 6 in)",
+			},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "model",
 		Name: "display_option",
@@ -224,6 +253,16 @@ func main() {
 			},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "bundle_conflict_action",
+		Desc: "holds the action to take when a file being imported from a bundle conflicts with an existing file",
+		Values: []enumValue{
+			{Key: "keep_existing"},
+			{Key: "replace_existing"},
+			{Key: "keep_both"},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "model",
 		Name: "threshold_op",
@@ -468,6 +507,43 @@ func main() {
 			},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "feature_condition_type",
+		Desc: "holds the type of condition that controls whether a Feature is active",
+		Values: []enumValue{
+			{
+				Name:   "Always",
+				Key:    "always",
+				String: "Always active",
+			},
+			{
+				Name:   "Expression",
+				Key:    "expression",
+				String: "Active while the expression is true",
+			},
+			{
+				Name:   "Encumbrance",
+				Key:    "encumbrance",
+				String: "Active while encumbrance is between",
+			},
+			{
+				Name:   "EquippedName",
+				Key:    "equipped_name",
+				String: "Active while equipped with an item named",
+			},
+			{
+				Name:   "EquippedTag",
+				Key:    "equipped_tag",
+				String: "Active while equipped with an item tagged",
+			},
+			{
+				Name:   "Toggle",
+				Key:    "toggle",
+				String: "Active while toggled on",
+			},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "model",
 		Name: "prereq_type",
@@ -514,6 +590,11 @@ func main() {
 				Key:    "spell_prereq",
 				String: "spell(s)",
 			},
+			{
+				Name:   "Expression",
+				Key:    "expression_prereq",
+				String: "satisfies the expression",
+			},
 		},
 	})
 	processSourceTemplate(enumTmpl, &enumInfo{
@@ -889,6 +970,25 @@ func main() {
 			},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "custom_block_type",
+		Desc: "holds the type of content shown by a custom sheet block",
+		Values: []enumValue{
+			{
+				Key:    "attributes",
+				String: "Attributes & Pools",
+			},
+			{
+				Key:    "skills",
+				String: "Skills With Tags",
+			},
+			{
+				Key:    "notes",
+				String: "Notes Containing Text",
+			},
+		},
+	})
 	processSourceTemplate(enumTmpl, &enumInfo{
 		Pkg:  "model",
 		Name: "name_generation_type",
@@ -898,6 +998,64 @@ func main() {
 			{Key: "markov_chain"},
 		},
 	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "reaction_situation",
+		Desc: "holds the situation a reaction roll is being made for",
+		Values: []enumValue{
+			{Key: "general"},
+			{
+				Key:    "combat",
+				String: "Potential Combat",
+			},
+			{
+				Key:    "commerce",
+				String: "Commercial Transaction",
+			},
+			{
+				Key:    "aid",
+				String: "Request for Aid",
+			},
+			{
+				Key:    "information",
+				String: "Request for Information",
+			},
+		},
+	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "reaction_level",
+		Desc: "holds the attitude that results from a reaction roll",
+		Values: []enumValue{
+			{Key: "disastrous"},
+			{Key: "very_bad"},
+			{Key: "bad"},
+			{Key: "poor"},
+			{Key: "neutral"},
+			{Key: "good"},
+			{Key: "very_good"},
+			{Key: "excellent"},
+		},
+	})
+	processSourceTemplate(enumTmpl, &enumInfo{
+		Pkg:  "model",
+		Name: "compliance_issue_kind",
+		Desc: "holds the kind of problem found by a tech level and legality check",
+		Values: []enumValue{
+			{
+				Key:    "equipment_tech_level",
+				String: "Equipment Above Tech Level",
+			},
+			{
+				Key:    "tech_level_mismatch",
+				String: "Tech Level Mismatch",
+			},
+			{
+				Key:    "illegal",
+				String: "Illegal Under Control Rating",
+			},
+		},
+	})
 }
 
 func removeExistingGenFiles() {
@@ -39,6 +39,7 @@ func main() {
 
 	settingsName := cmdline.AppCmdName + "_prefs.json"
 	model.SettingsPath = filepath.Join(paths.AppDataDir(), settingsName)
+	model.RecoveryPath = filepath.Join(paths.AppDataDir(), "recovery")
 	// Look for a settings file co-located with the executable and prefer that over the one in the app data dir.
 	if dir, err := toolbox.AppDir(); err == nil {
 		settingsPath := filepath.Join(dir, settingsName)
@@ -21,6 +21,7 @@ import (
 	"github.com/richardwilkes/toolbox/eval"
 	"github.com/richardwilkes/toolbox/log/jot"
 	xfs "github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 )
 
 // DefaultAncestry holds the name of the default ancestry.
@@ -95,8 +96,8 @@ func (a *Ancestry) Save(filePath string) error {
 }
 
 // RandomGender returns a randomized gender.
-func (a *Ancestry) RandomGender(not string) string {
-	if choice := ChooseWeightedAncestryOptions(a.GenderOptions, func(o *AncestryOptions) bool {
+func (a *Ancestry) RandomGender(rnd rand.Randomizer, not string) string {
+	if choice := ChooseWeightedAncestryOptions(rnd, a.GenderOptions, func(o *AncestryOptions) bool {
 		return o.Name == not
 	}); choice != nil {
 		return choice.Name
@@ -116,89 +117,89 @@ func (a *Ancestry) GenderedOptions(gender string) *AncestryOptions {
 }
 
 // RandomHeight returns a randomized height.
-func (a *Ancestry) RandomHeight(resolver eval.VariableResolver, gender string, not Length) Length {
+func (a *Ancestry) RandomHeight(rnd rand.Randomizer, resolver eval.VariableResolver, gender string, not Length) Length {
 	if options := a.GenderedOptions(gender); options != nil && options.HeightFormula != "" {
-		return options.RandomHeight(resolver, not)
+		return options.RandomHeight(rnd, resolver, not)
 	}
 	if a.CommonOptions != nil && a.CommonOptions.HeightFormula != "" {
-		return a.CommonOptions.RandomHeight(resolver, not)
+		return a.CommonOptions.RandomHeight(rnd, resolver, not)
 	}
 	return LengthFromInteger(defaultHeight, Inch)
 }
 
 // RandomWeight returns a randomized weight.
-func (a *Ancestry) RandomWeight(resolver eval.VariableResolver, gender string, not Weight) Weight {
+func (a *Ancestry) RandomWeight(rnd rand.Randomizer, resolver eval.VariableResolver, gender string, not Weight) Weight {
 	if options := a.GenderedOptions(gender); options != nil && options.WeightFormula != "" {
-		return options.RandomWeight(resolver, not)
+		return options.RandomWeight(rnd, resolver, not)
 	}
 	if a.CommonOptions != nil && a.CommonOptions.WeightFormula != "" {
-		return a.CommonOptions.RandomWeight(resolver, not)
+		return a.CommonOptions.RandomWeight(rnd, resolver, not)
 	}
 	return WeightFromInteger(defaultWeight, Pound)
 }
 
 // RandomAge returns a randomized age.
-func (a *Ancestry) RandomAge(resolver eval.VariableResolver, gender string, not int) int {
+func (a *Ancestry) RandomAge(rnd rand.Randomizer, resolver eval.VariableResolver, gender string, not int) int {
 	if options := a.GenderedOptions(gender); options != nil && options.AgeFormula != "" {
-		return options.RandomAge(resolver, not)
+		return options.RandomAge(rnd, resolver, not)
 	}
 	if a.CommonOptions != nil && a.CommonOptions.AgeFormula != "" {
-		return a.CommonOptions.RandomAge(resolver, not)
+		return a.CommonOptions.RandomAge(rnd, resolver, not)
 	}
 	return defaultAge
 }
 
 // RandomHair returns a randomized hair.
-func (a *Ancestry) RandomHair(gender, not string) string {
+func (a *Ancestry) RandomHair(rnd rand.Randomizer, gender, not string) string {
 	if options := a.GenderedOptions(gender); options != nil && len(options.HairOptions) != 0 {
-		return options.RandomHair(not)
+		return options.RandomHair(rnd, not)
 	}
 	if a.CommonOptions != nil && len(a.CommonOptions.HairOptions) != 0 {
-		return a.CommonOptions.RandomHair(not)
+		return a.CommonOptions.RandomHair(rnd, not)
 	}
 	return defaultHair
 }
 
 // RandomEyes returns a randomized eyes.
-func (a *Ancestry) RandomEyes(gender, not string) string {
+func (a *Ancestry) RandomEyes(rnd rand.Randomizer, gender, not string) string {
 	if options := a.GenderedOptions(gender); options != nil && len(options.EyeOptions) != 0 {
-		return options.RandomEye(not)
+		return options.RandomEye(rnd, not)
 	}
 	if a.CommonOptions != nil && len(a.CommonOptions.EyeOptions) != 0 {
-		return a.CommonOptions.RandomEye(not)
+		return a.CommonOptions.RandomEye(rnd, not)
 	}
 	return defaultEye
 }
 
 // RandomSkin returns a randomized skin.
-func (a *Ancestry) RandomSkin(gender, not string) string {
+func (a *Ancestry) RandomSkin(rnd rand.Randomizer, gender, not string) string {
 	if options := a.GenderedOptions(gender); options != nil && len(options.SkinOptions) != 0 {
-		return options.RandomSkin(not)
+		return options.RandomSkin(rnd, not)
 	}
 	if a.CommonOptions != nil && len(a.CommonOptions.SkinOptions) != 0 {
-		return a.CommonOptions.RandomSkin(not)
+		return a.CommonOptions.RandomSkin(rnd, not)
 	}
 	return defaultSkin
 }
 
 // RandomHandedness returns a randomized handedness.
-func (a *Ancestry) RandomHandedness(gender, not string) string {
+func (a *Ancestry) RandomHandedness(rnd rand.Randomizer, gender, not string) string {
 	if options := a.GenderedOptions(gender); options != nil && len(options.HandednessOptions) != 0 {
-		return options.RandomHandedness(not)
+		return options.RandomHandedness(rnd, not)
 	}
 	if a.CommonOptions != nil && len(a.CommonOptions.HandednessOptions) != 0 {
-		return a.CommonOptions.RandomHandedness(not)
+		return a.CommonOptions.RandomHandedness(rnd, not)
 	}
 	return defaultHandedness
 }
 
 // RandomName returns a randomized name.
-func (a *Ancestry) RandomName(nameGeneratorRefs []*NameGeneratorRef, gender string) string {
+func (a *Ancestry) RandomName(rnd rand.Randomizer, nameGeneratorRefs []*NameGeneratorRef, gender string) string {
 	if options := a.GenderedOptions(gender); options != nil && len(options.NameGenerators) != 0 {
-		return options.RandomName(nameGeneratorRefs)
+		return options.RandomName(rnd, nameGeneratorRefs)
 	}
 	if a.CommonOptions != nil && len(a.CommonOptions.NameGenerators) != 0 {
-		return a.CommonOptions.RandomName(nameGeneratorRefs)
+		return a.CommonOptions.RandomName(rnd, nameGeneratorRefs)
 	}
 	return ""
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/toolbox/eval"
 	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 )
 
 const (
@@ -44,10 +45,10 @@ type AncestryOptions struct {
 }
 
 // RandomHeight returns a randomized height.
-func (o *AncestryOptions) RandomHeight(resolver eval.VariableResolver, not Length) Length {
+func (o *AncestryOptions) RandomHeight(rnd rand.Randomizer, resolver eval.VariableResolver, not Length) Length {
 	def := LengthFromInteger(defaultHeight, Inch)
 	for i := 0; i < maximumRandomTries; i++ {
-		value := Length(fxp.EvaluateToNumber(o.HeightFormula, resolver))
+		value := Length(evaluateToNumberWithRandomizer(rnd, o.HeightFormula, resolver))
 		if value <= 0 {
 			value = def
 		}
@@ -59,10 +60,10 @@ func (o *AncestryOptions) RandomHeight(resolver eval.VariableResolver, not Lengt
 }
 
 // RandomWeight returns a randomized weight.
-func (o *AncestryOptions) RandomWeight(resolver eval.VariableResolver, not Weight) Weight {
+func (o *AncestryOptions) RandomWeight(rnd rand.Randomizer, resolver eval.VariableResolver, not Weight) Weight {
 	def := WeightFromInteger(defaultWeight, Pound)
 	for i := 0; i < maximumRandomTries; i++ {
-		value := Weight(fxp.EvaluateToNumber(o.WeightFormula, resolver))
+		value := Weight(evaluateToNumberWithRandomizer(rnd, o.WeightFormula, resolver))
 		if value <= 0 {
 			value = def
 		}
@@ -74,9 +75,9 @@ func (o *AncestryOptions) RandomWeight(resolver eval.VariableResolver, not Weigh
 }
 
 // RandomAge returns a randomized age.
-func (o *AncestryOptions) RandomAge(resolver eval.VariableResolver, not int) int {
+func (o *AncestryOptions) RandomAge(rnd rand.Randomizer, resolver eval.VariableResolver, not int) int {
 	for i := 0; i < maximumRandomTries; i++ {
-		age := fxp.As[int](fxp.EvaluateToNumber(o.AgeFormula, resolver))
+		age := fxp.As[int](evaluateToNumberWithRandomizer(rnd, o.AgeFormula, resolver))
 		if age <= 0 {
 			age = defaultAge
 		}
@@ -88,39 +89,39 @@ func (o *AncestryOptions) RandomAge(resolver eval.VariableResolver, not int) int
 }
 
 // RandomHair returns a randomized hair.
-func (o *AncestryOptions) RandomHair(not string) string {
-	if choice := ChooseWeightedStringOption(o.HairOptions, not); choice != "" {
+func (o *AncestryOptions) RandomHair(rnd rand.Randomizer, not string) string {
+	if choice := ChooseWeightedStringOption(rnd, o.HairOptions, not); choice != "" {
 		return choice
 	}
 	return defaultHair
 }
 
 // RandomEye returns a randomized eye.
-func (o *AncestryOptions) RandomEye(not string) string {
-	if choice := ChooseWeightedStringOption(o.EyeOptions, not); choice != "" {
+func (o *AncestryOptions) RandomEye(rnd rand.Randomizer, not string) string {
+	if choice := ChooseWeightedStringOption(rnd, o.EyeOptions, not); choice != "" {
 		return choice
 	}
 	return defaultEye
 }
 
 // RandomSkin returns a randomized skin.
-func (o *AncestryOptions) RandomSkin(not string) string {
-	if choice := ChooseWeightedStringOption(o.SkinOptions, not); choice != "" {
+func (o *AncestryOptions) RandomSkin(rnd rand.Randomizer, not string) string {
+	if choice := ChooseWeightedStringOption(rnd, o.SkinOptions, not); choice != "" {
 		return choice
 	}
 	return defaultSkin
 }
 
 // RandomHandedness returns a randomized handedness.
-func (o *AncestryOptions) RandomHandedness(not string) string {
-	if choice := ChooseWeightedStringOption(o.HandednessOptions, not); choice != "" {
+func (o *AncestryOptions) RandomHandedness(rnd rand.Randomizer, not string) string {
+	if choice := ChooseWeightedStringOption(rnd, o.HandednessOptions, not); choice != "" {
 		return choice
 	}
 	return defaultHandedness
 }
 
 // RandomName returns a randomized name.
-func (o *AncestryOptions) RandomName(nameGeneratorRefs []*NameGeneratorRef) string {
+func (o *AncestryOptions) RandomName(rnd rand.Randomizer, nameGeneratorRefs []*NameGeneratorRef) string {
 	m := make(map[string]*NameGeneratorRef)
 	for _, one := range nameGeneratorRefs {
 		m[one.FileRef.Name] = one
@@ -131,7 +132,7 @@ func (o *AncestryOptions) RandomName(nameGeneratorRefs []*NameGeneratorRef) stri
 			if generator, err := ref.Generator(); err != nil {
 				jot.Error(err)
 			} else {
-				if name := strings.TrimSpace(generator.Generate()); name != "" {
+				if name := strings.TrimSpace(generator.Generate(rnd)); name != "" {
 					if buffer.Len() != 0 {
 						buffer.WriteByte(' ')
 					}
@@ -26,6 +26,7 @@ type AttributeBonus struct {
 	Limitation BonusLimitation `json:"limitation,omitempty"`
 	Attribute  string          `json:"attribute"`
 	LeveledAmount
+	FeatureConditionHolder
 	owner fmt.Stringer
 }
 
@@ -30,6 +30,7 @@ const (
 	ParryFlag
 	BlockFlag
 	SkillFlag
+	PoolsOnlyFlag // Restricts the defined attributes to pools
 )
 
 // AttributeChoice holds a single attribute choice.
@@ -52,6 +53,9 @@ func AttributeChoices(entity *Entity, prefix string, flags AttributeFlags, curre
 		choices = append(choices, &AttributeChoice{Key: "10", Title: prefix + "10"})
 	}
 	for _, def := range list {
+		if flags&PoolsOnlyFlag != 0 && def.Type != PoolAttributeType {
+			continue
+		}
 		choices = append(choices, &AttributeChoice{Key: def.DefID, Title: prefix + def.Name})
 	}
 	if flags&SizeFlag != 0 {
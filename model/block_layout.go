@@ -73,8 +73,7 @@ func NewBlockLayoutFromString(str string) (blockLayout *BlockLayout, inputWasVal
 				break
 			}
 			part = mapOldLayoutKeys(part)
-			if remaining[part] {
-				delete(remaining, part)
+			if claimBlockLayoutKey(remaining, part) {
 				parts = append(parts, part)
 			} else {
 				inputWasValid = false
@@ -94,6 +93,22 @@ func NewBlockLayoutFromString(str string) (blockLayout *BlockLayout, inputWasVal
 	return &BlockLayout{Layout: layout}, inputWasValid
 }
 
+// claimBlockLayoutKey returns true if the key is valid and has not already been used. Custom block keys are always
+// considered valid the first time they are seen.
+func claimBlockLayoutKey(remaining map[string]bool, key string) bool {
+	if remaining[key] {
+		delete(remaining, key)
+		return true
+	}
+	if IsCustomBlockKey(key) {
+		if _, used := remaining[key]; !used {
+			remaining[key] = false
+			return true
+		}
+	}
+	return false
+}
+
 func mapOldLayoutKeys(key string) string {
 	if key == "advantages" {
 		return BlockLayoutTraitsKey
@@ -109,8 +124,7 @@ func (b *BlockLayout) EnsureValidity() {
 		var parts []string
 		for _, part := range strings.Split(strings.ToLower(txt.CollapseSpaces(line)), " ") {
 			part = mapOldLayoutKeys(part)
-			if remaining[part] {
-				delete(remaining, part)
+			if claimBlockLayoutKey(remaining, part) {
 				parts = append(parts, part)
 				if len(parts) > 1 {
 					break
@@ -131,6 +145,51 @@ func (b *BlockLayout) EnsureValidity() {
 	b.Layout = layout
 }
 
+// SyncCustomKeys removes any custom block keys that aren't in the list and appends any that are missing.
+func (b *BlockLayout) SyncCustomKeys(keys []string) {
+	valid := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		valid[k] = true
+	}
+	var layout []string
+	for _, line := range b.Layout {
+		var parts []string
+		for _, part := range strings.Split(strings.ToLower(txt.CollapseSpaces(line)), " ") {
+			if part == "" {
+				continue
+			}
+			if !IsCustomBlockKey(part) {
+				parts = append(parts, part)
+			} else if valid[part] {
+				delete(valid, part)
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) != 0 {
+			layout = append(layout, strings.Join(parts, " "))
+		}
+	}
+	for _, k := range keys {
+		if valid[k] {
+			layout = append(layout, k)
+		}
+	}
+	b.Layout = layout
+}
+
+// ReplaceKey replaces any occurrence of the old key with the new key.
+func (b *BlockLayout) ReplaceKey(oldKey, newKey string) {
+	for i, line := range b.Layout {
+		parts := strings.Split(strings.ToLower(txt.CollapseSpaces(line)), " ")
+		for j, part := range parts {
+			if part == oldKey {
+				parts[j] = newKey
+			}
+		}
+		b.Layout[i] = strings.Join(parts, " ")
+	}
+}
+
 // ByRow breaks the layout down into rows.
 func (b *BlockLayout) ByRow() [][]string {
 	var layout [][]string
@@ -139,8 +198,7 @@ func (b *BlockLayout) ByRow() [][]string {
 		var parts []string
 		for _, part := range strings.Split(strings.ToLower(txt.CollapseSpaces(line)), " ") {
 			part = mapOldLayoutKeys(part)
-			if remaining[part] {
-				delete(remaining, part)
+			if claimBlockLayoutKey(remaining, part) {
 				parts = append(parts, part)
 			}
 		}
@@ -222,7 +280,15 @@ func (b *BlockLayout) HTMLGridTemplate() string {
 	var buffer strings.Builder
 	remaining := CreateFullKeySet()
 	for _, line := range b.Layout {
-		parts := strings.Split(strings.ToLower(txt.CollapseSpaces(line)), " ")
+		var parts []string
+		for _, one := range strings.Split(strings.ToLower(txt.CollapseSpaces(line)), " ") {
+			if !IsCustomBlockKey(one) {
+				parts = append(parts, one)
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
 		part := mapOldLayoutKeys(parts[0])
 		if part != "" && remaining[part] {
 			delete(remaining, part)
@@ -0,0 +1,456 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/json"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/log/jot"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+const (
+	bundleTypeKey      = "character_bundle"
+	bundleManifestName = "manifest.json"
+	bundleSettingsDir  = "Settings"
+	bundleLibraryDir   = "Library"
+	maxBundleFileSize  = 64 * 1024 * 1024
+)
+
+type bundleManifest struct {
+	Type    string   `json:"type"`
+	Version int      `json:"version"`
+	Sheet   string   `json:"sheet"`
+	Files   []string `json:"files,omitempty"`
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// ExportBundle writes a self-contained character bundle to the given path. In addition to the sheet, the bundle holds
+// the ancestry, calendar and name lists the sheet relies upon, the page reference mappings for the references it
+// contains, and any data files from libraries other than the Master Library that contain items used by the sheet.
+func ExportBundle(entity *Entity, filePath string) error {
+	name := xfs.TrimExtension(filepath.Base(filePath))
+	var buffer bytes.Buffer
+	if err := jio.Save(context.Background(), &buffer, entity); err != nil {
+		return err
+	}
+	sheetName := name + SheetExt
+	files := []*bundleFile{{name: sheetName, data: buffer.Bytes()}}
+	files = append(files, collectBundleSettingsFiles(entity, name)...)
+	files = append(files, collectBundleLibraryFiles(entity)...)
+	manifest := &bundleManifest{
+		Type:    bundleTypeKey,
+		Version: CurrentDataVersion,
+		Sheet:   sheetName,
+	}
+	for _, one := range files {
+		manifest.Files = append(manifest.Files, one.name)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	files = append(files, &bundleFile{name: bundleManifestName, data: data})
+	buffer.Reset()
+	w := zip.NewWriter(&buffer)
+	for _, one := range files {
+		var fw io.Writer
+		if fw, err = w.Create(one.name); err != nil {
+			return errs.Wrap(err)
+		}
+		if _, err = fw.Write(one.data); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		return errs.Wrap(err)
+	}
+	if err = os.WriteFile(filePath, buffer.Bytes(), 0o640); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+func collectBundleSettingsFiles(entity *Entity, name string) []*bundleFile {
+	globalSettings := GlobalSettings()
+	libraries := globalSettings.Libraries()
+	var files []*bundleFile
+	addRef := func(ref *NamedFileRef) {
+		if ref == nil {
+			return
+		}
+		data, err := fs.ReadFile(ref.FileSystem, ref.FilePath)
+		if err != nil {
+			jot.Warn(errs.NewWithCause("unable to read "+ref.FilePath, err))
+			return
+		}
+		files = append(files, &bundleFile{
+			name: path.Join(bundleSettingsDir, path.Base(ref.FilePath)),
+			data: data,
+		})
+	}
+	ancestries := AvailableAncestries(libraries)
+	var ancestry *Ancestry
+	Traverse(func(t *Trait) bool {
+		if t.Container() && t.ContainerType == RaceContainerType {
+			if ref := findLibraryNamedFileRef(ancestries, t.Ancestry); ref != nil {
+				addRef(ref)
+				var err error
+				if ancestry, err = NewAncestryFromFile(ref.FileSystem, ref.FilePath); err != nil {
+					jot.Warn(err)
+				}
+				return true
+			}
+		}
+		return false
+	}, true, false, entity.Traits...)
+	if ancestry != nil {
+		names := make(map[string]bool)
+		if ancestry.CommonOptions != nil {
+			for _, one := range ancestry.CommonOptions.NameGenerators {
+				names[one] = true
+			}
+		}
+		for _, option := range ancestry.GenderOptions {
+			if option.Value != nil {
+				for _, one := range option.Value.NameGenerators {
+					names[one] = true
+				}
+			}
+		}
+		for _, one := range AvailableNameGenerators(libraries) {
+			if names[one.FileRef.Name] && one.FileRef.FileSystem != fs.FS(embeddedFS) {
+				addRef(one.FileRef)
+			}
+		}
+	}
+	addRef(findLibraryNamedFileRef(AvailableCalendarRefs(libraries), globalSettings.CalendarRefFor(entity).Name))
+	var refs PageRefs
+	for key := range collectPageRefKeys(entity) {
+		if ref := globalSettings.LookupPageRef(key); ref != nil {
+			refs.Set(ref)
+		}
+	}
+	if !refs.ShouldOmit() {
+		var buffer bytes.Buffer
+		if err := jio.Save(context.Background(), &buffer, &refs); err != nil {
+			jot.Warn(err)
+		} else {
+			files = append(files, &bundleFile{
+				name: path.Join(bundleSettingsDir, name+PageRefSettingsExt),
+				data: buffer.Bytes(),
+			})
+		}
+	}
+	return files
+}
+
+func findLibraryNamedFileRef(sets []*NamedFileSet, name string) *NamedFileRef {
+	for _, set := range sets {
+		for _, one := range set.List {
+			if one.Name == name {
+				if one.FileSystem == fs.FS(embeddedFS) {
+					// Built-in files are available to everyone, so there is no need to bundle them
+					return nil
+				}
+				return one
+			}
+		}
+	}
+	return nil
+}
+
+func collectPageRefKeys(entity *Entity) map[string]bool {
+	keys := make(map[string]bool)
+	add := func(refs string) {
+		for _, one := range strings.FieldsFunc(refs, func(ch rune) bool { return ch == ',' || ch == ';' }) {
+			if key := pageRefKey(strings.TrimSpace(one)); key != "" {
+				keys[key] = true
+			}
+		}
+	}
+	Traverse(func(t *Trait) bool {
+		add(t.PageRef)
+		Traverse(func(mod *TraitModifier) bool {
+			add(mod.PageRef)
+			return false
+		}, false, false, t.Modifiers...)
+		return false
+	}, false, false, entity.Traits...)
+	Traverse(func(s *Skill) bool {
+		add(s.PageRef)
+		return false
+	}, false, false, entity.Skills...)
+	Traverse(func(s *Spell) bool {
+		add(s.PageRef)
+		return false
+	}, false, false, entity.Spells...)
+	Traverse(func(e *Equipment) bool {
+		add(e.PageRef)
+		Traverse(func(mod *EquipmentModifier) bool {
+			add(mod.PageRef)
+			return false
+		}, false, false, e.Modifiers...)
+		return false
+	}, false, false, append(append([]*Equipment{}, entity.CarriedEquipment...), entity.OtherEquipment...)...)
+	Traverse(func(n *Note) bool {
+		add(n.PageRef)
+		return false
+	}, false, false, entity.Notes...)
+	return keys
+}
+
+// pageRefKey returns the key portion of a page reference, e.g. "B" for "B22". Links are ignored.
+func pageRefKey(ref string) string {
+	lower := strings.ToLower(ref)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "md:") {
+		return ""
+	}
+	i := len(ref)
+	for i > 0 && ref[i-1] >= '0' && ref[i-1] <= '9' {
+		i--
+	}
+	if i == len(ref) {
+		return ""
+	}
+	return ref[:i]
+}
+
+func collectBundleLibraryFiles(entity *Entity) []*bundleFile {
+	names := make(map[string]map[string]bool)
+	add := func(ext, name string) {
+		m, ok := names[ext]
+		if !ok {
+			m = make(map[string]bool)
+			names[ext] = m
+		}
+		m[strings.ToLower(name)] = true
+	}
+	Traverse(func(t *Trait) bool {
+		add(TraitsExt, t.Name)
+		return false
+	}, false, true, entity.Traits...)
+	Traverse(func(s *Skill) bool {
+		add(SkillsExt, s.Name+"\n"+s.Specialization)
+		return false
+	}, false, true, entity.Skills...)
+	Traverse(func(s *Spell) bool {
+		add(SpellsExt, s.Name)
+		return false
+	}, false, true, entity.Spells...)
+	Traverse(func(e *Equipment) bool {
+		add(EquipmentExt, e.Name)
+		return false
+	}, false, true, append(append([]*Equipment{}, entity.CarriedEquipment...), entity.OtherEquipment...)...)
+	var files []*bundleFile
+	for _, lib := range GlobalSettings().Libraries().List() {
+		if lib.IsMaster() {
+			continue
+		}
+		fileSystem := os.DirFS(lib.Path())
+		_ = fs.WalkDir(fileSystem, ".", func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // Intentionally ignored the error result
+			if err != nil {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			ext := strings.ToLower(path.Ext(p))
+			if m, ok := names[ext]; ok && libraryFileUsesAny(fileSystem, p, ext, m) {
+				if data, readErr := fs.ReadFile(fileSystem, p); readErr != nil {
+					jot.Warn(errs.NewWithCause("unable to read "+p, readErr))
+				} else {
+					files = append(files, &bundleFile{
+						name: path.Join(bundleLibraryDir, p),
+						data: data,
+					})
+				}
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+func libraryFileUsesAny(fileSystem fs.FS, filePath, ext string, names map[string]bool) bool {
+	found := false
+	switch ext {
+	case TraitsExt:
+		if list, err := NewTraitsFromFile(fileSystem, filePath); err == nil {
+			Traverse(func(t *Trait) bool {
+				found = names[strings.ToLower(t.Name)]
+				return found
+			}, false, true, list...)
+		}
+	case SkillsExt:
+		if list, err := NewSkillsFromFile(fileSystem, filePath); err == nil {
+			Traverse(func(s *Skill) bool {
+				found = names[strings.ToLower(s.Name+"\n"+s.Specialization)]
+				return found
+			}, false, true, list...)
+		}
+	case SpellsExt:
+		if list, err := NewSpellsFromFile(fileSystem, filePath); err == nil {
+			Traverse(func(s *Spell) bool {
+				found = names[strings.ToLower(s.Name)]
+				return found
+			}, false, true, list...)
+		}
+	case EquipmentExt:
+		if list, err := NewEquipmentFromFile(fileSystem, filePath); err == nil {
+			Traverse(func(e *Equipment) bool {
+				found = names[strings.ToLower(e.Name)]
+				return found
+			}, false, true, list...)
+		}
+	}
+	return found
+}
+
+// ImportBundle extracts a character bundle into the library, returning the path to the extracted sheet. The resolve
+// function is called for each file that already exists in the library with different content to determine what should
+// be done with it.
+func ImportBundle(bundlePath string, lib *Library, resolve func(relPath string) BundleConflictAction) (string, error) {
+	zr, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return "", errs.NewWithCause("unable to open "+bundlePath, err)
+	}
+	defer func() {
+		if closeErr := zr.Close(); closeErr != nil {
+			jot.Warn(errs.Wrap(closeErr))
+		}
+	}()
+	var manifest bundleManifest
+	var data []byte
+	if data, err = readBundleFile(&zr.Reader, bundleManifestName); err != nil {
+		return "", err
+	}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return "", errs.NewWithCause(invalidFileDataMsg(), err)
+	}
+	if manifest.Type != bundleTypeKey {
+		return "", errs.New(unexpectedFileDataMsg())
+	}
+	if err = CheckVersion(manifest.Version); err != nil {
+		return "", err
+	}
+	root := filepath.Clean(lib.Path())
+	rootWithTrailingSep := root
+	if !strings.HasSuffix(rootWithTrailingSep, string(filepath.Separator)) {
+		rootWithTrailingSep += string(filepath.Separator)
+	}
+	sheetPath := ""
+	names := append([]string{}, manifest.Files...)
+	sort.Strings(names)
+	for _, name := range names {
+		relPath := name
+		if strings.HasPrefix(relPath, bundleLibraryDir+"/") {
+			relPath = strings.TrimPrefix(relPath, bundleLibraryDir+"/")
+		}
+		fullPath := filepath.Join(root, filepath.FromSlash(relPath))
+		if !strings.HasPrefix(fullPath, rootWithTrailingSep) {
+			return "", errs.Newf("path outside of root is not permitted: %s", fullPath)
+		}
+		if data, err = readBundleFile(&zr.Reader, name); err != nil {
+			return "", err
+		}
+		if existing, readErr := os.ReadFile(fullPath); readErr == nil {
+			if !bytes.Equal(existing, data) {
+				switch resolve(relPath) {
+				case ReplaceExistingBundleConflictAction:
+					if err = writeBundleFile(fullPath, data); err != nil {
+						return "", err
+					}
+				case KeepBothBundleConflictAction:
+					fullPath = uniqueBundleFilePath(fullPath)
+					if err = writeBundleFile(fullPath, data); err != nil {
+						return "", err
+					}
+				default:
+				}
+			}
+		} else if err = writeBundleFile(fullPath, data); err != nil {
+			return "", err
+		}
+		if name == manifest.Sheet {
+			sheetPath = fullPath
+		}
+	}
+	if sheetPath == "" {
+		return "", errs.New(invalidFileDataMsg())
+	}
+	return sheetPath, nil
+}
+
+func readBundleFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, errs.NewWithCause("unable to find "+name, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			jot.Warn(errs.Wrap(closeErr))
+		}
+	}()
+	var data []byte
+	if data, err = io.ReadAll(io.LimitReader(f, maxBundleFileSize+1)); err != nil {
+		return nil, errs.NewWithCause("unable to read "+name, err)
+	}
+	if len(data) > maxBundleFileSize {
+		return nil, errs.New("file too large: " + name)
+	}
+	return data, nil
+}
+
+func writeBundleFile(fullPath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o750); err != nil {
+		return errs.NewWithCause("unable to create "+filepath.Dir(fullPath), err)
+	}
+	if err := os.WriteFile(fullPath, data, 0o640); err != nil {
+		return errs.NewWithCause("unable to create "+fullPath, err)
+	}
+	return nil
+}
+
+func uniqueBundleFilePath(fullPath string) string {
+	ext := filepath.Ext(fullPath)
+	base := strings.TrimSuffix(fullPath, ext)
+	for i := 2; ; i++ {
+		p := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if !xfs.FileExists(p) {
+			return p
+		}
+	}
+}
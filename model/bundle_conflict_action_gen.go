@@ -0,0 +1,96 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	KeepExistingBundleConflictAction BundleConflictAction = iota
+	ReplaceExistingBundleConflictAction
+	KeepBothBundleConflictAction
+	LastBundleConflictAction = KeepBothBundleConflictAction
+)
+
+// AllBundleConflictAction holds all possible values.
+var AllBundleConflictAction = []BundleConflictAction{
+	KeepExistingBundleConflictAction,
+	ReplaceExistingBundleConflictAction,
+	KeepBothBundleConflictAction,
+}
+
+// BundleConflictAction holds the action to take when a file being imported from a bundle conflicts with an existing
+// file.
+type BundleConflictAction byte
+
+// EnsureValid ensures this is of a known value.
+func (enum BundleConflictAction) EnsureValid() BundleConflictAction {
+	if enum <= LastBundleConflictAction {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum BundleConflictAction) Key() string {
+	switch enum {
+	case KeepExistingBundleConflictAction:
+		return "keep_existing"
+	case ReplaceExistingBundleConflictAction:
+		return "replace_existing"
+	case KeepBothBundleConflictAction:
+		return "keep_both"
+	default:
+		return BundleConflictAction(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum BundleConflictAction) String() string {
+	switch enum {
+	case KeepExistingBundleConflictAction:
+		return i18n.Text("Keep Existing")
+	case ReplaceExistingBundleConflictAction:
+		return i18n.Text("Replace Existing")
+	case KeepBothBundleConflictAction:
+		return i18n.Text("Keep Both")
+	default:
+		return BundleConflictAction(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum BundleConflictAction) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *BundleConflictAction) UnmarshalText(text []byte) error {
+	*enum = ExtractBundleConflictAction(string(text))
+	return nil
+}
+
+// ExtractBundleConflictAction extracts the value from a string.
+func ExtractBundleConflictAction(str string) BundleConflictAction {
+	for _, enum := range AllBundleConflictAction {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
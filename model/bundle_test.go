@@ -0,0 +1,127 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	entity := model.NewEntity(model.PC)
+	entity.Profile.Name = "Bundled"
+	bundlePath := filepath.Join(dir, "Bundled"+model.BundleExt)
+	require.NoError(t, model.ExportBundle(entity, bundlePath))
+
+	lib := &model.Library{PathOnDisk: filepath.Join(dir, "library")}
+	sheetPath, err := model.ImportBundle(bundlePath, lib, func(relPath string) model.BundleConflictAction {
+		t.Errorf("unexpected conflict for %s", relPath)
+		return model.KeepExistingBundleConflictAction
+	})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(lib.PathOnDisk, "Bundled"+model.SheetExt), sheetPath)
+	imported, err := model.NewEntityFromFile(os.DirFS(filepath.Dir(sheetPath)), filepath.Base(sheetPath))
+	require.NoError(t, err)
+	assert.Equal(t, "Bundled", imported.Profile.Name)
+
+	// Importing the same bundle again finds identical files, so there is nothing to resolve
+	_, err = model.ImportBundle(bundlePath, lib, func(relPath string) model.BundleConflictAction {
+		t.Errorf("unexpected conflict for %s", relPath)
+		return model.KeepExistingBundleConflictAction
+	})
+	require.NoError(t, err)
+}
+
+func TestBundleConflictActions(t *testing.T) {
+	for _, action := range model.AllBundleConflictAction {
+		dir := t.TempDir()
+		bundlePath := filepath.Join(dir, "conflict"+model.BundleExt)
+		writeTestBundle(t, bundlePath, "Library/Traits/Test"+model.SheetExt, "new")
+		lib := &model.Library{PathOnDisk: filepath.Join(dir, "library")}
+		existingPath := filepath.Join(lib.PathOnDisk, "Traits", "Test"+model.SheetExt)
+		require.NoError(t, os.MkdirAll(filepath.Dir(existingPath), 0o750))
+		require.NoError(t, os.WriteFile(existingPath, []byte("old"), 0o640))
+		var conflicts []string
+		sheetPath, err := model.ImportBundle(bundlePath, lib, func(relPath string) model.BundleConflictAction {
+			conflicts = append(conflicts, relPath)
+			return action
+		})
+		require.NoError(t, err, action.String())
+		assert.Equal(t, []string{"Traits/Test" + model.SheetExt}, conflicts, action.String())
+		keepBothPath := filepath.Join(filepath.Dir(existingPath), "Test (2)"+model.SheetExt)
+		switch action {
+		case model.KeepExistingBundleConflictAction:
+			assert.Equal(t, existingPath, sheetPath)
+			assertFileContents(t, existingPath, "old")
+			assert.NoFileExists(t, keepBothPath)
+		case model.ReplaceExistingBundleConflictAction:
+			assert.Equal(t, existingPath, sheetPath)
+			assertFileContents(t, existingPath, "new")
+			assert.NoFileExists(t, keepBothPath)
+		case model.KeepBothBundleConflictAction:
+			assert.Equal(t, keepBothPath, sheetPath)
+			assertFileContents(t, existingPath, "old")
+			assertFileContents(t, keepBothPath, "new")
+		}
+	}
+}
+
+func TestBundleRejectsPathsOutsideLibrary(t *testing.T) {
+	dir := t.TempDir()
+	bundlePath := filepath.Join(dir, "escape"+model.BundleExt)
+	writeTestBundle(t, bundlePath, "Library/../../escaped"+model.SheetExt, "data")
+	lib := &model.Library{PathOnDisk: filepath.Join(dir, "nested", "library")}
+	_, err := model.ImportBundle(bundlePath, lib, func(_ string) model.BundleConflictAction {
+		return model.ReplaceExistingBundleConflictAction
+	})
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "escaped"+model.SheetExt))
+	assert.NoFileExists(t, filepath.Join(dir, "nested", "escaped"+model.SheetExt))
+}
+
+func writeTestBundle(t *testing.T, bundlePath, name, contents string) {
+	t.Helper()
+	f, err := os.Create(bundlePath)
+	require.NoError(t, err)
+	w := zip.NewWriter(f)
+	for _, one := range []struct{ name, data string }{
+		{name: name, data: contents},
+		{
+			name: "manifest.json",
+			data: fmt.Sprintf(`{"type":"character_bundle","version":%d,"sheet":%q,"files":[%q]}`,
+				model.CurrentDataVersion, name, name),
+		},
+	} {
+		fw, createErr := w.Create(one.name)
+		require.NoError(t, createErr)
+		_, err = fw.Write([]byte(one.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+}
+
+func assertFileContents(t *testing.T, filePath, expected string) {
+	t.Helper()
+	data, err := os.ReadFile(filePath)
+	if assert.NoError(t, err, filePath) {
+		assert.Equal(t, expected, string(data), filePath)
+	}
+}
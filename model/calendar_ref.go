@@ -62,7 +62,7 @@ func NewCalendarRefFromFS(fileSystem fs.FS, filePath string) (*CalendarRef, erro
 }
 
 // RandomBirthday generates a random birthday month and day.
-func (c *CalendarRef) RandomBirthday(not string) string {
+func (c *CalendarRef) RandomBirthday(rnd rand.Randomizer, not string) string {
 	year := 1
 	base := 0
 	if c.Calendar.LeapYear != nil {
@@ -74,7 +74,7 @@ func (c *CalendarRef) RandomBirthday(not string) string {
 	daysInYear := c.Calendar.Days(year)
 	result := ""
 	for i := 0; i < 5; i++ {
-		if result = c.Calendar.NewDateByDays(base + rand.NewCryptoRand().Intn(daysInYear)).Format("%M %D"); result != not {
+		if result = c.Calendar.NewDateByDays(base + rnd.Intn(daysInYear)).Format("%M %D"); result != not {
 			break
 		}
 	}
@@ -0,0 +1,206 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+)
+
+// Campaign holds a campaign profile, which bundles the settings, libraries and house rules to be used by the sheets
+// created for a particular campaign.
+type Campaign struct {
+	Name             string         `json:"name"`
+	HouseRules       string         `json:"house_rules,omitempty"`
+	SheetSettings    *SheetSettings `json:"sheet_settings,omitempty"`
+	Libraries        []string       `json:"libraries,omitempty"`
+	InitialPoints    fxp.Int        `json:"initial_points,omitempty"`
+	DefaultTechLevel string         `json:"default_tech_level,omitempty"`
+	ControlRating    int            `json:"control_rating,omitempty"`
+	CalendarName     string         `json:"calendar_ref,omitempty"`
+	PageRefs         PageRefs       `json:"page_refs,omitempty"`
+	Clock            *CampaignClock `json:"clock,omitempty"`
+}
+
+// NewCampaign creates a new, empty, campaign profile.
+func NewCampaign() *Campaign {
+	return &Campaign{}
+}
+
+// NewCampaignFromFile loads a campaign profile from a file.
+func NewCampaignFromFile(fileSystem fs.FS, filePath string) (*Campaign, error) {
+	var c Campaign
+	if err := jio.LoadFromFS(context.Background(), fileSystem, filePath, &c); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		c.Name = xfs.BaseName(filePath)
+	}
+	c.EnsureValidity()
+	return &c, nil
+}
+
+// Save writes the campaign profile to the file as JSON.
+func (c *Campaign) Save(filePath string) error {
+	return jio.SaveToFile(context.Background(), filePath, c)
+}
+
+// EnsureValidity checks the current settings for validity and if they aren't valid, makes them so.
+func (c *Campaign) EnsureValidity() {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.InitialPoints != 0 {
+		c.InitialPoints = fxp.ResetIfOutOfRange(c.InitialPoints, InitialPointsMin, InitialPointsMax, InitialPointsDef)
+	}
+	if c.ControlRating < ControlRatingMin || c.ControlRating > ControlRatingMax {
+		c.ControlRating = ControlRatingMin
+	}
+	if c.SheetSettings != nil {
+		c.SheetSettings.EnsureValidity()
+	}
+}
+
+// Active returns true if this campaign profile has been given a name and is therefore in use.
+func (c *Campaign) Active() bool {
+	return c != nil && c.Name != ""
+}
+
+// UsesLibrary returns true if the library is part of this campaign. A campaign that hasn't selected any libraries is
+// considered to use all of them.
+func (c *Campaign) UsesLibrary(lib *Library) bool {
+	if len(c.Libraries) == 0 {
+		return true
+	}
+	key := lib.Key()
+	for _, one := range c.Libraries {
+		if one == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SetUsesLibrary adds or removes the library from this campaign.
+func (c *Campaign) SetUsesLibrary(lib *Library, use bool) {
+	key := lib.Key()
+	list := make([]string, 0, len(c.Libraries)+1)
+	for _, one := range c.Libraries {
+		if one != key {
+			list = append(list, one)
+		}
+	}
+	if use {
+		list = append(list, key)
+	}
+	c.Libraries = list
+}
+
+// ApplyTo links the entity to this campaign, replacing its sheet settings with the campaign's, if it has any.
+func (c *Campaign) ApplyTo(entity *Entity) {
+	entity.Campaign = c.Name
+	if c.SheetSettings != nil {
+		entity.SheetSettings = c.SheetSettings.Clone(entity)
+		entity.SheetSettings.SetOwningEntity(entity)
+	}
+}
+
+// ActiveCampaign returns the active campaign profile, or nil if there isn't one.
+func (s *Settings) ActiveCampaign() *Campaign {
+	if s.Campaign.Active() {
+		return s.Campaign
+	}
+	return nil
+}
+
+// CampaignFor returns the active campaign profile if the entity is linked to it, or nil.
+func (s *Settings) CampaignFor(entity *Entity) *Campaign {
+	if c := s.ActiveCampaign(); c != nil && entity != nil && strings.EqualFold(c.Name, entity.Campaign) {
+		return c
+	}
+	return nil
+}
+
+// InitialPoints returns the initial points to use for new sheets.
+func (s *Settings) InitialPoints() fxp.Int {
+	if c := s.ActiveCampaign(); c != nil && c.InitialPoints != 0 {
+		return c.InitialPoints
+	}
+	return s.General.InitialPoints
+}
+
+// DefaultTechLevelFor returns the default tech level to use for the entity.
+func (s *Settings) DefaultTechLevelFor(entity *Entity) string {
+	if c := s.CampaignFor(entity); c != nil && c.DefaultTechLevel != "" {
+		return c.DefaultTechLevel
+	}
+	return s.General.DefaultTechLevel
+}
+
+// CalendarRefFor returns the CalendarRef to use for the entity.
+func (s *Settings) CalendarRefFor(entity *Entity) *CalendarRef {
+	if c := s.CampaignFor(entity); c != nil && c.CalendarName != "" {
+		if ref := LookupCalendarRef(c.CalendarName, s.LibrarySet); ref != nil {
+			return ref
+		}
+	}
+	return s.General.CalendarRef(s.LibrarySet)
+}
+
+// LookupPageRef returns the PageRef for the given ID, preferring the mappings of the active campaign, if any.
+func (s *Settings) LookupPageRef(id string) *PageRef {
+	if c := s.ActiveCampaign(); c != nil {
+		if ref := c.PageRefs.Lookup(id); ref != nil {
+			return ref
+		}
+	}
+	return s.PageRefs.Lookup(id)
+}
+
+// PageRefList returns a sorted list of the page references, with the readable mappings of the active campaign, if any,
+// taking the place of the global ones with the same ID.
+func (s *Settings) PageRefList() []*PageRef {
+	c := s.ActiveCampaign()
+	if c == nil {
+		return s.PageRefs.List()
+	}
+	var refs PageRefs
+	for _, one := range s.PageRefs.List() {
+		refs.Set(one)
+	}
+	for _, one := range c.PageRefs.List() {
+		if ref := c.PageRefs.Lookup(one.ID); ref != nil {
+			refs.Set(ref)
+		}
+	}
+	return refs.List()
+}
+
+// CampaignLibraries returns the libraries that the active campaign uses. If there is no active campaign, all libraries
+// are returned.
+func (s *Settings) CampaignLibraries() []*Library {
+	libs := s.LibrarySet.List()
+	c := s.ActiveCampaign()
+	if c == nil {
+		return libs
+	}
+	list := make([]*Library, 0, len(libs))
+	for _, lib := range libs {
+		if c.UsesLibrary(lib) {
+			list = append(list, lib)
+		}
+	}
+	return list
+}
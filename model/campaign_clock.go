@@ -0,0 +1,235 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/rpgtools/calendar"
+)
+
+// Units of in-game time, in minutes.
+const (
+	MinutesPerHour = 60
+	MinutesPerDay  = 24 * MinutesPerHour
+	MinutesPerWeek = 7 * MinutesPerDay
+)
+
+// CampaignClock tracks the in-game date and time of a campaign, along with a log of notable events.
+type CampaignClock struct {
+	Minutes int           `json:"minutes"`
+	Events  []*ClockEvent `json:"events,omitempty"`
+}
+
+// ClockEvent holds an entry in the campaign's event log.
+type ClockEvent struct {
+	When int    `json:"when"`
+	Text string `json:"text"`
+}
+
+// CampaignClock returns the clock of the current campaign, or nil if it isn't being used.
+func (s *Settings) CampaignClock() *CampaignClock {
+	if s.Campaign == nil {
+		return nil
+	}
+	return s.Campaign.Clock
+}
+
+// EnsureCampaignClock returns the clock of the current campaign, creating it if necessary.
+func (s *Settings) EnsureCampaignClock() *CampaignClock {
+	if s.Campaign == nil {
+		s.Campaign = NewCampaign()
+	}
+	if s.Campaign.Clock == nil {
+		s.Campaign.Clock = &CampaignClock{}
+	}
+	return s.Campaign.Clock
+}
+
+// CampaignCalendarRef returns the CalendarRef used by the current campaign.
+func (s *Settings) CampaignCalendarRef() *CalendarRef {
+	if c := s.ActiveCampaign(); c != nil && c.CalendarName != "" {
+		if ref := LookupCalendarRef(c.CalendarName, s.LibrarySet); ref != nil {
+			return ref
+		}
+	}
+	return s.General.CalendarRef(s.LibrarySet)
+}
+
+// CurrentGameDate returns the current in-game date in the short format used for dates entered into the entity's sheet,
+// or an empty string if the campaign clock isn't being used.
+func (s *Settings) CurrentGameDate(entity *Entity) string {
+	clock := s.CampaignClock()
+	if clock == nil {
+		return ""
+	}
+	return clock.Date(s.CalendarRefFor(entity).Calendar).String()
+}
+
+// Date returns the current in-game date.
+func (c *CampaignClock) Date(cal *calendar.Calendar) calendar.Date {
+	return cal.NewDateByDays(floorDiv(c.Minutes, MinutesPerDay))
+}
+
+// TimeOfDay returns the current in-game hour and minute.
+func (c *CampaignClock) TimeOfDay() (hour, minute int) {
+	m := c.Minutes - floorDiv(c.Minutes, MinutesPerDay)*MinutesPerDay
+	return m / MinutesPerHour, m % MinutesPerHour
+}
+
+// Format returns the in-game date and time.
+func (c *CampaignClock) Format(cal *calendar.Calendar) string {
+	return FormatGameTime(cal, c.Minutes)
+}
+
+// Advance the clock by the given number of minutes, which may be negative.
+func (c *CampaignClock) Advance(minutes int) {
+	c.Minutes += minutes
+}
+
+// SetDate changes the date, preserving the time of day.
+func (c *CampaignClock) SetDate(date calendar.Date) {
+	hour, minute := c.TimeOfDay()
+	c.Minutes = date.Days*MinutesPerDay + hour*MinutesPerHour + minute
+}
+
+// SetTimeOfDay changes the time of day, preserving the date.
+func (c *CampaignClock) SetTimeOfDay(hour, minute int) {
+	c.Minutes = floorDiv(c.Minutes, MinutesPerDay)*MinutesPerDay + hour*MinutesPerHour + minute
+}
+
+// Log adds an event at the current time.
+func (c *CampaignClock) Log(text string) *ClockEvent {
+	event := &ClockEvent{
+		When: c.Minutes,
+		Text: text,
+	}
+	c.Events = append(c.Events, event)
+	return event
+}
+
+// FormatGameTime returns the in-game date and time for the given number of minutes since the start of the calendar.
+func FormatGameTime(cal *calendar.Calendar, minutes int) string {
+	clock := CampaignClock{Minutes: minutes}
+	hour, minute := clock.TimeOfDay()
+	return fmt.Sprintf("%s %02d:%02d", clock.Date(cal).Format(calendar.FullFormat), hour, minute)
+}
+
+// ParseTimeOfDay parses text of the form "14:05" into an hour and minute.
+func ParseTimeOfDay(text string) (hour, minute int, ok bool) {
+	h, m, found := strings.Cut(strings.TrimSpace(text), ":")
+	if !found {
+		return 0, 0, false
+	}
+	var err error
+	if hour, err = strconv.Atoi(strings.TrimSpace(h)); err != nil || hour < 0 || hour > 23 {
+		return 0, 0, false
+	}
+	if minute, err = strconv.Atoi(strings.TrimSpace(m)); err != nil || minute < 0 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// ParseBirthday parses a birthday of the form produced by CalendarRef.RandomBirthday, i.e. "September 22", returning
+// the month (starting at 1) and day.
+func ParseBirthday(cal *calendar.Calendar, birthday string) (month, day int, ok bool) {
+	fields := strings.Fields(birthday)
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+	var err error
+	if day, err = strconv.Atoi(fields[len(fields)-1]); err != nil || day < 1 {
+		return 0, 0, false
+	}
+	name := strings.Join(fields[:len(fields)-1], " ")
+	for i, one := range cal.Months {
+		if strings.EqualFold(one.Name, name) {
+			return i + 1, day, true
+		}
+	}
+	for i, one := range cal.Months {
+		if len(name) >= 3 && len(one.Name) >= len(name) && strings.EqualFold(one.Name[:len(name)], name) {
+			return i + 1, day, true
+		}
+	}
+	return 0, 0, false
+}
+
+// BirthdayInYear returns the date of the birthday within the given year. Birthdays that fall on a day that doesn't
+// exist in that year, such as a leap day, are moved to the last day of the month.
+func BirthdayInYear(cal *calendar.Calendar, month, day, year int) calendar.Date {
+	maxDay := cal.Months[month-1].Days
+	if cal.IsLeapMonth(month) && cal.IsLeapYear(year) {
+		maxDay++
+	}
+	if day > maxDay {
+		day = maxDay
+	}
+	return cal.MustNewDate(month, day, year)
+}
+
+// BirthdaysBetween returns the number of birthdays that occur after fromDays and up to and including toDays. If toDays
+// is before fromDays, the result is the negated count of birthdays that were passed going backwards.
+func BirthdaysBetween(cal *calendar.Calendar, month, day, fromDays, toDays int) int {
+	if toDays < fromDays {
+		return -BirthdaysBetween(cal, month, day, toDays, fromDays)
+	}
+	count := 0
+	for year := cal.NewDateByDays(fromDays).Year(); year <= cal.NewDateByDays(toDays).Year(); year++ {
+		if year == 0 {
+			continue
+		}
+		if days := BirthdayInYear(cal, month, day, year).Days; days > fromDays && days <= toDays {
+			count++
+		}
+	}
+	return count
+}
+
+// NextBirthday returns the date of the first birthday on or after the given day.
+func NextBirthday(cal *calendar.Calendar, month, day, fromDays int) calendar.Date {
+	year := cal.NewDateByDays(fromDays).Year()
+	date := BirthdayInYear(cal, month, day, year)
+	if date.Days < fromDays {
+		if year++; year == 0 {
+			year++
+		}
+		date = BirthdayInYear(cal, month, day, year)
+	}
+	return date
+}
+
+// AgingRollInterval returns the number of months between aging rolls for a character of the given age, or 0 if no
+// aging rolls are required yet. See p. B444.
+func AgingRollInterval(age int) int {
+	switch {
+	case age >= 90:
+		return 3
+	case age >= 70:
+		return 6
+	case age >= 50:
+		return 12
+	default:
+		return 0
+	}
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
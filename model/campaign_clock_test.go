@@ -0,0 +1,57 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/rpgtools/calendar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCampaignClock(t *testing.T) {
+	cal := calendar.Gregorian()
+	clock := &model.CampaignClock{}
+	clock.SetDate(cal.MustNewDate(9, 20, 1200))
+	clock.SetTimeOfDay(23, 30)
+	assert.Equal(t, "9/20/1200", clock.Date(cal).String())
+	clock.Advance(45)
+	hour, minute := clock.TimeOfDay()
+	assert.Equal(t, 0, hour)
+	assert.Equal(t, 15, minute)
+	assert.Equal(t, "9/21/1200", clock.Date(cal).String())
+
+	month, day, ok := model.ParseBirthday(cal, "September 22")
+	assert.True(t, ok)
+	assert.Equal(t, 9, month)
+	assert.Equal(t, 22, day)
+	_, _, ok = model.ParseBirthday(cal, "Smarch 3")
+	assert.False(t, ok)
+
+	from := clock.Date(cal).Days
+	assert.Equal(t, 0, model.BirthdaysBetween(cal, month, day, from, from))
+	assert.Equal(t, 1, model.BirthdaysBetween(cal, month, day, from, from+1))
+	assert.Equal(t, 3, model.BirthdaysBetween(cal, month, day, from, cal.MustNewDate(9, 22, 1202).Days))
+	assert.Equal(t, -1, model.BirthdaysBetween(cal, month, day, from+1, from))
+	assert.Equal(t, cal.MustNewDate(9, 22, 1200).Days, model.NextBirthday(cal, month, day, from).Days)
+	assert.Equal(t, cal.MustNewDate(2, 28, 1201).Days, model.BirthdayInYear(cal, 2, 29, 1201).Days)
+
+	h, m, ok := model.ParseTimeOfDay("14:05")
+	assert.True(t, ok)
+	assert.Equal(t, 14, h)
+	assert.Equal(t, 5, m)
+	_, _, ok = model.ParseTimeOfDay("25:00")
+	assert.False(t, ok)
+	assert.Equal(t, 12, model.AgingRollInterval(50))
+	assert.Equal(t, 0, model.AgingRollInterval(49))
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio"
+)
+
+// CardField holds a single labeled value shown on a card.
+type CardField struct {
+	Label string
+	Value string
+}
+
+// Card holds the content of a single printable card.
+type Card struct {
+	Title   string
+	Kind    string
+	Fields  []CardField
+	Notes   string
+	PageRef string
+}
+
+// NewCard creates a card for the given data. Returns nil if the data is not something that can be placed on a card.
+func NewCard(data any) *Card {
+	switch item := data.(type) {
+	case *Spell:
+		return NewSpellCard(item)
+	case *Trait:
+		return NewTraitCard(item)
+	case *Weapon:
+		return NewWeaponCard(item)
+	case *Equipment:
+		return NewEquipmentCard(item)
+	default:
+		return nil
+	}
+}
+
+// NewCards creates cards for the data. Spell containers are replaced by the spells they contain, since they are just
+// used for grouping.
+func NewCards[T NodeTypes](data ...T) []*Card {
+	var cards []*Card
+	for _, one := range data {
+		if spell, ok := any(one).(*Spell); ok && spell.Container() {
+			Traverse(func(s *Spell) bool {
+				cards = append(cards, NewSpellCard(s))
+				return false
+			}, false, true, spell.Children...)
+			continue
+		}
+		if card := NewCard(one); card != nil {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// NewSpellCard creates a card for the spell.
+func NewSpellCard(s *Spell) *Card {
+	c := &Card{
+		Title:   s.String(),
+		Kind:    i18n.Text("Spell"),
+		Notes:   s.Notes(),
+		PageRef: s.PageRef,
+	}
+	if !s.Container() {
+		c.AddField(i18n.Text("Level"), s.CalculateLevel().LevelAsString(false))
+		c.AddField(i18n.Text("Class"), s.Class)
+		c.AddField(i18n.Text("College"), strings.Join(s.College, ", "))
+		c.AddField(i18n.Text("Cost"), s.CastingCost)
+		c.AddField(i18n.Text("Maintain"), s.MaintenanceCost)
+		c.AddField(i18n.Text("Time"), s.CastingTime)
+		c.AddField(i18n.Text("Duration"), s.Duration)
+		c.AddField(i18n.Text("Resist"), s.Resist)
+	}
+	return c
+}
+
+// NewTraitCard creates a card for the trait.
+func NewTraitCard(t *Trait) *Card {
+	c := &Card{
+		Title:   t.String(),
+		Kind:    i18n.Text("Trait"),
+		Notes:   t.SecondaryText(func(DisplayOption) bool { return true }),
+		PageRef: t.PageRef,
+	}
+	c.AddField(i18n.Text("Points"), t.AdjustedPoints().String())
+	if t.Switchable {
+		c.AddField(i18n.Text("Activation"), t.ActivationCost.String()+" "+t.ActivationPoolAttribute())
+	}
+	return c
+}
+
+// NewWeaponCard creates a card for the weapon.
+func NewWeaponCard(w *Weapon) *Card {
+	c := &Card{
+		Title: w.String(),
+		Kind:  w.Type.String(),
+		Notes: w.Notes(),
+	}
+	switch owner := w.Owner.(type) {
+	case *Trait:
+		c.PageRef = owner.PageRef
+	case *Equipment:
+		c.PageRef = owner.PageRef
+	case *Skill:
+		c.PageRef = owner.PageRef
+	case *Spell:
+		c.PageRef = owner.PageRef
+	}
+	var tooltip xio.ByteBuffer
+	c.AddField(i18n.Text("Usage"), w.Usage)
+	c.AddField(i18n.Text("Level"), w.SkillLevel(&tooltip).String())
+	c.AddField(i18n.Text("Damage"), w.Damage.ResolvedDamage(&tooltip))
+	c.AddField(i18n.Text("ST"), w.MinimumStrength)
+	if w.Type == MeleeWeaponType {
+		c.AddField(i18n.Text("Reach"), w.Reach)
+		c.AddField(i18n.Text("Parry"), w.ResolvedParry(&tooltip))
+		c.AddField(i18n.Text("Block"), w.ResolvedBlock(&tooltip))
+	} else {
+		c.AddField(i18n.Text("Accuracy"), w.Accuracy)
+		c.AddField(i18n.Text("Range"), w.ResolvedRange())
+		c.AddField(i18n.Text("Rate of Fire"), w.RateOfFire)
+		c.AddField(i18n.Text("Shots"), w.Shots)
+		c.AddField(i18n.Text("Bulk"), w.Bulk)
+		c.AddField(i18n.Text("Recoil"), w.Recoil)
+	}
+	return c
+}
+
+// NewEquipmentCard creates a card for the equipment.
+func NewEquipmentCard(e *Equipment) *Card {
+	c := &Card{
+		Title:   e.Description(),
+		Kind:    i18n.Text("Equipment"),
+		Notes:   e.SecondaryText(func(DisplayOption) bool { return true }),
+		PageRef: e.PageRef,
+	}
+	settings := SheetSettingsFor(e.Entity)
+	c.AddField(i18n.Text("Quantity"), e.Quantity.String())
+	c.AddField(i18n.Text("Cost"), settings.FormatCurrency(e.AdjustedValue()))
+	units := settings.DefaultWeightUnits
+	c.AddField(i18n.Text("Weight"), units.Format(e.AdjustedWeight(false, units)))
+	c.AddField(i18n.Text("TL"), e.TechLevel)
+	c.AddField(i18n.Text("LC"), e.DisplayLegalityClass())
+	if e.MaxUses > 0 {
+		c.AddField(i18n.Text("Uses"), strconv.Itoa(e.Uses)+"/"+strconv.Itoa(e.MaxUses))
+	}
+	return c
+}
+
+// AddField adds a field to the card, unless the value is empty.
+func (c *Card) AddField(label, value string) {
+	if value = strings.TrimSpace(value); value != "" && value != "-" {
+		c.Fields = append(c.Fields, CardField{Label: label, Value: value})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+// Dimensions returns the card dimensions.
+func (enum CardSize) Dimensions() (width, height PaperLength) {
+	switch enum {
+	case PokerCardSize:
+		return PaperLength{Length: 2.5, Units: InchPaperUnits}, PaperLength{Length: 3.5, Units: InchPaperUnits}
+	case BridgeCardSize:
+		return PaperLength{Length: 2.25, Units: InchPaperUnits}, PaperLength{Length: 3.5, Units: InchPaperUnits}
+	case TarotCardSize:
+		return PaperLength{Length: 2.75, Units: InchPaperUnits}, PaperLength{Length: 4.75, Units: InchPaperUnits}
+	case Index3x5CardSize:
+		return PaperLength{Length: 3, Units: InchPaperUnits}, PaperLength{Length: 5, Units: InchPaperUnits}
+	case Index4x6CardSize:
+		return PaperLength{Length: 4, Units: InchPaperUnits}, PaperLength{Length: 6, Units: InchPaperUnits}
+	default:
+		return PokerCardSize.Dimensions()
+	}
+}
+
+// Grid returns the number of columns and rows of cards of this size that fit within the printable area of the page. At
+// least one card will always be placed on a page.
+func (enum CardSize) Grid(page *PageSettings) (columns, rows int) {
+	pw, ph := page.Orientation.Dimensions(page.Size.Dimensions())
+	cw, ch := enum.Dimensions()
+	width := pw.Pixels() - (page.LeftMargin.Pixels() + page.RightMargin.Pixels())
+	height := ph.Pixels() - (page.TopMargin.Pixels() + page.BottomMargin.Pixels())
+	columns = int(width / cw.Pixels())
+	if columns < 1 {
+		columns = 1
+	}
+	rows = int(height / ch.Pixels())
+	if rows < 1 {
+		rows = 1
+	}
+	return columns, rows
+}
@@ -0,0 +1,107 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	PokerCardSize CardSize = iota
+	BridgeCardSize
+	TarotCardSize
+	Index3x5CardSize
+	Index4x6CardSize
+	LastCardSize = Index4x6CardSize
+)
+
+// AllCardSize holds all possible values.
+var AllCardSize = []CardSize{
+	PokerCardSize,
+	BridgeCardSize,
+	TarotCardSize,
+	Index3x5CardSize,
+	Index4x6CardSize,
+}
+
+// CardSize holds a standard card dimension.
+type CardSize byte
+
+// EnsureValid ensures this is of a known value.
+func (enum CardSize) EnsureValid() CardSize {
+	if enum <= LastCardSize {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum CardSize) Key() string {
+	switch enum {
+	case PokerCardSize:
+		return "poker"
+	case BridgeCardSize:
+		return "bridge"
+	case TarotCardSize:
+		return "tarot"
+	case Index3x5CardSize:
+		return "index_3x5"
+	case Index4x6CardSize:
+		return "index_4x6"
+	default:
+		return CardSize(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum CardSize) String() string {
+	switch enum {
+	case PokerCardSize:
+		return i18n.Text("Poker (2.5 x 3.5 in)")
+	case BridgeCardSize:
+		return i18n.Text("Bridge (2.25 x 3.5 in)")
+	case TarotCardSize:
+		return i18n.Text("Tarot (2.75 x 4.75 in)")
+	case Index3x5CardSize:
+		return i18n.Text("Index (3 x 5 in)")
+	case Index4x6CardSize:
+		return i18n.Text("Index (4 x 6 in)")
+	default:
+		return CardSize(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum CardSize) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *CardSize) UnmarshalText(text []byte) error {
+	*enum = ExtractCardSize(string(text))
+	return nil
+}
+
+// ExtractCardSize extracts the value from a string.
+func ExtractCardSize(str string) CardSize {
+	for _, enum := range AllCardSize {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
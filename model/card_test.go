@@ -0,0 +1,75 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardGrid(t *testing.T) {
+	page := model.NewPageSettings()
+	columns, rows := model.PokerCardSize.Grid(page)
+	assert.Equal(t, 3, columns)
+	assert.Equal(t, 3, rows)
+	columns, rows = model.Index4x6CardSize.Grid(page)
+	assert.Equal(t, 2, columns)
+	assert.Equal(t, 1, rows)
+	page.Orientation = model.Landscape
+	columns, rows = model.PokerCardSize.Grid(page)
+	assert.Equal(t, 4, columns)
+	assert.Equal(t, 2, rows)
+	page.Size = model.A6PaperSize
+	columns, rows = model.Index4x6CardSize.Grid(page)
+	assert.Equal(t, 1, columns)
+	assert.Equal(t, 1, rows)
+}
+
+func TestCards(t *testing.T) {
+	entity := model.NewEntity(model.PC)
+	container := model.NewSpell(entity, nil, true)
+	spell := model.NewSpell(entity, container, false)
+	spell.Name = "Fireball"
+	spell.CastingCost = "1 to 3"
+	spell.Duration = "Instant"
+	spell.Resist = "-"
+	spell.PageRef = "B247"
+	container.Children = append(container.Children, spell)
+	entity.Spells = append(entity.Spells, container)
+	entity.Recalculate()
+
+	cards := model.NewCards(container)
+	assert.Len(t, cards, 1)
+	card := cards[0]
+	assert.Equal(t, "Fireball", card.Title)
+	assert.Equal(t, "B247", card.PageRef)
+	values := make(map[string]string)
+	for _, field := range card.Fields {
+		values[field.Label] = field.Value
+	}
+	assert.Equal(t, "1 to 3", values["Cost"])
+	assert.Equal(t, "Instant", values["Duration"])
+	assert.NotContains(t, values, "Resist")
+	assert.NotContains(t, values, "Maintain")
+
+	eqp := model.NewEquipment(entity, nil, false)
+	eqp.Name = "Rope"
+	eqp.MaxUses = 3
+	eqp.Uses = 2
+	card = model.NewCard(eqp)
+	assert.NotNil(t, card)
+	assert.Equal(t, "Rope", card.Title)
+	assert.Contains(t, card.Fields, model.CardField{Label: "Uses", Value: "2/3"})
+	assert.Nil(t, model.NewCard(model.NewNote(entity, nil, false)))
+}
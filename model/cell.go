@@ -27,6 +27,8 @@ type CellData struct {
 	Secondary         string
 	Tooltip           string
 	UnsatisfiedReason string
+	Warning           string
+	ComplianceWarning string
 	TemplateInfo      string
 }
 
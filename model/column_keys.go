@@ -0,0 +1,114 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+var (
+	conditionalModifierColumnKeys = map[int]string{
+		ConditionalModifierValueColumn:       "value",
+		ConditionalModifierDescriptionColumn: "description",
+	}
+	weaponColumnKeys = map[int]string{
+		WeaponDescriptionColumn: "description",
+		WeaponUsageColumn:       "usage",
+		WeaponSLColumn:          "level",
+		WeaponParryColumn:       "parry",
+		WeaponBlockColumn:       "block",
+		WeaponDamageColumn:      "damage",
+		WeaponReachColumn:       "reach",
+		WeaponSTColumn:          "strength",
+		WeaponAccColumn:         "accuracy",
+		WeaponRangeColumn:       "range",
+		WeaponRoFColumn:         "rate_of_fire",
+		WeaponShotsColumn:       "shots",
+		WeaponBulkColumn:        "bulk",
+		WeaponRecoilColumn:      "recoil",
+	}
+	traitColumnKeys = map[int]string{
+		TraitDescriptionColumn: "description",
+		TraitPointsColumn:      "points",
+		TraitTagsColumn:        "tags",
+		TraitReferenceColumn:   "reference",
+		TraitActiveColumn:      "active",
+	}
+	skillColumnKeys = map[int]string{
+		SkillDescriptionColumn:   "description",
+		SkillDifficultyColumn:    "difficulty",
+		SkillTagsColumn:          "tags",
+		SkillReferenceColumn:     "reference",
+		SkillLevelColumn:         "level",
+		SkillRelativeLevelColumn: "relative_level",
+		SkillPointsColumn:        "points",
+	}
+	spellColumnKeys = map[int]string{
+		SpellDescriptionColumn:        "description",
+		SpellResistColumn:             "resist",
+		SpellClassColumn:              "class",
+		SpellCollegeColumn:            "college",
+		SpellCastCostColumn:           "casting_cost",
+		SpellMaintainCostColumn:       "maintenance_cost",
+		SpellCastTimeColumn:           "casting_time",
+		SpellDurationColumn:           "duration",
+		SpellDifficultyColumn:         "difficulty",
+		SpellTagsColumn:               "tags",
+		SpellReferenceColumn:          "reference",
+		SpellLevelColumn:              "level",
+		SpellRelativeLevelColumn:      "relative_level",
+		SpellPointsColumn:             "points",
+		SpellDescriptionForPageColumn: "page_description",
+	}
+	equipmentColumnKeys = map[int]string{
+		EquipmentEquippedColumn:       "equipped",
+		EquipmentQuantityColumn:       "quantity",
+		EquipmentDescriptionColumn:    "description",
+		EquipmentUsesColumn:           "uses",
+		EquipmentMaxUsesColumn:        "max_uses",
+		EquipmentTLColumn:             "tech_level",
+		EquipmentLCColumn:             "legality_class",
+		EquipmentCostColumn:           "cost",
+		EquipmentExtendedCostColumn:   "extended_cost",
+		EquipmentWeightColumn:         "weight",
+		EquipmentExtendedWeightColumn: "extended_weight",
+		EquipmentTagsColumn:           "tags",
+		EquipmentReferenceColumn:      "reference",
+	}
+	noteColumnKeys = map[int]string{
+		NoteTextColumn:      "text",
+		NoteReferenceColumn: "reference",
+	}
+)
+
+func columnKeysForBlock(blockKey string) map[int]string {
+	switch blockKey {
+	case BlockLayoutReactionsKey, BlockLayoutConditionalModifiersKey:
+		return conditionalModifierColumnKeys
+	case BlockLayoutMeleeKey, BlockLayoutRangedKey:
+		return weaponColumnKeys
+	case BlockLayoutTraitsKey:
+		return traitColumnKeys
+	case BlockLayoutSkillsKey:
+		return skillColumnKeys
+	case BlockLayoutSpellsKey:
+		return spellColumnKeys
+	case BlockLayoutEquipmentKey, BlockLayoutOtherEquipmentKey:
+		return equipmentColumnKeys
+	case BlockLayoutNotesKey:
+		return noteColumnKeys
+	default:
+		return nil
+	}
+}
+
+// ColumnKey returns the stable key used to persist the column with the given ID within the block with the given key.
+// Returns an empty string if the column is unknown.
+func ColumnKey(blockKey string, columnID int) string {
+	return columnKeysForBlock(blockKey)[columnID]
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/richardwilkes/toolbox/txt"
+)
+
+const maxRecentCommands = 10
+
+// FuzzyMatch returns a score for how well the pattern matches the text and true if every character of the pattern
+// appears, in order, within the text. Matching is case-insensitive. Higher scores indicate better matches, with
+// consecutive characters and characters at the start of words scoring better.
+func FuzzyMatch(pattern, text string) (score int, matched bool) {
+	p := []rune(strings.ToLower(strings.TrimSpace(pattern)))
+	if len(p) == 0 {
+		return 0, true
+	}
+	t := []rune(text)
+	pi := 0
+	last := -2
+	for i, r := range t {
+		if pi == len(p) {
+			break
+		}
+		if unicode.ToLower(r) != p[pi] {
+			continue
+		}
+		score++
+		if last == i-1 {
+			score += 4
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 3
+		} else if unicode.IsUpper(r) && unicode.IsLower(t[i-1]) {
+			score += 2
+		}
+		last = i
+		pi++
+	}
+	if pi != len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// RankCommands filters the bindings to those whose action title or ID fuzzy matches the query and sorts them with the
+// best matches first. Ties are broken by how recently the command was used, then by title. The recent list should
+// contain binding IDs, most recent first.
+func RankCommands(bindings []*Binding, query string, recent []string) []*Binding {
+	type ranked struct {
+		binding *Binding
+		score   int
+		recency int
+	}
+	list := make([]ranked, 0, len(bindings))
+	for _, b := range bindings {
+		score, matched := FuzzyMatch(query, b.Action.Title)
+		if idScore, idMatched := FuzzyMatch(query, b.ID); idMatched && (!matched || idScore > score) {
+			score = idScore
+			matched = true
+		}
+		if !matched {
+			continue
+		}
+		recency := len(recent)
+		for i, id := range recent {
+			if id == b.ID {
+				recency = i
+				break
+			}
+		}
+		list = append(list, ranked{binding: b, score: score, recency: recency})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].score != list[j].score {
+			return list[i].score > list[j].score
+		}
+		if list[i].recency != list[j].recency {
+			return list[i].recency < list[j].recency
+		}
+		return txt.NaturalLess(list[i].binding.Action.Title, list[j].binding.Action.Title, true)
+	})
+	result := make([]*Binding, len(list))
+	for i, one := range list {
+		result[i] = one.binding
+	}
+	return result
+}
+
+// AddRecentCommand records the binding ID as the most recently used command.
+func (s *Settings) AddRecentCommand(id string) {
+	list := make([]string, 0, len(s.RecentCommands)+1)
+	list = append(list, id)
+	for _, one := range s.RecentCommands {
+		if one != id {
+			list = append(list, one)
+		}
+	}
+	if len(list) > maxRecentCommands {
+		list = list[:maxRecentCommands]
+	}
+	s.RecentCommands = list
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/unison"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	_, matched := model.FuzzyMatch("", "anything")
+	assert.True(t, matched)
+	_, matched = model.FuzzyMatch("xyz", "New Character Sheet")
+	assert.False(t, matched)
+	_, matched = model.FuzzyMatch("shc", "New Character Sheet")
+	assert.False(t, matched)
+	wordStarts, matched := model.FuzzyMatch("ncs", "New Character Sheet")
+	assert.True(t, matched)
+	scattered, matched := model.FuzzyMatch("ncs", "Convert to Non-Container")
+	assert.True(t, matched)
+	assert.Greater(t, wordStarts, scattered)
+	consecutive, _ := model.FuzzyMatch("sheet", "New Character Sheet")
+	spread, _ := model.FuzzyMatch("sheet", "Show the Embedded Equipment Table")
+	assert.Greater(t, consecutive, spread)
+}
+
+func TestRankCommands(t *testing.T) {
+	newBinding := func(id, title string) *model.Binding {
+		return &model.Binding{ID: id, Action: &unison.Action{Title: title}}
+	}
+	sheet := newBinding("new.sheet", "New Character Sheet")
+	template := newBinding("new.template", "New Character Template")
+	save := newBinding("save", "Save")
+	bindings := []*model.Binding{save, template, sheet}
+
+	assert.Equal(t, []*model.Binding{sheet, template, save}, model.RankCommands(bindings, "", nil))
+	assert.Equal(t, []*model.Binding{save, sheet, template}, model.RankCommands(bindings, "", []string{"save"}))
+	assert.Equal(t, []*model.Binding{template}, model.RankCommands(bindings, "tmpl", nil))
+	assert.Equal(t, []*model.Binding{template, sheet}, model.RankCommands(bindings, "new.", []string{"new.template"}))
+
+	var s model.Settings
+	for _, id := range []string{"a", "b", "c", "a"} {
+		s.AddRecentCommand(id)
+	}
+	assert.Equal(t, []string{"a", "c", "b"}, s.RecentCommands)
+	for i := 0; i < 20; i++ {
+		s.AddRecentCommand(string(rune('d' + i)))
+	}
+	assert.Len(t, s.RecentCommands, 10)
+}
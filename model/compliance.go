@@ -0,0 +1,163 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Control rating limits. A control rating of 0 disables legality checks, since nothing is illegal at CR0.
+const (
+	ControlRatingMin = 0
+	ControlRatingMax = 6
+)
+
+// ComplianceIssue describes a way in which a row doesn't comply with its character's tech level or the campaign's
+// control rating.
+type ComplianceIssue struct {
+	Kind   ComplianceIssueKind
+	Name   string
+	Detail string
+}
+
+// ExtractLegalityClass extracts the first digit it finds in the string and returns that as the legality class. Returns
+// false if the string didn't contain a digit in the range 0 to 4.
+func ExtractLegalityClass(str string) (lc int, ok bool) {
+	for _, ch := range str {
+		if ch >= '0' && ch <= '9' {
+			if ch > '4' {
+				return 0, false
+			}
+			return int(ch - '0'), true
+		}
+	}
+	return 0, false
+}
+
+// ControlRatingFor returns the control rating that applies to the entity, or 0 if there isn't one.
+func (s *Settings) ControlRatingFor(entity *Entity) int {
+	if c := s.CampaignFor(entity); c != nil {
+		return c.ControlRating
+	}
+	return 0
+}
+
+func entityTechLevel(entity *Entity) (tl fxp.Int, ok bool) {
+	if entity == nil {
+		return 0, false
+	}
+	tl, start, _ := ExtractTechLevel(entity.Profile.TechLevel)
+	return tl, start != -1
+}
+
+// ComplianceIssues returns the ways in which this equipment doesn't comply with its character's tech level or the
+// campaign's control rating.
+func (e *Equipment) ComplianceIssues() []*ComplianceIssue {
+	if e.Entity == nil {
+		return nil
+	}
+	var issues []*ComplianceIssue
+	if charTL, ok := entityTechLevel(e.Entity); ok {
+		if tl, start, _ := ExtractTechLevel(e.TechLevel); start != -1 && tl > charTL {
+			issues = append(issues, &ComplianceIssue{
+				Kind:   EquipmentTechLevelComplianceIssueKind,
+				Name:   e.String(),
+				Detail: fmt.Sprintf(i18n.Text("TL%s exceeds the character's TL%s"), e.TechLevel, e.Entity.Profile.TechLevel),
+			})
+		}
+	}
+	if cr := GlobalSettings().ControlRatingFor(e.Entity); cr > ControlRatingMin {
+		if lc, ok := ExtractLegalityClass(e.LegalityClass); ok && lc < cr {
+			issues = append(issues, &ComplianceIssue{
+				Kind:   IllegalComplianceIssueKind,
+				Name:   e.String(),
+				Detail: fmt.Sprintf(i18n.Text("LC%d is illegal under CR%d"), lc, cr),
+			})
+		}
+	}
+	return issues
+}
+
+// ComplianceIssues returns the ways in which this skill doesn't comply with its character's tech level.
+func (s *Skill) ComplianceIssues() []*ComplianceIssue {
+	if s.Container() {
+		return nil
+	}
+	return techLevelMismatch(s.Entity, s.String(), s.TechLevel)
+}
+
+// ComplianceIssues returns the ways in which this spell doesn't comply with its character's tech level.
+func (s *Spell) ComplianceIssues() []*ComplianceIssue {
+	if s.Container() {
+		return nil
+	}
+	return techLevelMismatch(s.Entity, s.String(), s.TechLevel)
+}
+
+func techLevelMismatch(entity *Entity, name string, techLevel *string) []*ComplianceIssue {
+	if techLevel == nil {
+		return nil
+	}
+	charTL, ok := entityTechLevel(entity)
+	if !ok {
+		return nil
+	}
+	if tl, start, _ := ExtractTechLevel(*techLevel); start != -1 && tl != charTL {
+		return []*ComplianceIssue{
+			{
+				Kind:   TechLevelMismatchComplianceIssueKind,
+				Name:   name,
+				Detail: fmt.Sprintf(i18n.Text("TL%s differs from the character's TL%s"), *techLevel, entity.Profile.TechLevel),
+			},
+		}
+	}
+	return nil
+}
+
+// ComplianceWarning returns a description of the issues, or an empty string if there are none.
+func ComplianceWarning(issues []*ComplianceIssue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	var buffer strings.Builder
+	buffer.WriteString(i18n.Text("Noncompliant:"))
+	for _, one := range issues {
+		buffer.WriteString("\n● ")
+		buffer.WriteString(one.Detail)
+	}
+	return buffer.String()
+}
+
+// ComplianceIssues returns all of the ways in which the character's equipment, skills and spells don't comply with
+// the character's tech level or the campaign's control rating.
+func (e *Entity) ComplianceIssues() []*ComplianceIssue {
+	var issues []*ComplianceIssue
+	equipmentFunc := func(eqp *Equipment) bool {
+		issues = append(issues, eqp.ComplianceIssues()...)
+		return false
+	}
+	Traverse(equipmentFunc, false, false, e.CarriedEquipment...)
+	Traverse(equipmentFunc, false, false, e.OtherEquipment...)
+	Traverse(func(s *Skill) bool {
+		issues = append(issues, s.ComplianceIssues()...)
+		return false
+	}, false, true, e.Skills...)
+	Traverse(func(s *Spell) bool {
+		issues = append(issues, s.ComplianceIssues()...)
+		return false
+	}, false, true, e.Spells...)
+	return issues
+}
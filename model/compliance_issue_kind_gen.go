@@ -0,0 +1,95 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	EquipmentTechLevelComplianceIssueKind ComplianceIssueKind = iota
+	TechLevelMismatchComplianceIssueKind
+	IllegalComplianceIssueKind
+	LastComplianceIssueKind = IllegalComplianceIssueKind
+)
+
+// AllComplianceIssueKind holds all possible values.
+var AllComplianceIssueKind = []ComplianceIssueKind{
+	EquipmentTechLevelComplianceIssueKind,
+	TechLevelMismatchComplianceIssueKind,
+	IllegalComplianceIssueKind,
+}
+
+// ComplianceIssueKind holds the kind of problem found by a tech level and legality check.
+type ComplianceIssueKind byte
+
+// EnsureValid ensures this is of a known value.
+func (enum ComplianceIssueKind) EnsureValid() ComplianceIssueKind {
+	if enum <= LastComplianceIssueKind {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum ComplianceIssueKind) Key() string {
+	switch enum {
+	case EquipmentTechLevelComplianceIssueKind:
+		return "equipment_tech_level"
+	case TechLevelMismatchComplianceIssueKind:
+		return "tech_level_mismatch"
+	case IllegalComplianceIssueKind:
+		return "illegal"
+	default:
+		return ComplianceIssueKind(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum ComplianceIssueKind) String() string {
+	switch enum {
+	case EquipmentTechLevelComplianceIssueKind:
+		return i18n.Text("Equipment Above Tech Level")
+	case TechLevelMismatchComplianceIssueKind:
+		return i18n.Text("Tech Level Mismatch")
+	case IllegalComplianceIssueKind:
+		return i18n.Text("Illegal Under Control Rating")
+	default:
+		return ComplianceIssueKind(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum ComplianceIssueKind) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *ComplianceIssueKind) UnmarshalText(text []byte) error {
+	*enum = ExtractComplianceIssueKind(string(text))
+	return nil
+}
+
+// ExtractComplianceIssueKind extracts the value from a string.
+func ExtractComplianceIssueKind(str string) ComplianceIssueKind {
+	for _, enum := range AllComplianceIssueKind {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
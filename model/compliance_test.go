@@ -0,0 +1,58 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompliance(t *testing.T) {
+	lc, ok := model.ExtractLegalityClass("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3, lc)
+	lc, ok = model.ExtractLegalityClass("LC2 (1 for ammo)")
+	assert.True(t, ok)
+	assert.Equal(t, 2, lc)
+	_, ok = model.ExtractLegalityClass("")
+	assert.False(t, ok)
+	_, ok = model.ExtractLegalityClass("7")
+	assert.False(t, ok)
+
+	entity := model.NewEntity(model.PC)
+	entity.Profile.TechLevel = "3"
+	sword := model.NewEquipment(entity, nil, false)
+	sword.TechLevel = "2"
+	rifle := model.NewEquipment(entity, nil, false)
+	rifle.TechLevel = "7"
+	entity.CarriedEquipment = []*model.Equipment{sword, rifle}
+	tlSkill := "4"
+	skill := model.NewSkill(entity, nil, false)
+	skill.TechLevel = &tlSkill
+	plainSkill := model.NewSkill(entity, nil, false)
+	entity.Skills = []*model.Skill{skill, plainSkill}
+
+	assert.Empty(t, sword.ComplianceIssues())
+	assert.Empty(t, plainSkill.ComplianceIssues())
+	issues := entity.ComplianceIssues()
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, model.EquipmentTechLevelComplianceIssueKind, issues[0].Kind)
+		assert.Equal(t, model.TechLevelMismatchComplianceIssueKind, issues[1].Kind)
+	}
+	assert.NotEmpty(t, model.ComplianceWarning(issues))
+	assert.Empty(t, model.ComplianceWarning(nil))
+
+	entity.Profile.TechLevel = ""
+	assert.Empty(t, entity.ComplianceIssues())
+}
@@ -19,7 +19,9 @@ import (
 
 var _ Feature = &ContainedWeightReduction{}
 
-// ContainedWeightReduction holds the data for a weight reduction that can be applied to a container's contents.
+// ContainedWeightReduction holds the data for a weight reduction that can be applied to a container's contents. Unlike
+// the other features, it does not support an activation condition, since container weights are computed without an
+// entity and feed into the encumbrance level that conditions may themselves depend upon.
 type ContainedWeightReduction struct {
 	Type      FeatureType `json:"type"`
 	Reduction string      `json:"reduction"`
@@ -100,6 +100,14 @@ func Convert(paths ...string) error {
 			if err = SaveNotes(data, p); err != nil {
 				return err
 			}
+		case RandomTableExt:
+			var table *RandomTable
+			if table, err = NewRandomTableFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
+				return err
+			}
+			if err = table.Save(p); err != nil {
+				return err
+			}
 		case TemplatesExt:
 			var tmpl *Template
 			if tmpl, err = NewTemplateFromFile(os.DirFS(filepath.Dir(p)), filepath.Base(p)); err != nil {
@@ -22,6 +22,7 @@ type CostReduction struct {
 	Type       FeatureType `json:"type"`
 	Attribute  string      `json:"attribute,omitempty"`
 	Percentage fxp.Int     `json:"percentage,omitempty"`
+	FeatureConditionHolder
 }
 
 // NewCostReduction creates a new CostReduction.
@@ -0,0 +1,183 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// Currency holds the definition of a unit of money.
+type Currency struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name,omitempty"`
+	Symbol      string  `json:"symbol,omitempty"`
+	Rate        fxp.Int `json:"rate"`
+	CoinWeight  Weight  `json:"coin_weight,omitempty"`
+	SymbolAfter bool    `json:"symbol_after,omitempty"`
+}
+
+// Clone creates a copy of this.
+func (c *Currency) Clone() *Currency {
+	clone := *c
+	return &clone
+}
+
+// String implements fmt.Stringer.
+func (c *Currency) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.ID
+}
+
+// Format the value using this currency's symbol.
+func (c *Currency) Format(value fxp.Int) string {
+	if c.Symbol == "" {
+		return value.String()
+	}
+	if c.SymbolAfter {
+		return value.String() + " " + c.Symbol
+	}
+	return c.Symbol + value.String()
+}
+
+// CloneCurrencies creates a copy of the currency list.
+func CloneCurrencies(in []*Currency) []*Currency {
+	if len(in) == 0 {
+		return nil
+	}
+	list := make([]*Currency, len(in))
+	for i, one := range in {
+		list[i] = one.Clone()
+	}
+	return list
+}
+
+// CurrencyFor returns the currency with the given ID, or nil.
+func (s *SheetSettings) CurrencyFor(id string) *Currency {
+	for _, one := range s.Currencies {
+		if strings.EqualFold(one.ID, id) {
+			return one
+		}
+	}
+	return nil
+}
+
+// DefaultCurrencyDef returns the currency that values are totaled in, or nil if no currencies have been defined.
+func (s *SheetSettings) DefaultCurrencyDef() *Currency {
+	if c := s.CurrencyFor(s.DefaultCurrency); c != nil {
+		return c
+	}
+	if len(s.Currencies) != 0 {
+		return s.Currencies[0]
+	}
+	return nil
+}
+
+// ConvertCurrency converts a value expressed in the currency with the given ID into the default currency. Values in an
+// unknown currency are presumed to already be in the default currency.
+func (s *SheetSettings) ConvertCurrency(value fxp.Int, fromID string) fxp.Int {
+	if value == 0 || fromID == "" {
+		return value
+	}
+	from := s.CurrencyFor(fromID)
+	to := s.DefaultCurrencyDef()
+	if from == nil || to == nil || from == to || from.Rate <= 0 || to.Rate <= 0 {
+		return value
+	}
+	return value.Mul(from.Rate).Div(to.Rate)
+}
+
+// CoinValue returns the value of the given coins, converted into the default currency.
+func (s *SheetSettings) CoinValue(coins map[string]fxp.Int) fxp.Int {
+	var total fxp.Int
+	for id, amount := range coins {
+		total += s.ConvertCurrency(amount, id)
+	}
+	return total
+}
+
+// CoinWeight returns the weight of the given coins.
+func (s *SheetSettings) CoinWeight(coins map[string]fxp.Int) Weight {
+	var total fxp.Int
+	for id, amount := range coins {
+		if c := s.CurrencyFor(id); c != nil && amount > 0 {
+			total += fxp.Int(c.CoinWeight).Mul(amount)
+		}
+	}
+	return Weight(total)
+}
+
+// FormatCurrency formats a value expressed in the default currency.
+func (s *SheetSettings) FormatCurrency(value fxp.Int) string {
+	if c := s.DefaultCurrencyDef(); c != nil {
+		return c.Format(value)
+	}
+	return "$" + value.String()
+}
+
+// UsesCurrency returns true if any of the entity's equipment or equipment modifiers refer to the currency.
+func (e *Entity) UsesCurrency(id string) bool {
+	found := false
+	Traverse(func(eqp *Equipment) bool {
+		if strings.EqualFold(eqp.Currency, id) {
+			found = true
+		} else {
+			for coinID := range eqp.Coins {
+				if strings.EqualFold(coinID, id) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			Traverse(func(mod *EquipmentModifier) bool {
+				found = strings.EqualFold(mod.CostCurrency, id)
+				return found
+			}, false, true, eqp.Modifiers...)
+		}
+		return found
+	}, false, false, e.allEquipment()...)
+	return found
+}
+
+// RenameCurrency updates the references the entity's equipment and equipment modifiers hold to a currency whose ID has
+// changed.
+func (e *Entity) RenameCurrency(oldID, newID string) {
+	if strings.EqualFold(oldID, newID) {
+		return
+	}
+	Traverse(func(eqp *Equipment) bool {
+		if strings.EqualFold(eqp.Currency, oldID) {
+			eqp.Currency = newID
+		}
+		for coinID, amount := range eqp.Coins {
+			if strings.EqualFold(coinID, oldID) {
+				delete(eqp.Coins, coinID)
+				eqp.Coins[newID] += amount
+			}
+		}
+		Traverse(func(mod *EquipmentModifier) bool {
+			if strings.EqualFold(mod.CostCurrency, oldID) {
+				mod.CostCurrency = newID
+			}
+			return false
+		}, false, true, eqp.Modifiers...)
+		return false
+	}, false, false, e.allEquipment()...)
+}
+
+func (e *Entity) allEquipment() []*Equipment {
+	return append(append([]*Equipment{}, e.CarriedEquipment...), e.OtherEquipment...)
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/txt"
+)
+
+// CustomBlockKeyPrefix is the prefix used for the block layout keys of custom blocks.
+const CustomBlockKeyPrefix = "custom_"
+
+// CustomBlock holds a user-defined block for the sheet.
+type CustomBlock struct {
+	ID         string          `json:"id"`
+	Title      string          `json:"title,omitempty"`
+	Type       CustomBlockType `json:"type"`
+	Attributes []string        `json:"attributes,omitempty"`
+	Filter     string          `json:"filter,omitempty"`
+}
+
+// IsCustomBlockKey returns true if the block layout key refers to a custom block.
+func IsCustomBlockKey(key string) bool {
+	return len(key) > len(CustomBlockKeyPrefix) && strings.HasPrefix(key, CustomBlockKeyPrefix)
+}
+
+// SanitizeCustomBlockID returns the ID converted to a form suitable for use in the block layout.
+func SanitizeCustomBlockID(id string) string {
+	var buffer strings.Builder
+	for _, ch := range strings.ToLower(strings.TrimSpace(id)) {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9':
+			buffer.WriteRune(ch)
+		case ch == '_' || ch == ' ' || ch == '-':
+			buffer.WriteByte('_')
+		}
+	}
+	return buffer.String()
+}
+
+// Key returns the key used to refer to this block in the block layout.
+func (b *CustomBlock) Key() string {
+	return CustomBlockKeyPrefix + b.ID
+}
+
+// String implements fmt.Stringer.
+func (b *CustomBlock) String() string {
+	if b.Title != "" {
+		return b.Title
+	}
+	return b.Type.String()
+}
+
+// Clone a copy of this.
+func (b *CustomBlock) Clone() *CustomBlock {
+	clone := *b
+	clone.Attributes = txt.CloneStringSlice(b.Attributes)
+	return &clone
+}
+
+// Skills returns the skills and techniques that have at least one of the tags in the block's filter.
+func (b *CustomBlock) Skills(entity *Entity) []*Skill {
+	tags := ExtractTags(b.Filter)
+	var list []*Skill
+	Traverse(func(s *Skill) bool {
+		for _, tag := range tags {
+			if HasTag(tag, s.Tags) {
+				list = append(list, s)
+				break
+			}
+		}
+		return false
+	}, true, true, entity.Skills...)
+	return list
+}
+
+// Notes returns the notes whose text contains the block's filter.
+func (b *CustomBlock) Notes(entity *Entity) []*Note {
+	filter := strings.ToLower(strings.TrimSpace(b.Filter))
+	var list []*Note
+	Traverse(func(n *Note) bool {
+		if filter == "" || strings.Contains(strings.ToLower(n.Text), filter) {
+			list = append(list, n)
+		}
+		return false
+	}, true, true, entity.Notes...)
+	return list
+}
+
+// CloneCustomBlocks creates a copy of the custom blocks.
+func CloneCustomBlocks(blocks []*CustomBlock) []*CustomBlock {
+	if blocks == nil {
+		return nil
+	}
+	clone := make([]*CustomBlock, len(blocks))
+	for i, one := range blocks {
+		clone[i] = one.Clone()
+	}
+	return clone
+}
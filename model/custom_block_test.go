@@ -0,0 +1,69 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomBlocks(t *testing.T) {
+	s := model.FactorySheetSettings()
+	s.CustomBlocks = []*model.CustomBlock{
+		{ID: "Combat Skills", Type: model.SkillsCustomBlockType, Filter: "Combat"},
+		{ID: "combat_skills", Type: model.NotesCustomBlockType},
+		{ID: "pools", Type: model.AttributesCustomBlockType, Attributes: []string{"fp", "hp"}},
+	}
+	s.BlockLayout.Layout = append(s.BlockLayout.Layout, "custom_pools custom_unknown")
+	s.EnsureValidity()
+	assert.Len(t, s.CustomBlocks, 2)
+	assert.Equal(t, "combat_skills", s.CustomBlocks[0].ID)
+	assert.Equal(t, []string{"custom_combat_skills", "custom_pools"}, s.CustomBlockKeys())
+	rows := s.BlockLayout.ByRow()
+	assert.Equal(t, []string{"custom_pools"}, rows[len(rows)-2])
+	assert.Equal(t, []string{"custom_combat_skills"}, rows[len(rows)-1])
+	_, valid := model.NewBlockLayoutFromString("custom_pools custom_pools")
+	assert.False(t, valid)
+
+	entity := model.NewEntity(model.PC)
+	for _, tags := range [][]string{{"Combat", "Melee"}, {"Social"}} {
+		skill := model.NewSkill(entity, nil, false)
+		skill.Tags = tags
+		entity.Skills = append(entity.Skills, skill)
+	}
+	skills := s.CustomBlocks[0].Skills(entity)
+	assert.Len(t, skills, 1)
+	assert.Equal(t, entity.Skills[0], skills[0])
+
+	s.SetColumnHidden(model.BlockLayoutSkillsKey, model.SkillPointsColumn, true)
+	s.SetColumnHidden(model.BlockLayoutSkillsKey, model.SkillDifficultyColumn, true)
+	assert.True(t, s.ColumnHidden(model.BlockLayoutSkillsKey, model.SkillDifficultyColumn))
+	assert.False(t, s.ColumnHidden(model.BlockLayoutSpellsKey, model.SpellResistColumn))
+	assert.Equal(t, []string{"difficulty", "points"}, s.HiddenColumns[model.BlockLayoutSkillsKey])
+	assert.Equal(t, []int{model.SkillDifficultyColumn, model.SkillPointsColumn},
+		s.HiddenColumnIDs(model.BlockLayoutSkillsKey))
+	s.SetColumnHidden(model.BlockLayoutSkillsKey, model.SkillDifficultyColumn, false)
+	s.SetColumnHidden(model.BlockLayoutSkillsKey, model.SkillPointsColumn, false)
+	assert.Empty(t, s.HiddenColumns)
+
+	// Weapon tables must find their hidden columns under the same key the settings store them with
+	s.SetColumnHidden(model.BlockLayoutMeleeKey, model.WeaponReachColumn, true)
+	assert.Equal(t, []int{model.WeaponReachColumn}, s.HiddenColumnIDs(model.MeleeWeaponType.BlockLayoutKey()))
+	assert.Empty(t, s.HiddenColumnIDs(model.RangedWeaponType.BlockLayoutKey()))
+	assert.Equal(t, []string{"reach"}, s.HiddenColumns[model.BlockLayoutMeleeKey])
+
+	s.HiddenColumns[model.BlockLayoutNotesKey] = []string{"bogus", "reference"}
+	s.EnsureValidity()
+	assert.Equal(t, []string{"reference"}, s.HiddenColumns[model.BlockLayoutNotesKey])
+}
@@ -0,0 +1,95 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	AttributesCustomBlockType CustomBlockType = iota
+	SkillsCustomBlockType
+	NotesCustomBlockType
+	LastCustomBlockType = NotesCustomBlockType
+)
+
+// AllCustomBlockType holds all possible values.
+var AllCustomBlockType = []CustomBlockType{
+	AttributesCustomBlockType,
+	SkillsCustomBlockType,
+	NotesCustomBlockType,
+}
+
+// CustomBlockType holds the type of content shown by a custom sheet block.
+type CustomBlockType byte
+
+// EnsureValid ensures this is of a known value.
+func (enum CustomBlockType) EnsureValid() CustomBlockType {
+	if enum <= LastCustomBlockType {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum CustomBlockType) Key() string {
+	switch enum {
+	case AttributesCustomBlockType:
+		return "attributes"
+	case SkillsCustomBlockType:
+		return "skills"
+	case NotesCustomBlockType:
+		return "notes"
+	default:
+		return CustomBlockType(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum CustomBlockType) String() string {
+	switch enum {
+	case AttributesCustomBlockType:
+		return i18n.Text("Attributes & Pools")
+	case SkillsCustomBlockType:
+		return i18n.Text("Skills With Tags")
+	case NotesCustomBlockType:
+		return i18n.Text("Notes Containing Text")
+	default:
+		return CustomBlockType(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum CustomBlockType) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *CustomBlockType) UnmarshalText(text []byte) error {
+	*enum = ExtractCustomBlockType(string(text))
+	return nil
+}
+
+// ExtractCustomBlockType extracts the value from a string.
+func ExtractCustomBlockType(str string) CustomBlockType {
+	for _, enum := range AllCustomBlockType {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
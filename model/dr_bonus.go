@@ -29,6 +29,7 @@ type DRBonusData struct {
 	Location       string      `json:"location"`
 	Specialization string      `json:"specialization,omitempty"`
 	LeveledAmount
+	FeatureConditionHolder
 }
 
 // DRBonus holds the data for a DR adjustment.
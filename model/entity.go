@@ -15,6 +15,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"math"
 	"sort"
@@ -33,6 +34,7 @@ import (
 	"github.com/richardwilkes/toolbox/log/jot"
 	"github.com/richardwilkes/toolbox/xio"
 	"github.com/richardwilkes/toolbox/xmath"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 )
 
 var (
@@ -63,23 +65,25 @@ func (pb *PointsBreakdown) Total() fxp.Int {
 
 // EntityData holds the Entity data that is written to disk.
 type EntityData struct {
-	Type             EntityType      `json:"type"`
-	Version          int             `json:"version"`
-	ID               uuid.UUID       `json:"id"`
-	TotalPoints      fxp.Int         `json:"total_points"`
-	PointsRecord     []*PointsRecord `json:"points_record,omitempty"`
-	Profile          *Profile        `json:"profile,omitempty"`
-	SheetSettings    *SheetSettings  `json:"settings,omitempty"`
-	Attributes       *Attributes     `json:"attributes,omitempty"`
-	Traits           []*Trait        `json:"traits,alt=advantages,omitempty"`
-	Skills           []*Skill        `json:"skills,omitempty"`
-	Spells           []*Spell        `json:"spells,omitempty"`
-	CarriedEquipment []*Equipment    `json:"equipment,omitempty"`
-	OtherEquipment   []*Equipment    `json:"other_equipment,omitempty"`
-	Notes            []*Note         `json:"notes,omitempty"`
-	CreatedOn        jio.Time        `json:"created_date"`
-	ModifiedOn       jio.Time        `json:"modified_date"`
-	ThirdParty       map[string]any  `json:"third_party,omitempty"`
+	Type             EntityType        `json:"type"`
+	Version          int               `json:"version"`
+	ID               uuid.UUID         `json:"id"`
+	TotalPoints      fxp.Int           `json:"total_points"`
+	PointsRecord     []*PointsRecord   `json:"points_record,omitempty"`
+	Profile          *Profile          `json:"profile,omitempty"`
+	SheetSettings    *SheetSettings    `json:"settings,omitempty"`
+	Campaign         string            `json:"campaign,omitempty"`
+	Attributes       *Attributes       `json:"attributes,omitempty"`
+	Traits           []*Trait          `json:"traits,alt=advantages,omitempty"`
+	Skills           []*Skill          `json:"skills,omitempty"`
+	Spells           []*Spell          `json:"spells,omitempty"`
+	CarriedEquipment []*Equipment      `json:"equipment,omitempty"`
+	OtherEquipment   []*Equipment      `json:"other_equipment,omitempty"`
+	Notes            []*Note           `json:"notes,omitempty"`
+	LocationInjuries []*LocationInjury `json:"location_injuries,omitempty"`
+	CreatedOn        jio.Time          `json:"created_date"`
+	ModifiedOn       jio.Time          `json:"modified_date"`
+	ThirdParty       map[string]any    `json:"third_party,omitempty"`
 }
 
 type features struct {
@@ -109,6 +113,8 @@ type Entity struct {
 	cachedEncumbranceLevel          Encumbrance
 	cachedEncumbranceLevelForSkills Encumbrance
 	cachedVariables                 map[string]string
+	featureConditions               map[Feature]bool
+	activeThresholds                []*activeThreshold
 }
 
 // NewEntityFromFile loads an Entity from a file.
@@ -125,15 +131,17 @@ func NewEntityFromFile(fileSystem fs.FS, filePath string) (*Entity, error) {
 
 // NewEntity creates a new Entity.
 func NewEntity(entityType EntityType) *Entity {
-	settings := GlobalSettings().GeneralSettings()
+	globalSettings := GlobalSettings()
+	settings := globalSettings.GeneralSettings()
+	initialPoints := globalSettings.InitialPoints()
 	entity := &Entity{
 		EntityData: EntityData{
 			Type:        entityType,
 			ID:          NewUUID(),
-			TotalPoints: settings.InitialPoints,
+			TotalPoints: initialPoints,
 			PointsRecord: []*PointsRecord{
 				{
-					Points: settings.InitialPoints,
+					Points: initialPoints,
 					When:   jio.Now(),
 					Reason: i18n.Text("Initial points"),
 				},
@@ -142,10 +150,14 @@ func NewEntity(entityType EntityType) *Entity {
 			CreatedOn: jio.Now(),
 		},
 	}
-	entity.SheetSettings = GlobalSettings().SheetSettings().Clone(entity)
+	entity.SheetSettings = globalSettings.SheetSettings().Clone(entity)
+	if c := globalSettings.ActiveCampaign(); c != nil {
+		c.ApplyTo(entity)
+	}
+	entity.PointsRecord[0].GameDate = globalSettings.CurrentGameDate(entity)
 	entity.Attributes = NewAttributes(entity)
 	if settings.AutoFillProfile {
-		entity.Profile.AutoFill(entity)
+		entity.Profile.AutoFill(entity, rand.NewCryptoRand())
 	}
 	if settings.AutoAddNaturalAttacks {
 		entity.Traits = append(entity.Traits, NewNaturalAttacks(entity, nil))
@@ -165,6 +177,11 @@ func (e *Entity) Save(filePath string) error {
 	return jio.SaveToFile(context.Background(), filePath, e)
 }
 
+// SaveTo writes the Entity to the writer as JSON.
+func (e *Entity) SaveTo(w io.Writer) error {
+	return jio.Save(context.Background(), w, e)
+}
+
 // MarshalJSON implements json.Marshaler.
 func (e *Entity) MarshalJSON() ([]byte, error) {
 	e.Recalculate()
@@ -295,24 +312,27 @@ func (e *Entity) ensureAttachments() {
 	}
 }
 
-func (e *Entity) processFeatures() {
-	e.features = features{}
+// walkFeatures calls f for each feature of every enabled trait, skill and equipped item.
+func (e *Entity) walkFeatures(visitor func(owner fmt.Stringer, f Feature, levels fxp.Int)) {
 	Traverse(func(a *Trait) bool {
+		if a.Inactive() {
+			return false
+		}
 		var levels fxp.Int
 		if a.IsLeveled() {
 			levels = a.Levels.Max(0)
 		}
 		if !a.Container() {
 			for _, f := range a.Features {
-				e.processFeature(a, f, levels)
+				visitor(a, f, levels)
 			}
 		}
 		for _, f := range a.CRAdj.Features(a.CR) {
-			e.processFeature(a, f, levels)
+			visitor(a, f, levels)
 		}
 		Traverse(func(mod *TraitModifier) bool {
 			for _, f := range mod.Features {
-				e.processFeature(a, f, mod.Levels)
+				visitor(a, f, mod.Levels)
 			}
 			return false
 		}, true, true, a.Modifiers...)
@@ -320,7 +340,7 @@ func (e *Entity) processFeatures() {
 	}, true, false, e.Traits...)
 	Traverse(func(s *Skill) bool {
 		for _, f := range s.Features {
-			e.processFeature(s, f, s.LevelData.Level)
+			visitor(s, f, s.LevelData.Level)
 		}
 		return false
 	}, false, true, e.Skills...)
@@ -329,16 +349,43 @@ func (e *Entity) processFeatures() {
 			return false
 		}
 		for _, f := range eqp.Features {
-			e.processFeature(eqp, f, 0)
+			visitor(eqp, f, 0)
 		}
 		Traverse(func(mod *EquipmentModifier) bool {
 			for _, f := range mod.Features {
-				e.processFeature(eqp, f, 0)
+				visitor(eqp, f, 0)
 			}
 			return false
 		}, true, true, eqp.Modifiers...)
 		return false
 	}, false, false, e.CarriedEquipment...)
+	for _, one := range e.activeThresholds {
+		for _, f := range one.threshold.Features {
+			visitor(one, f, 0)
+		}
+	}
+}
+
+func (e *Entity) processFeatures() {
+	e.updateActiveThresholds()
+	e.updateFeatureConditions()
+	for i := 0; i < 5; i++ {
+		// Pool thresholds and feature conditions may depend on the values the features themselves modify, so they are
+		// re-evaluated once the features have been collected and, should that change which features are active, the
+		// features are collected again. As with Recalculate(), the iterations are capped to avoid a potential endless
+		// loop.
+		e.collectFeatures()
+		thresholdsChanged := e.updateActiveThresholds()
+		conditionsChanged := e.updateFeatureConditions()
+		if !thresholdsChanged && !conditionsChanged {
+			break
+		}
+	}
+}
+
+func (e *Entity) collectFeatures() {
+	e.features = features{}
+	e.walkFeatures(e.processFeature)
 	e.LiftingStrengthBonus = e.AttributeBonusFor(StrengthID, LiftingOnlyBonusLimitation, nil).Trunc()
 	e.StrikingStrengthBonus = e.AttributeBonusFor(StrengthID, StrikingOnlyBonusLimitation, nil).Trunc()
 	e.ThrowingStrengthBonus = e.AttributeBonusFor(StrengthID, ThrowingOnlyBonusLimitation, nil).Trunc()
@@ -361,6 +408,9 @@ func (e *Entity) processFeatures() {
 }
 
 func (e *Entity) processFeature(owner fmt.Stringer, f Feature, levels fxp.Int) {
+	if !e.FeatureActive(f) {
+		return
+	}
 	if bonus, ok := f.(Bonus); ok {
 		bonus.SetOwner(owner)
 		bonus.SetLevel(levels)
@@ -1120,6 +1170,9 @@ func (e *Entity) SetWeapons(_ WeaponType, _ []*Weapon) {
 func (e *Entity) EquippedWeapons(weaponType WeaponType) []*Weapon {
 	m := make(map[uint32]*Weapon)
 	Traverse(func(a *Trait) bool {
+		if a.Inactive() {
+			return false
+		}
 		for _, w := range a.Weapons {
 			if w.Type == weaponType {
 				m[w.HashCode()] = w
@@ -1165,6 +1218,9 @@ func (e *Entity) EquippedWeapons(weaponType WeaponType) []*Weapon {
 func (e *Entity) Reactions() []*ConditionalModifier {
 	m := make(map[string]*ConditionalModifier)
 	Traverse(func(a *Trait) bool {
+		if a.Inactive() {
+			return false
+		}
 		source := i18n.Text("from trait ") + a.String()
 		if !a.Container() {
 			e.reactionsFromFeatureList(source, a.Features, m)
@@ -1209,7 +1265,7 @@ func (e *Entity) Reactions() []*ConditionalModifier {
 
 func (e *Entity) reactionsFromFeatureList(source string, features Features, m map[string]*ConditionalModifier) {
 	for _, f := range features {
-		if bonus, ok := f.(*ReactionBonus); ok {
+		if bonus, ok := f.(*ReactionBonus); ok && e.FeatureActive(f) {
 			amt := bonus.AdjustedAmount()
 			if r, exists := m[bonus.Situation]; exists {
 				r.Add(source, amt)
@@ -1224,6 +1280,9 @@ func (e *Entity) reactionsFromFeatureList(source string, features Features, m ma
 func (e *Entity) ConditionalModifiers() []*ConditionalModifier {
 	m := make(map[string]*ConditionalModifier)
 	Traverse(func(a *Trait) bool {
+		if a.Inactive() {
+			return false
+		}
 		source := i18n.Text("from trait ") + a.String()
 		if !a.Container() {
 			e.conditionalModifiersFromFeatureList(source, a.Features, m)
@@ -1259,7 +1318,7 @@ func (e *Entity) ConditionalModifiers() []*ConditionalModifier {
 
 func (e *Entity) conditionalModifiersFromFeatureList(source string, features Features, m map[string]*ConditionalModifier) {
 	for _, f := range features {
-		if bonus, ok := f.(*ConditionalModifierBonus); ok {
+		if bonus, ok := f.(*ConditionalModifierBonus); ok && e.FeatureActive(f) {
 			amt := bonus.AdjustedAmount()
 			if r, exists := m[bonus.Situation]; exists {
 				r.Add(source, amt)
@@ -15,6 +15,8 @@ import (
 	"testing"
 
 	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/rpgtools/dice"
+	"github.com/richardwilkes/toolbox/xio"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,3 +58,241 @@ func TestEntityAttributeBonus(t *testing.T) {
 	require.Equal(t, fxp.Ten, entity.Attributes.Current("st"), "ST; leveled +1 bonus, with 3 levels, for throwing only")
 	require.Equal(t, fxp.From(3), entity.ThrowingStrengthBonus, "Throwing ST Bonus; leveled +1 bonus, with 3 levels, for throwing only")
 }
+
+func TestExpressionPrereq(t *testing.T) {
+	entity := NewEntity(PC)
+	prereq := NewExpressionPrereq()
+	prereq.Expression = "$st + $dx >= 20"
+	require.True(t, prereq.Satisfied(entity, nil, nil, "", nil), "ST+DX default")
+
+	prereq.Expression = "$st + $dx >= 25"
+	var tooltip xio.ByteBuffer
+	require.False(t, prereq.Satisfied(entity, nil, &tooltip, "", nil), "ST+DX too low")
+	require.Contains(t, tooltip.String(), "$st=10")
+
+	trait := NewTrait(entity, nil, false)
+	trait.Name = "Magery"
+	trait.CanLevel = true
+	trait.Levels = fxp.Three
+	entity.Traits = append(entity.Traits, trait)
+	prereq.Expression = "trait_level(Magery) >= 3 && has_trait(Magery)"
+	require.True(t, prereq.Satisfied(entity, nil, nil, "", nil), "Magery 3")
+
+	prereq.Expression = "$st +"
+	tooltip.Reset()
+	require.False(t, prereq.Satisfied(entity, nil, &tooltip, "", nil), "invalid expression")
+	require.Contains(t, tooltip.String(), "invalid")
+}
+
+func TestConditionalFeatures(t *testing.T) {
+	entity := NewEntity(PC)
+	bonus := NewAttributeBonus("st")
+	bonus.Amount = fxp.Three
+	bonus.Condition.Type = ToggleFeatureConditionType
+	trait := NewTrait(entity, nil, false)
+	trait.Name = "Berserk"
+	trait.Features = append(trait.Features, bonus)
+	entity.Traits = append(entity.Traits, trait)
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("st"), "ST; toggled off")
+	require.Len(t, ToggleableConditions(trait), 1)
+
+	bonus.Condition.Active = true
+	entity.Recalculate()
+	require.Equal(t, fxp.From(13), entity.Attributes.Current("st"), "ST; toggled on")
+
+	bonus.Condition = FeatureCondition{Type: ExpressionFeatureConditionType, Expression: "$dx > 10"}
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("st"), "ST; expression false")
+
+	dxBonus := NewAttributeBonus("dx")
+	dxBonus.Amount = fxp.Two
+	trait.Features = append(trait.Features, dxBonus)
+	entity.Recalculate()
+	require.Equal(t, fxp.From(13), entity.Attributes.Current("st"), "ST; expression true from a bonus in the same pass")
+	trait.Features = trait.Features[:1]
+
+	bonus.Condition = FeatureCondition{Type: EncumbranceFeatureConditionType, MaxEncumbrance: NoEncumbrance}
+	entity.Recalculate()
+	require.Equal(t, fxp.From(13), entity.Attributes.Current("st"), "ST; unencumbered")
+}
+
+func TestPoolThresholdEffects(t *testing.T) {
+	entity := NewEntity(PC)
+	tired := entity.SheetSettings.Attributes.Set["fp"].Thresholds[2]
+	bonus := NewAttributeBonus("dx")
+	bonus.Amount = -fxp.Two
+	tired.Features = append(tired.Features, bonus)
+	tired.DisabledTags = []string{"Exertion"}
+	trait := NewTrait(entity, nil, false)
+	trait.Name = "Extra Effort"
+	trait.Tags = []string{"Exertion"}
+	entity.Traits = append(entity.Traits, trait)
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("dx"), "DX; rested")
+	require.True(t, trait.Enabled(), "trait; rested")
+
+	entity.Attributes.Set["fp"].Damage = fxp.From(8)
+	entity.Recalculate()
+	require.Equal(t, fxp.Eight, entity.Attributes.Current("dx"), "DX; tired")
+	require.False(t, trait.Enabled(), "trait; tired")
+
+	entity.Attributes.Set["fp"].Damage = fxp.From(5)
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("dx"), "DX; tiring")
+	fpBonus := NewAttributeBonus("fp")
+	fpBonus.Amount = -fxp.Four
+	drained := NewTrait(entity, nil, false)
+	drained.Name = "Drained"
+	drained.Features = append(drained.Features, fpBonus)
+	entity.Traits = append(entity.Traits, drained)
+	entity.Recalculate()
+	require.Equal(t, fxp.Eight, entity.Attributes.Current("dx"), "DX; tired from a bonus in the same pass")
+}
+
+func TestSwitchableTraits(t *testing.T) {
+	entity := NewEntity(PC)
+	trait := NewTrait(entity, nil, false)
+	trait.Name = "Berserk"
+	trait.Switchable = true
+	trait.ActivationCost = fxp.Two
+	bonus := NewAttributeBonus("st")
+	bonus.Amount = fxp.Three
+	trait.Features = append(trait.Features, bonus)
+	entity.Traits = append(entity.Traits, trait)
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("st"), "ST; inactive")
+
+	require.NoError(t, trait.SetActive(true))
+	entity.Recalculate()
+	require.Equal(t, fxp.From(13), entity.Attributes.Current("st"), "ST; active")
+	require.Equal(t, fxp.Two, entity.Attributes.Set["fp"].Damage, "FP spent")
+
+	require.NoError(t, trait.SetActive(false))
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("st"), "ST; switched off")
+	require.Equal(t, fxp.Two, entity.Attributes.Set["fp"].Damage, "FP not refunded")
+}
+
+func TestAlternativeAbilities(t *testing.T) {
+	entity := NewEntity(PC)
+	container := NewTrait(entity, nil, true)
+	container.ContainerType = AlternativeAbilitiesContainerType
+	for i, attr := range []string{"st", "dx"} {
+		child := NewTrait(entity, container, false)
+		child.BasePoints = fxp.From(10 * (i + 1))
+		bonus := NewAttributeBonus(attr)
+		bonus.Amount = fxp.One
+		child.Features = append(child.Features, bonus)
+		container.Children = append(container.Children, child)
+	}
+	entity.Traits = append(entity.Traits, container)
+	entity.Recalculate()
+	require.Equal(t, fxp.From(22), container.AdjustedPoints())
+	require.Equal(t, fxp.From(11), entity.Attributes.Current("st"), "ST; first alternative in use by default")
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("dx"), "DX; first alternative in use by default")
+
+	require.NoError(t, container.Children[1].SetActive(true))
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, entity.Attributes.Current("st"), "ST; second alternative in use")
+	require.Equal(t, fxp.From(11), entity.Attributes.Current("dx"), "DX; second alternative in use")
+
+	container.ActiveSlots = 2
+	require.NoError(t, container.Children[0].SetActive(true))
+	entity.Recalculate()
+	require.Equal(t, fxp.From(11), entity.Attributes.Current("st"), "ST; both alternatives in use")
+	require.Equal(t, fxp.From(11), entity.Attributes.Current("dx"), "DX; both alternatives in use")
+}
+
+func TestConditionalThisWeaponFeatures(t *testing.T) {
+	entity := NewEntity(PC)
+	trait := NewTrait(entity, nil, false)
+	trait.Name = "Claws"
+	weapon := NewWeapon(trait, MeleeWeaponType)
+	weapon.Defaults = []*SkillDefault{{DefaultType: "dx"}}
+	weapon.Damage.Base = dice.New("1d")
+	trait.Weapons = []*Weapon{weapon}
+	skillBonus := NewSkillBonus()
+	skillBonus.SelectionType = ThisWeaponSkillSelectionType
+	skillBonus.Amount = fxp.Two
+	skillBonus.Condition.Type = ToggleFeatureConditionType
+	damageBonus := NewWeaponDamageBonus()
+	damageBonus.SelectionType = ThisWeaponWeaponSelectionType
+	damageBonus.Amount = fxp.One
+	damageBonus.Condition.Type = ToggleFeatureConditionType
+	trait.Features = append(trait.Features, skillBonus, damageBonus)
+	entity.Traits = append(entity.Traits, trait)
+	entity.Recalculate()
+	require.Equal(t, fxp.Ten, weapon.SkillLevel(nil), "skill; toggled off")
+	offDamage := weapon.Damage.ResolvedDamage(nil)
+
+	skillBonus.Condition.Active = true
+	damageBonus.Condition.Active = true
+	entity.Recalculate()
+	require.Equal(t, fxp.From(12), weapon.SkillLevel(nil), "skill; toggled on")
+	require.NotEqual(t, offDamage, weapon.Damage.ResolvedDamage(nil), "damage; toggled on")
+}
+
+func TestSwitchedOffTraitModifiers(t *testing.T) {
+	entity := NewEntity(PC)
+	container := NewTrait(entity, nil, true)
+	container.Name = "Disguise Kit"
+	trait := NewTrait(entity, container, false)
+	trait.Name = "Charming"
+	trait.Switchable = true
+	trait.Features = append(trait.Features, NewReactionBonus(), NewConditionalModifierBonus())
+	container.Children = append(container.Children, trait)
+	entity.Traits = append(entity.Traits, container)
+	require.False(t, HasToggleableTraits(nil))
+	require.True(t, HasToggleableTraits(entity.Traits))
+	entity.Recalculate()
+	require.Empty(t, entity.Reactions(), "reactions; switched off")
+	require.Empty(t, entity.ConditionalModifiers(), "conditional modifiers; switched off")
+
+	require.NoError(t, trait.SetActive(true))
+	entity.Recalculate()
+	require.Len(t, entity.Reactions(), 1, "reactions; switched on")
+	require.Len(t, entity.ConditionalModifiers(), 1, "conditional modifiers; switched on")
+}
+
+func TestSwitchableAlternativeAbilities(t *testing.T) {
+	entity := NewEntity(PC)
+	container := NewTrait(entity, nil, true)
+	container.ContainerType = AlternativeAbilitiesContainerType
+	for i := 0; i < 2; i++ {
+		child := NewTrait(entity, container, false)
+		child.Switchable = true
+		child.ActivationCost = fxp.One
+		child.Features = append(child.Features, NewReactionBonus(), NewConditionalModifierBonus())
+		container.Children = append(container.Children, child)
+	}
+	first := container.Children[0]
+	second := container.Children[1]
+	entity.Traits = append(entity.Traits, container)
+	entity.Recalculate()
+	require.True(t, first.Inactive(), "first; in use but switched off")
+	require.Empty(t, entity.Reactions(), "reactions; switched off")
+	require.Empty(t, entity.ConditionalModifiers(), "conditional modifiers; switched off")
+
+	require.NoError(t, first.SetActive(true))
+	entity.Recalculate()
+	require.False(t, first.Inactive(), "first; in use and switched on")
+	require.Equal(t, fxp.One, entity.Attributes.Set["fp"].Damage, "FP spent switching on the alternative in use")
+	require.Len(t, entity.Reactions(), 1, "reactions; switched on")
+	require.Len(t, entity.ConditionalModifiers(), 1, "conditional modifiers; switched on")
+
+	require.NoError(t, first.SetActive(true))
+	require.Equal(t, fxp.One, entity.Attributes.Set["fp"].Damage, "FP not spent again when already in use")
+
+	second.Active = true
+	entity.Recalculate()
+	require.True(t, second.Inactive(), "second; switched on but not in use")
+	require.Len(t, entity.Reactions(), 1, "reactions; alternative not in use")
+	require.Len(t, entity.ConditionalModifiers(), 1, "conditional modifiers; alternative not in use")
+
+	require.NoError(t, second.SetActive(true))
+	entity.Recalculate()
+	require.True(t, first.Inactive(), "first; replaced")
+	require.False(t, second.Inactive(), "second; in use and switched on")
+	require.Equal(t, fxp.Two, entity.Attributes.Set["fp"].Damage, "FP spent putting the alternative into use")
+}
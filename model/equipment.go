@@ -13,6 +13,8 @@ package model
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"io/fs"
 	"strconv"
 	"strings"
@@ -91,6 +93,15 @@ func SaveEquipment(equipment []*Equipment, filePath string) error {
 	})
 }
 
+// SaveEquipmentTo writes the Equipment list to the writer as JSON.
+func SaveEquipmentTo(equipment []*Equipment, w io.Writer) error {
+	return jio.Save(context.Background(), w, &equipmentListData{
+		Type:    equipmentListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    equipment,
+	})
+}
+
 // NewEquipment creates a new Equipment.
 func NewEquipment(entity *Entity, parent *Equipment, container bool) *Equipment {
 	e := Equipment{
@@ -201,6 +212,8 @@ func (e *Equipment) CellData(columnID int, data *CellData) {
 		data.Primary = e.Description()
 		data.Secondary = e.SecondaryText(func(option DisplayOption) bool { return option.Inline() })
 		data.UnsatisfiedReason = e.UnsatisfiedReason
+		data.Warning = e.CapacityWarning()
+		data.ComplianceWarning = ComplianceWarning(e.ComplianceIssues())
 		data.Tooltip = e.SecondaryText(func(option DisplayOption) bool { return option.Tooltip() })
 	case EquipmentUsesColumn:
 		if e.MaxUses > 0 {
@@ -328,9 +341,12 @@ func (e *Equipment) TagList() []string {
 	return e.Tags
 }
 
-// AdjustedValue returns the value after adjustments for any modifiers. Does not include the value of children.
+// AdjustedValue returns the value after adjustments for any modifiers, converted into the default currency. Includes the
+// value of any coins held, but does not include the value of children.
 func (e *Equipment) AdjustedValue() fxp.Int {
-	return ValueAdjustedForModifiers(e.Value, e.Modifiers)
+	settings := SheetSettingsFor(e.Entity)
+	return ValueAdjustedForModifiers(settings.ConvertCurrency(e.Value, e.Currency), e.Modifiers) +
+		settings.CoinValue(e.Coins)
 }
 
 // ExtendedValue returns the extended value.
@@ -352,22 +368,22 @@ func (e *Equipment) AdjustedWeight(forSkills bool, defUnits WeightUnits) Weight
 	if forSkills && e.WeightIgnoredForSkills && e.Equipped {
 		return 0
 	}
-	return WeightAdjustedForModifiers(e.Weight, e.Modifiers, defUnits)
+	return WeightAdjustedForModifiers(e.Weight, e.Modifiers, defUnits) + SheetSettingsFor(e.Entity).CoinWeight(e.Coins)
 }
 
 // ExtendedWeight returns the extended weight.
 func (e *Equipment) ExtendedWeight(forSkills bool, defUnits WeightUnits) Weight {
-	return ExtendedWeightAdjustedForModifiers(defUnits, e.Quantity, e.Weight, e.Modifiers, e.Features, e.Children, forSkills, e.WeightIgnoredForSkills && e.Equipped)
+	return ExtendedWeightAdjustedForModifiers(defUnits, e.Quantity, e.Weight, SheetSettingsFor(e.Entity).CoinWeight(e.Coins), e.Modifiers, e.Features, e.Children, forSkills, e.WeightIgnoredForSkills && e.Equipped)
 }
 
-// ExtendedWeightAdjustedForModifiers calculates the extended weight.
-func ExtendedWeightAdjustedForModifiers(defUnits WeightUnits, qty fxp.Int, baseWeight Weight, modifiers []*EquipmentModifier, features Features, children []*Equipment, forSkills, weightIgnoredForSkills bool) Weight {
+// ExtendedWeightAdjustedForModifiers calculates the extended weight. The coin weight is not subject to the modifiers.
+func ExtendedWeightAdjustedForModifiers(defUnits WeightUnits, qty fxp.Int, baseWeight, coinWeight Weight, modifiers []*EquipmentModifier, features Features, children []*Equipment, forSkills, weightIgnoredForSkills bool) Weight {
 	if qty <= 0 {
 		return 0
 	}
 	var base fxp.Int
 	if !forSkills || !weightIgnoredForSkills {
-		base = fxp.Int(WeightAdjustedForModifiers(baseWeight, modifiers, defUnits))
+		base = fxp.Int(WeightAdjustedForModifiers(baseWeight, modifiers, defUnits) + coinWeight)
 	}
 	if len(children) != 0 {
 		var contained fxp.Int
@@ -406,6 +422,79 @@ func ExtendedWeightAdjustedForModifiers(defUnits WeightUnits, qty fxp.Int, baseW
 	return Weight(base.Mul(qty))
 }
 
+// ContainedWeight returns the weight of the contents of this container, before any contained weight reductions
+// provided by the container are applied.
+func (e *Equipment) ContainedWeight(defUnits WeightUnits) Weight {
+	var total Weight
+	if e.Container() {
+		for _, one := range e.Children {
+			total += one.ExtendedWeight(false, defUnits)
+		}
+	}
+	return total
+}
+
+// ContainedVolume returns the volume occupied by the direct contents of this container.
+func (e *Equipment) ContainedVolume() fxp.Int {
+	var total fxp.Int
+	if e.Container() {
+		for _, one := range e.Children {
+			if one.Quantity > 0 {
+				total += one.Volume.Mul(one.Quantity)
+			}
+		}
+	}
+	return total
+}
+
+// ContainedQuantity returns the number of items directly held by this container.
+func (e *Equipment) ContainedQuantity() fxp.Int {
+	var total fxp.Int
+	if e.Container() {
+		for _, one := range e.Children {
+			total += one.Quantity
+		}
+	}
+	return total
+}
+
+// HasCapacity returns true if this is a container with at least one capacity limit set.
+func (e *Equipment) HasCapacity() bool {
+	return e.Container() && (e.MaxContainedWeight > 0 || e.MaxContainedVolume > 0 || e.MaxContainedQuantity > 0)
+}
+
+// CapacityWarning returns a description of the ways in which the contents of this container exceed its capacity, or an
+// empty string if they do not.
+func (e *Equipment) CapacityWarning() string {
+	if !e.HasCapacity() {
+		return ""
+	}
+	var buffer strings.Builder
+	if e.MaxContainedWeight > 0 {
+		units := SheetSettingsFor(e.Entity).DefaultWeightUnits
+		if weight := e.ContainedWeight(units); weight > e.MaxContainedWeight {
+			fmt.Fprintf(&buffer, i18n.Text("\n● Contents weigh %s, but the maximum is %s"), units.Format(weight),
+				units.Format(e.MaxContainedWeight))
+		}
+	}
+	if e.MaxContainedVolume > 0 {
+		if volume := e.ContainedVolume(); volume > e.MaxContainedVolume {
+			fmt.Fprintf(&buffer, i18n.Text("\n● Contents occupy a volume of %s, but the maximum is %s"), volume.Comma(),
+				e.MaxContainedVolume.Comma())
+		}
+	}
+	if e.MaxContainedQuantity > 0 {
+		if qty := e.ContainedQuantity(); qty > e.MaxContainedQuantity {
+			fmt.Fprintf(&buffer, i18n.Text("\n● Holds %s items, but the maximum is %s"), qty.Comma(),
+				e.MaxContainedQuantity.Comma())
+		}
+	}
+	if buffer.Len() == 0 {
+		return ""
+	}
+	return i18n.Text("Over capacity:") + buffer.String()
+}
+
 // FillWithNameableKeys adds any nameable keys found to the provided map.
 func (e *Equipment) FillWithNameableKeys(m map[string]string) {
 	Extract(e.Name, m)
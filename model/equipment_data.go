@@ -27,4 +27,9 @@ func (d *EquipmentData) Kind() string {
 // ClearUnusedFieldsForType zeroes out the fields that are not applicable to this type (container vs not-container).
 func (d *EquipmentData) ClearUnusedFieldsForType() {
 	d.clearUnusedFields()
+	if !d.Container() {
+		d.MaxContainedWeight = 0
+		d.MaxContainedVolume = 0
+		d.MaxContainedQuantity = 0
+	}
 }
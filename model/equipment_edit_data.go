@@ -30,7 +30,13 @@ type EquipmentEditData struct {
 	Modifiers              []*EquipmentModifier `json:"modifiers,omitempty"`
 	Quantity               fxp.Int              `json:"quantity,omitempty"`
 	Value                  fxp.Int              `json:"value,omitempty"`
+	Currency               string               `json:"currency,omitempty"`
+	Coins                  map[string]fxp.Int   `json:"coins,omitempty"`
 	Weight                 Weight               `json:"weight,omitempty"`
+	Volume                 fxp.Int              `json:"volume,omitempty"`
+	MaxContainedWeight     Weight               `json:"max_contained_weight,omitempty"`   // Container only
+	MaxContainedVolume     fxp.Int              `json:"max_contained_volume,omitempty"`   // Container only
+	MaxContainedQuantity   fxp.Int              `json:"max_contained_quantity,omitempty"` // Container only
 	MaxUses                int                  `json:"max_uses,omitempty"`
 	Uses                   int                  `json:"uses,omitempty"`
 	Prereq                 *PrereqList          `json:"prereqs,omitempty"`
@@ -53,6 +59,15 @@ func (d *EquipmentEditData) ApplyTo(e *Equipment) {
 func (d *EquipmentEditData) copyFrom(entity *Entity, other *EquipmentEditData, isApply bool) {
 	*d = *other
 	d.Tags = txt.CloneStringSlice(d.Tags)
+	d.Coins = nil
+	if len(other.Coins) != 0 {
+		d.Coins = make(map[string]fxp.Int, len(other.Coins))
+		for k, v := range other.Coins {
+			if v != 0 {
+				d.Coins[k] = v
+			}
+		}
+	}
 	d.Modifiers = nil
 	if len(other.Modifiers) != 0 {
 		d.Modifiers = make([]*EquipmentModifier, 0, len(other.Modifiers))
@@ -13,6 +13,7 @@ package model
 
 import (
 	"context"
+	"io"
 	"io/fs"
 	"strings"
 
@@ -81,6 +82,15 @@ func SaveEquipmentModifiers(modifiers []*EquipmentModifier, filePath string) err
 	})
 }
 
+// SaveEquipmentModifiersTo writes the EquipmentModifier list to the writer as JSON.
+func SaveEquipmentModifiersTo(modifiers []*EquipmentModifier, w io.Writer) error {
+	return jio.Save(context.Background(), w, &equipmentModifierListData{
+		Type:    equipmentModifierListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    modifiers,
+	})
+}
+
 // NewEquipmentModifier creates an EquipmentModifier.
 func NewEquipmentModifier(entity *Entity, parent *EquipmentModifier, container bool) *EquipmentModifier {
 	a := &EquipmentModifier{
@@ -274,7 +284,17 @@ func (m *EquipmentModifier) CostDescription() string {
 	if m.Container() || (m.CostType == OriginalEquipmentModifierCostType && (m.CostAmount == "" || m.CostAmount == "+0")) {
 		return ""
 	}
-	return m.CostType.Format(m.CostAmount) + " " + m.CostType.String()
+	desc := m.CostType.Format(m.CostAmount)
+	if m.CostType.FromString(m.CostAmount) == AdditionEquipmentModifierCostValueType {
+		if c := SheetSettingsFor(m.Entity).CurrencyFor(m.CostCurrency); c != nil {
+			if c.Symbol != "" {
+				desc += " " + c.Symbol
+			} else {
+				desc += " " + c.ID
+			}
+		}
+	}
+	return desc + " " + m.CostType.String()
 }
 
 // WeightDescription returns the formatted weight.
@@ -359,7 +379,7 @@ func processNonCFStep(costType EquipmentModifierCostType, value fxp.Int, modifie
 			amt := t.ExtractValue(mod.CostAmount)
 			switch t {
 			case AdditionEquipmentModifierCostValueType:
-				additions += amt
+				additions += SheetSettingsFor(mod.Entity).ConvertCurrency(amt, mod.CostCurrency)
 			case PercentageEquipmentModifierCostValueType:
 				percentages += amt
 			case MultiplierEquipmentModifierCostValueType:
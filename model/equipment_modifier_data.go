@@ -35,6 +35,7 @@ func (d *EquipmentModifierData) ClearUnusedFieldsForType() {
 		d.Disabled = false
 		d.TechLevel = ""
 		d.CostAmount = ""
+		d.CostCurrency = ""
 		d.WeightAmount = ""
 		d.Features = nil
 	}
@@ -24,13 +24,14 @@ type EquipmentModifierEditData struct {
 	LocalNotes   string                      `json:"notes,omitempty"`
 	VTTNotes     string                      `json:"vtt_notes,omitempty"`
 	Tags         []string                    `json:"tags,omitempty"`
-	CostType     EquipmentModifierCostType   `json:"cost_type,omitempty"`   // Non-container only
-	WeightType   EquipmentModifierWeightType `json:"weight_type,omitempty"` // Non-container only
-	Disabled     bool                        `json:"disabled,omitempty"`    // Non-container only
-	TechLevel    string                      `json:"tech_level,omitempty"`  // Non-container only
-	CostAmount   string                      `json:"cost,omitempty"`        // Non-container only
-	WeightAmount string                      `json:"weight,omitempty"`      // Non-container only
-	Features     Features                    `json:"features,omitempty"`    // Non-container only
+	CostType     EquipmentModifierCostType   `json:"cost_type,omitempty"`     // Non-container only
+	WeightType   EquipmentModifierWeightType `json:"weight_type,omitempty"`   // Non-container only
+	Disabled     bool                        `json:"disabled,omitempty"`      // Non-container only
+	TechLevel    string                      `json:"tech_level,omitempty"`    // Non-container only
+	CostAmount   string                      `json:"cost,omitempty"`          // Non-container only
+	CostCurrency string                      `json:"cost_currency,omitempty"` // Non-container only
+	WeightAmount string                      `json:"weight,omitempty"`        // Non-container only
+	Features     Features                    `json:"features,omitempty"`      // Non-container only
 }
 
 // CopyFrom implements node.EditorData.
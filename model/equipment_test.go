@@ -0,0 +1,79 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquipmentCapacity(t *testing.T) {
+	entity := model.NewEntity(model.PC)
+	backpack := model.NewEquipment(entity, nil, true)
+	assert.False(t, backpack.HasCapacity())
+	assert.Empty(t, backpack.CapacityWarning())
+
+	rope := model.NewEquipment(entity, backpack, false)
+	rope.Quantity = fxp.Two
+	rope.Weight = model.WeightFromInteger(5, model.Pound)
+	rope.Volume = fxp.Three
+	backpack.Children = append(backpack.Children, rope)
+	assert.Equal(t, model.WeightFromInteger(10, model.Pound), backpack.ContainedWeight(model.Pound))
+	assert.Equal(t, fxp.From(6), backpack.ContainedVolume())
+	assert.Equal(t, fxp.Two, backpack.ContainedQuantity())
+
+	backpack.MaxContainedWeight = model.WeightFromInteger(10, model.Pound)
+	backpack.MaxContainedVolume = fxp.From(6)
+	backpack.MaxContainedQuantity = fxp.Two
+	assert.True(t, backpack.HasCapacity())
+	assert.Empty(t, backpack.CapacityWarning())
+
+	rope.Quantity = fxp.Three
+	assert.NotEmpty(t, backpack.CapacityWarning())
+
+	backpack.Type = "equipment"
+	assert.False(t, backpack.HasCapacity())
+	assert.Empty(t, backpack.CapacityWarning())
+}
+
+func TestEquipmentCurrency(t *testing.T) {
+	entity := model.NewEntity(model.PC)
+	entity.SheetSettings.Currencies = []*model.Currency{
+		{ID: "sp", Symbol: "sp", Rate: fxp.One, CoinWeight: model.Weight(fxp.From(1).Div(fxp.Hundred)), SymbolAfter: true},
+		{ID: "gp", Symbol: "gp", Rate: fxp.Ten, CoinWeight: model.Weight(fxp.From(1).Div(fxp.Hundred)), SymbolAfter: true},
+	}
+	entity.SheetSettings.DefaultCurrency = "sp"
+	assert.Equal(t, fxp.From(50), entity.SheetSettings.ConvertCurrency(fxp.From(5), "gp"))
+	assert.Equal(t, fxp.From(5), entity.SheetSettings.ConvertCurrency(fxp.From(5), "unknown"))
+	assert.Equal(t, "50 sp", entity.SheetSettings.FormatCurrency(fxp.From(50)))
+
+	purse := model.NewEquipment(entity, nil, false)
+	purse.Quantity = fxp.One
+	purse.Value = fxp.Two
+	purse.Currency = "gp"
+	purse.Coins = map[string]fxp.Int{"sp": fxp.From(30), "gp": fxp.From(70)}
+	assert.Equal(t, fxp.From(20+30+700), purse.AdjustedValue())
+	assert.Equal(t, model.Weight(fxp.One), purse.AdjustedWeight(false, model.Pound))
+
+	entity.SetCarriedEquipmentList([]*model.Equipment{purse})
+	assert.True(t, entity.UsesCurrency("SP"))
+	assert.False(t, entity.UsesCurrency("cp"))
+	entity.RenameCurrency("gp", "gold")
+	entity.SheetSettings.Currencies[1].ID = "gold"
+	assert.Equal(t, "gold", purse.Currency)
+	assert.Equal(t, map[string]fxp.Int{"sp": fxp.From(30), "gold": fxp.From(70)}, purse.Coins)
+	assert.False(t, entity.UsesCurrency("gp"))
+	assert.Equal(t, fxp.From(20+30+700), purse.AdjustedValue())
+}
@@ -20,18 +20,27 @@ import (
 	"github.com/richardwilkes/toolbox/errs"
 	"github.com/richardwilkes/toolbox/eval"
 	"github.com/richardwilkes/toolbox/txt"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 )
 
 // InstallEvaluatorFunctions installs additional functions for the evaluator.
 func InstallEvaluatorFunctions(m map[string]eval.Function) {
 	m["advantage_level"] = evalTraitLevel // For older files
+	m["contained_quantity"] = evalContainedQuantity
+	m["contained_volume"] = evalContainedVolume
+	m["contained_weight"] = evalContainedWeight
 	m["dice"] = evalDice
 	m["enc"] = evalEncumbrance
+	m["has_ancestry"] = evalHasAncestry
+	m["has_skill"] = evalHasSkill
+	m["has_trait"] = evalHasTrait
+	m["random_table"] = evalRandomTable
 	m["roll"] = evalRoll
 	m["signed"] = evalSigned
 	m["skill_level"] = evalSkillLevel
 	m["ssrt"] = evalSSRT
 	m["ssrt_to_yards"] = evalSSRTYards
+	m["tech_level"] = evalTechLevel
 	m["trait_level"] = evalTraitLevel
 }
 
@@ -93,6 +102,77 @@ func evalEncumbrance(e *eval.Evaluator, arguments string) (any, error) {
 	return level, nil
 }
 
+// evalContainedWeight takes up to 2 arguments: name (string, required) and maximum (bool, optional). Returns the weight
+// of the contents of the named container in the default weight units, or its maximum contained weight if 'maximum' is
+// true.
+func evalContainedWeight(e *eval.Evaluator, arguments string) (any, error) {
+	eqp, maximum, err := evalContainerArgs(e, arguments)
+	if err != nil || eqp == nil {
+		return fxp.Int(0), err
+	}
+	units := SheetSettingsFor(eqp.Entity).DefaultWeightUnits
+	if maximum {
+		return units.FromPounds(eqp.MaxContainedWeight), nil
+	}
+	return units.FromPounds(eqp.ContainedWeight(units)), nil
+}
+
+// evalContainedVolume takes up to 2 arguments: name (string, required) and maximum (bool, optional). Returns the volume
+// occupied by the contents of the named container, or its maximum contained volume if 'maximum' is true.
+func evalContainedVolume(e *eval.Evaluator, arguments string) (any, error) {
+	eqp, maximum, err := evalContainerArgs(e, arguments)
+	if err != nil || eqp == nil {
+		return fxp.Int(0), err
+	}
+	if maximum {
+		return eqp.MaxContainedVolume, nil
+	}
+	return eqp.ContainedVolume(), nil
+}
+
+// evalContainedQuantity takes up to 2 arguments: name (string, required) and maximum (bool, optional). Returns the
+// number of items held by the named container, or its maximum contained quantity if 'maximum' is true.
+func evalContainedQuantity(e *eval.Evaluator, arguments string) (any, error) {
+	eqp, maximum, err := evalContainerArgs(e, arguments)
+	if err != nil || eqp == nil {
+		return fxp.Int(0), err
+	}
+	if maximum {
+		return eqp.MaxContainedQuantity, nil
+	}
+	return eqp.ContainedQuantity(), nil
+}
+
+func evalContainerArgs(e *eval.Evaluator, arguments string) (eqp *Equipment, maximum bool, err error) {
+	entity, ok := e.Resolver.(*Entity)
+	if !ok || entity.Type != PC {
+		return nil, false, nil
+	}
+	name, remaining := eval.NextArg(arguments)
+	if name, err = evalToString(e, name); err != nil {
+		return nil, false, err
+	}
+	name = strings.Trim(name, `"`)
+	arg, _ := eval.NextArg(remaining)
+	if arg = strings.TrimSpace(arg); arg != "" {
+		if maximum, err = evalToBool(e, arg); err != nil {
+			return nil, false, err
+		}
+	}
+	f := func(one *Equipment) bool {
+		if strings.EqualFold(one.Name, name) {
+			eqp = one
+			return true
+		}
+		return false
+	}
+	Traverse(f, false, false, entity.CarriedEquipment...)
+	if eqp == nil {
+		Traverse(f, false, false, entity.OtherEquipment...)
+	}
+	return eqp, maximum, nil
+}
+
 // evalSkillLevel takes up to 3 arguments: name (string, required), specialization (string, optional), relative (bool, optional)
 func evalSkillLevel(e *eval.Evaluator, arguments string) (any, error) {
 	entity, ok := e.Resolver.(*Entity)
@@ -163,6 +243,80 @@ func evalTraitLevel(e *eval.Evaluator, arguments string) (any, error) {
 	return levels, nil
 }
 
+// evalHasTrait takes 1 argument: name (string, required). Returns true if the entity has an enabled trait with that name.
+func evalHasTrait(e *eval.Evaluator, arguments string) (any, error) {
+	entity, ok := e.Resolver.(*Entity)
+	if !ok || entity.Type != PC {
+		return false, nil
+	}
+	name, err := evalToString(e, arguments)
+	if err != nil {
+		return false, err
+	}
+	name = strings.Trim(name, `"`)
+	found := false
+	Traverse(func(t *Trait) bool {
+		found = strings.EqualFold(t.Name, name)
+		return found
+	}, true, false, entity.Traits...)
+	return found, nil
+}
+
+// evalHasSkill takes up to 2 arguments: name (string, required) and specialization (string, optional). Returns true if
+// the entity has the skill.
+func evalHasSkill(e *eval.Evaluator, arguments string) (any, error) {
+	entity, ok := e.Resolver.(*Entity)
+	if !ok || entity.Type != PC {
+		return false, nil
+	}
+	name, remaining := eval.NextArg(arguments)
+	var err error
+	if name, err = evalToString(e, name); err != nil {
+		return false, err
+	}
+	name = strings.Trim(name, `"`)
+	specialization, _ := eval.NextArg(remaining)
+	if specialization = strings.TrimSpace(specialization); specialization != "" {
+		if specialization, err = evalToString(e, specialization); err != nil {
+			return false, err
+		}
+		specialization = strings.Trim(specialization, `"`)
+	}
+	found := false
+	Traverse(func(s *Skill) bool {
+		found = strings.EqualFold(s.Name, name) && (specialization == "" ||
+			strings.EqualFold(s.Specialization, specialization))
+		return found
+	}, true, true, entity.Skills...)
+	return found, nil
+}
+
+// evalHasAncestry takes 1 argument: name (string, required). Returns true if the entity's ancestry has that name.
+func evalHasAncestry(e *eval.Evaluator, arguments string) (any, error) {
+	entity, ok := e.Resolver.(*Entity)
+	if !ok || entity.Type != PC {
+		return false, nil
+	}
+	name, err := evalToString(e, arguments)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(entity.Ancestry().Name, strings.Trim(name, `"`)), nil
+}
+
+// evalTechLevel takes no arguments. Returns the entity's tech level.
+func evalTechLevel(e *eval.Evaluator, _ string) (any, error) {
+	entity, ok := e.Resolver.(*Entity)
+	if !ok || entity.Type != PC {
+		return fxp.Int(0), nil
+	}
+	tl, _, _ := ExtractTechLevel(entity.Profile.TechLevel)
+	if tl < 0 {
+		tl = 0
+	}
+	return tl, nil
+}
+
 func evalDice(e *eval.Evaluator, arguments string) (any, error) {
 	var argList []int
 	for arguments != "" {
@@ -208,14 +362,79 @@ func evalDice(e *eval.Evaluator, arguments string) (any, error) {
 	return d.String(), nil
 }
 
+// evalFuncsWithRandomizer returns a copy of the evaluator functions with those that roll dice drawing from the
+// randomizer.
+func evalFuncsWithRandomizer(rnd rand.Randomizer) map[string]eval.Function {
+	m := make(map[string]eval.Function, len(fxp.EvalFuncs))
+	for k, v := range fxp.EvalFuncs {
+		m[k] = v
+	}
+	m["random_table"] = func(e *eval.Evaluator, arguments string) (any, error) {
+		return evalRandomTableWithRandomizer(e, arguments, rnd)
+	}
+	m["roll"] = func(e *eval.Evaluator, arguments string) (any, error) {
+		return evalRollWithRandomizer(e, arguments, rnd)
+	}
+	return m
+}
+
+// evaluateToNumberWithRandomizer evaluates the expression, drawing any dice rolls from the randomizer.
+func evaluateToNumberWithRandomizer(rnd rand.Randomizer, expression string, resolver eval.VariableResolver) fxp.Int {
+	evaluator := fxp.NewEvaluator(resolver)
+	evaluator.Functions = evalFuncsWithRandomizer(rnd)
+	return fxp.EvaluateToNumberWith(evaluator, expression)
+}
+
 func evalRoll(e *eval.Evaluator, arguments string) (any, error) {
+	return evalRollWithRandomizer(e, arguments, rand.NewCryptoRand())
+}
+
+func evalRollWithRandomizer(e *eval.Evaluator, arguments string, rnd rand.Randomizer) (any, error) {
 	if strings.IndexByte(arguments, '(') != -1 {
 		var err error
 		if arguments, err = evalToString(e, arguments); err != nil {
 			return nil, err
 		}
 	}
-	return fxp.From(dice.New(arguments).Roll(false)), nil
+	return fxp.From(dice.New(arguments).RollWithRandomizer(rnd, false)), nil
+}
+
+// evalRandomTable takes up to 3 arguments: name (string, required), modifier (number, optional) and text (bool,
+// optional). Rolls on the named random table and returns the value of the result, or its text if 'text' is true.
+func evalRandomTable(e *eval.Evaluator, arguments string) (any, error) {
+	return evalRandomTableWithRandomizer(e, arguments, rand.NewCryptoRand())
+}
+
+func evalRandomTableWithRandomizer(e *eval.Evaluator, arguments string, rnd rand.Randomizer) (any, error) {
+	name, remaining := eval.NextArg(arguments)
+	var err error
+	if name, err = evalToString(e, name); err != nil {
+		return nil, err
+	}
+	name = strings.Trim(name, `"`)
+	var modifier fxp.Int
+	arg, remaining := eval.NextArg(remaining)
+	if arg = strings.TrimSpace(arg); arg != "" {
+		if modifier, err = evalToNumber(e, arg); err != nil {
+			return nil, err
+		}
+	}
+	var wantText bool
+	arg, _ = eval.NextArg(remaining)
+	if arg = strings.TrimSpace(arg); arg != "" {
+		if wantText, err = evalToBool(e, arg); err != nil {
+			return nil, err
+		}
+	}
+	table := LookupRandomTable(name, GlobalSettings().Libraries())
+	if table == nil {
+		return nil, errs.Newf("unknown random table: %s", name)
+	}
+	result := table.RollWithRandomizer(rnd, fxp.As[int](modifier))
+	if wantText {
+		return result.Text(), nil
+	}
+	return result.Value(), nil
 }
 
 func evalSigned(e *eval.Evaluator, arguments string) (any, error) {
@@ -0,0 +1,137 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/txt"
+	"github.com/richardwilkes/toolbox/xio"
+)
+
+var _ Prereq = &ExpressionPrereq{}
+
+// ExpressionPrereq holds a prerequisite that is satisfied when an expression evaluates to true.
+type ExpressionPrereq struct {
+	Parent     *PrereqList `json:"-"`
+	Type       PrereqType  `json:"type"`
+	Expression string      `json:"expression,omitempty"`
+}
+
+// NewExpressionPrereq creates a new ExpressionPrereq.
+func NewExpressionPrereq() *ExpressionPrereq {
+	return &ExpressionPrereq{
+		Type:       ExpressionPrereqType,
+		Expression: "$st >= 10",
+	}
+}
+
+// PrereqType implements Prereq.
+func (e *ExpressionPrereq) PrereqType() PrereqType {
+	return e.Type
+}
+
+// ParentList implements Prereq.
+func (e *ExpressionPrereq) ParentList() *PrereqList {
+	return e.Parent
+}
+
+// Clone implements Prereq.
+func (e *ExpressionPrereq) Clone(parent *PrereqList) Prereq {
+	clone := *e
+	clone.Parent = parent
+	return &clone
+}
+
+// FillWithNameableKeys implements Prereq.
+func (e *ExpressionPrereq) FillWithNameableKeys(m map[string]string) {
+	Extract(e.Expression, m)
+}
+
+// ApplyNameableKeys implements Prereq.
+func (e *ExpressionPrereq) ApplyNameableKeys(m map[string]string) {
+	e.Expression = Apply(e.Expression, m)
+}
+
+// Satisfied implements Prereq.
+func (e *ExpressionPrereq) Satisfied(entity *Entity, _ any, tooltip *xio.ByteBuffer, prefix string, _ *bool) bool {
+	satisfied, err := EvaluateToBool(e.Expression, entity)
+	if !satisfied && tooltip != nil {
+		fmt.Fprintf(tooltip, i18n.Text("%sSatisfies the expression: %s"), prefix, e.Expression)
+		if err != nil {
+			fmt.Fprintf(tooltip, i18n.Text(" (invalid: %s)"), err.Error())
+		} else if values := expressionVariableValues(e.Expression, entity); values != "" {
+			fmt.Fprintf(tooltip, " (%s)", values)
+		}
+	}
+	return satisfied
+}
+
+// EvaluateToBool evaluates the expression against the entity and returns its result as a boolean.
+func EvaluateToBool(expression string, entity *Entity) (bool, error) {
+	if strings.TrimSpace(expression) == "" {
+		return false, errs.New(i18n.Text("empty expression"))
+	}
+	result, err := fxp.NewEvaluator(entity).Evaluate(expression)
+	if err != nil {
+		return false, err
+	}
+	switch v := result.(type) {
+	case bool:
+		return v, nil
+	case fxp.Int:
+		return v != 0, nil
+	case string:
+		if value, convErr := fxp.FromString(v); convErr == nil {
+			return value != 0, nil
+		}
+		return txt.IsTruthy(v), nil
+	default:
+		return false, nil
+	}
+}
+
+// expressionVariableValues returns a description of the current values of the variables referenced by the expression.
+func expressionVariableValues(expression string, entity *Entity) string {
+	if entity == nil {
+		return ""
+	}
+	var list []string
+	seen := make(map[string]bool)
+	for {
+		i := strings.IndexByte(expression, '$')
+		if i == -1 {
+			break
+		}
+		expression = expression[i+1:]
+		end := strings.IndexFunc(expression, func(ch rune) bool {
+			return ch != '_' && ch != '.' && ch != '#' && (ch < 'A' || ch > 'Z') && (ch < 'a' || ch > 'z') &&
+				(ch < '0' || ch > '9')
+		})
+		if end == -1 {
+			end = len(expression)
+		}
+		if name := expression[:end]; name != "" && !seen[name] {
+			seen[name] = true
+			value := entity.ResolveVariable(name)
+			if value == "" {
+				value = "?"
+			}
+			list = append(list, "$"+name+"="+value)
+		}
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/eval"
+	"github.com/richardwilkes/toolbox/i18n"
+	"golang.org/x/exp/slices"
+)
+
+// ExpressionVariable describes a variable that an entity can resolve for use in expressions.
+type ExpressionVariable struct {
+	Name        string
+	Description string
+	Value       string
+}
+
+// ExpressionResult holds the outcome of evaluating an expression.
+type ExpressionResult struct {
+	Value any
+	Err   error
+	// Failing holds the smallest portion of the expression that could be identified as the cause of the error.
+	Failing string
+	// Unresolved holds the names of any variables in the expression that could not be resolved.
+	Unresolved []string
+}
+
+// ExpressionVariables returns the variables that may be referenced in expressions, along with their current values.
+func (e *Entity) ExpressionVariables() []*ExpressionVariable {
+	list := []*ExpressionVariable{{
+		Name:        SizeModifierID,
+		Description: i18n.Text("Size Modifier"),
+		Value:       e.ResolveVariable(SizeModifierID),
+	}}
+	for _, attr := range e.Attributes.List() {
+		def := attr.AttributeDef()
+		if def == nil || def.IsSeparator() {
+			continue
+		}
+		list = append(list, &ExpressionVariable{
+			Name:        attr.AttrID,
+			Description: def.CombinedName(),
+			Value:       e.ResolveVariable(attr.AttrID),
+		})
+		if def.Type == PoolAttributeType {
+			name := attr.AttrID + ".current"
+			list = append(list, &ExpressionVariable{
+				Name:        name,
+				Description: fmt.Sprintf(i18n.Text("%s (current)"), def.CombinedName()),
+				Value:       e.ResolveVariable(name),
+			})
+		}
+	}
+	return list
+}
+
+// EvaluateExpression evaluates the expression. If evaluation fails, an attempt is made to narrow down the part of the
+// expression responsible.
+func EvaluateExpression(expression string, resolver eval.VariableResolver) *ExpressionResult {
+	var result ExpressionResult
+	result.Value, result.Err = fxp.NewEvaluator(resolver).Evaluate(expression)
+	if resolver != nil {
+		result.Unresolved = unresolvedVariables(expression, resolver)
+	}
+	if result.Err != nil {
+		result.Failing = failingSubExpression(strings.TrimSpace(expression), resolver)
+	}
+	return &result
+}
+
+// ErrorText returns the error message without any call stack information.
+func (r *ExpressionResult) ErrorText() string {
+	if r.Err == nil {
+		return ""
+	}
+	if msg, ok := r.Err.(interface{ Message() string }); ok {
+		return msg.Message()
+	}
+	return r.Err.Error()
+}
+
+// ValueText returns the value as text.
+func (r *ExpressionResult) ValueText() string {
+	if r.Err != nil || r.Value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", r.Value)
+}
+
+func failingSubExpression(expression string, resolver eval.VariableResolver) string {
+	for _, group := range parenthesizedGroups(expression) {
+		if group.function == "" {
+			if expressionFails(group.inner, resolver) {
+				return failingSubExpression(strings.TrimSpace(group.inner), resolver)
+			}
+			continue
+		}
+		if !expressionFails(group.text, resolver) {
+			continue
+		}
+		remaining := group.inner
+		for remaining != "" {
+			var arg string
+			arg, remaining = eval.NextArg(remaining)
+			if arg = strings.TrimSpace(arg); arg != "" && expressionFails(arg, resolver) {
+				return failingSubExpression(arg, resolver)
+			}
+		}
+		return group.text
+	}
+	if resolver != nil {
+		if unresolved := unresolvedVariables(expression, resolver); len(unresolved) != 0 {
+			return "$" + unresolved[0]
+		}
+	}
+	return expression
+}
+
+func expressionFails(expression string, resolver eval.VariableResolver) bool {
+	_, err := fxp.NewEvaluator(resolver).Evaluate(expression)
+	return err != nil
+}
+
+type parenthesizedGroup struct {
+	function string
+	text     string
+	inner    string
+}
+
+// parenthesizedGroups returns the top-level parenthesized groups within the expression, including any function name
+// that precedes them.
+func parenthesizedGroups(expression string) []parenthesizedGroup {
+	var groups []parenthesizedGroup
+	depth := 0
+	start := -1
+	inQuote := false
+	for i, ch := range expression {
+		switch {
+		case ch == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case ch == '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ch == ')' && depth > 0:
+			depth--
+			if depth == 0 {
+				nameStart := start
+				for nameStart > 0 && isExpressionNameChar(rune(expression[nameStart-1])) {
+					nameStart--
+				}
+				groups = append(groups, parenthesizedGroup{
+					function: expression[nameStart:start],
+					text:     expression[nameStart : i+1],
+					inner:    expression[start+1 : i],
+				})
+			}
+		}
+	}
+	return groups
+}
+
+// unresolvedVariables returns the names of the variables referenced in the expression that the resolver can't resolve.
+func unresolvedVariables(expression string, resolver eval.VariableResolver) []string {
+	var list []string
+	for {
+		dollar := strings.IndexRune(expression, '$')
+		if dollar == -1 {
+			return list
+		}
+		expression = expression[dollar+1:]
+		end := 0
+		for end < len(expression) && (isExpressionNameChar(rune(expression[end])) || expression[end] == '.' ||
+			expression[end] == '#') {
+			end++
+		}
+		if end != 0 {
+			if name := expression[:end]; !slices.Contains(list, name) &&
+				strings.TrimSpace(resolver.ResolveVariable(name)) == "" {
+				list = append(list, name)
+			}
+		}
+		expression = expression[end:]
+	}
+}
+
+func isExpressionNameChar(ch rune) bool {
+	return ch == '_' || (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9')
+}
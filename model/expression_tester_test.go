@@ -0,0 +1,50 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpressionTester(t *testing.T) {
+	entity := model.NewEntity(model.PC)
+	var haveST, haveHPCurrent bool
+	for _, one := range entity.ExpressionVariables() {
+		switch one.Name {
+		case "st":
+			haveST = true
+			assert.Equal(t, "10", one.Value)
+		case "hp.current":
+			haveHPCurrent = true
+		}
+	}
+	assert.True(t, haveST)
+	assert.True(t, haveHPCurrent)
+
+	result := model.EvaluateExpression("$st + 2", entity)
+	assert.NoError(t, result.Err)
+	assert.Equal(t, "12", result.ValueText())
+	assert.Empty(t, result.Unresolved)
+
+	result = model.EvaluateExpression("max(2, ($st + $bogus) * 2)", entity)
+	assert.Error(t, result.Err)
+	assert.Equal(t, []string{"bogus"}, result.Unresolved)
+	assert.Equal(t, "$bogus", result.Failing)
+	assert.NotEmpty(t, result.ErrorText())
+
+	result = model.EvaluateExpression("1 + nosuch(3)", entity)
+	assert.Error(t, result.Err)
+	assert.Equal(t, "nosuch(3)", result.Failing)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// ConditionalFeature defines the methods a Feature that can be conditionally active must implement. All features
+// except ContainedWeightReduction implement it.
+type ConditionalFeature interface {
+	Feature
+	ActivationCondition() *FeatureCondition
+}
+
+// FeatureConditionHolder provides the storage for a Feature's activation condition. Embed it in a Feature to make it
+// a ConditionalFeature.
+type FeatureConditionHolder struct {
+	Condition FeatureCondition `json:"condition,omitempty"`
+}
+
+// ActivationCondition implements ConditionalFeature.
+func (h *FeatureConditionHolder) ActivationCondition() *FeatureCondition {
+	return &h.Condition
+}
+
+// FeatureCondition holds the condition that controls whether a Feature is active.
+type FeatureCondition struct {
+	Type           FeatureConditionType `json:"type"`
+	Expression     string               `json:"expression,omitempty"`
+	Qualifier      string               `json:"qualifier,omitempty"`
+	MinEncumbrance Encumbrance          `json:"min_encumbrance,omitempty"`
+	MaxEncumbrance Encumbrance          `json:"max_encumbrance,omitempty"`
+	Active         bool                 `json:"active,omitempty"`
+}
+
+// ShouldOmit implements json.Omitter.
+func (c FeatureCondition) ShouldOmit() bool {
+	return c.Type.EnsureValid() == AlwaysFeatureConditionType
+}
+
+// Conditional returns true if the condition may cause the Feature to be inactive.
+func (c *FeatureCondition) Conditional() bool {
+	return c != nil && c.Type.EnsureValid() != AlwaysFeatureConditionType
+}
+
+// Satisfied returns true if the condition is currently met by the entity.
+func (c *FeatureCondition) Satisfied(entity *Entity) bool {
+	if !c.Conditional() {
+		return true
+	}
+	if entity == nil {
+		return false
+	}
+	switch c.Type {
+	case ExpressionFeatureConditionType:
+		result, err := EvaluateToBool(c.Expression, entity)
+		return err == nil && result
+	case EncumbranceFeatureConditionType:
+		level := entity.EncumbranceLevel(false)
+		return level >= c.MinEncumbrance && level <= c.MaxEncumbrance
+	case EquippedNameFeatureConditionType, EquippedTagFeatureConditionType:
+		qualifier := strings.TrimSpace(c.Qualifier)
+		if qualifier == "" {
+			return false
+		}
+		found := false
+		Traverse(func(eqp *Equipment) bool {
+			if eqp.Equipped && eqp.Quantity > 0 {
+				if c.Type == EquippedNameFeatureConditionType {
+					found = strings.EqualFold(eqp.Name, qualifier)
+				} else {
+					found = HasTag(qualifier, eqp.Tags)
+				}
+			}
+			return found
+		}, false, false, entity.CarriedEquipment...)
+		return found
+	case ToggleFeatureConditionType:
+		return c.Active
+	default:
+		return true
+	}
+}
+
+// String implements fmt.Stringer.
+func (c *FeatureCondition) String() string {
+	switch c.Type {
+	case ExpressionFeatureConditionType:
+		return fmt.Sprintf(i18n.Text("while %s"), c.Expression)
+	case EncumbranceFeatureConditionType:
+		if c.MinEncumbrance == c.MaxEncumbrance {
+			return fmt.Sprintf(i18n.Text("while at %s encumbrance"), c.MinEncumbrance)
+		}
+		return fmt.Sprintf(i18n.Text("while at %s to %s encumbrance"), c.MinEncumbrance, c.MaxEncumbrance)
+	case EquippedNameFeatureConditionType:
+		return fmt.Sprintf(i18n.Text("while %s is equipped"), c.Qualifier)
+	case EquippedTagFeatureConditionType:
+		return fmt.Sprintf(i18n.Text("while an item tagged %s is equipped"), c.Qualifier)
+	case ToggleFeatureConditionType:
+		return i18n.Text("while toggled on")
+	default:
+		return ""
+	}
+}
+
+// FeatureActive returns true if the feature is currently active for the entity.
+func (e *Entity) FeatureActive(f Feature) bool {
+	if active, exists := e.featureConditions[f]; exists {
+		return active
+	}
+	return true
+}
+
+// updateFeatureConditions evaluates the activation condition of every conditional feature, returning true if any of
+// them changed since the last evaluation.
+func (e *Entity) updateFeatureConditions() bool {
+	m := make(map[Feature]bool)
+	e.walkFeatures(func(_ fmt.Stringer, f Feature, _ fxp.Int) {
+		if cf, ok := f.(ConditionalFeature); ok {
+			if c := cf.ActivationCondition(); c.Conditional() {
+				m[f] = c.Satisfied(e)
+			}
+		}
+	})
+	changed := len(m) != len(e.featureConditions)
+	if !changed {
+		for f, active := range m {
+			if prev, exists := e.featureConditions[f]; !exists || prev != active {
+				changed = true
+				break
+			}
+		}
+	}
+	e.featureConditions = m
+	e.DiscardCaches()
+	return changed
+}
+
+// ToggleableConditions returns the activation conditions with a manual toggle that belong to the features of the
+// trait, skill or equipment, including those of its enabled modifiers.
+func ToggleableConditions(data any) []*FeatureCondition {
+	var list []*FeatureCondition
+	collect := func(features Features) {
+		for _, f := range features {
+			if cf, ok := f.(ConditionalFeature); ok {
+				if c := cf.ActivationCondition(); c.Type == ToggleFeatureConditionType {
+					list = append(list, c)
+				}
+			}
+		}
+	}
+	switch item := data.(type) {
+	case *Trait:
+		collect(item.Features)
+		Traverse(func(mod *TraitModifier) bool {
+			collect(mod.Features)
+			return false
+		}, true, true, item.Modifiers...)
+	case *Skill:
+		collect(item.Features)
+	case *Equipment:
+		collect(item.Features)
+		Traverse(func(mod *EquipmentModifier) bool {
+			collect(mod.Features)
+			return false
+		}, true, true, item.Modifiers...)
+	}
+	return list
+}
@@ -0,0 +1,113 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	AlwaysFeatureConditionType FeatureConditionType = iota
+	ExpressionFeatureConditionType
+	EncumbranceFeatureConditionType
+	EquippedNameFeatureConditionType
+	EquippedTagFeatureConditionType
+	ToggleFeatureConditionType
+	LastFeatureConditionType = ToggleFeatureConditionType
+)
+
+// AllFeatureConditionType holds all possible values.
+var AllFeatureConditionType = []FeatureConditionType{
+	AlwaysFeatureConditionType,
+	ExpressionFeatureConditionType,
+	EncumbranceFeatureConditionType,
+	EquippedNameFeatureConditionType,
+	EquippedTagFeatureConditionType,
+	ToggleFeatureConditionType,
+}
+
+// FeatureConditionType holds the type of condition that controls whether a Feature is active.
+type FeatureConditionType byte
+
+// EnsureValid ensures this is of a known value.
+func (enum FeatureConditionType) EnsureValid() FeatureConditionType {
+	if enum <= LastFeatureConditionType {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum FeatureConditionType) Key() string {
+	switch enum {
+	case AlwaysFeatureConditionType:
+		return "always"
+	case ExpressionFeatureConditionType:
+		return "expression"
+	case EncumbranceFeatureConditionType:
+		return "encumbrance"
+	case EquippedNameFeatureConditionType:
+		return "equipped_name"
+	case EquippedTagFeatureConditionType:
+		return "equipped_tag"
+	case ToggleFeatureConditionType:
+		return "toggle"
+	default:
+		return FeatureConditionType(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum FeatureConditionType) String() string {
+	switch enum {
+	case AlwaysFeatureConditionType:
+		return i18n.Text("Always active")
+	case ExpressionFeatureConditionType:
+		return i18n.Text("Active while the expression is true")
+	case EncumbranceFeatureConditionType:
+		return i18n.Text("Active while encumbrance is between")
+	case EquippedNameFeatureConditionType:
+		return i18n.Text("Active while equipped with an item named")
+	case EquippedTagFeatureConditionType:
+		return i18n.Text("Active while equipped with an item tagged")
+	case ToggleFeatureConditionType:
+		return i18n.Text("Active while toggled on")
+	default:
+		return FeatureConditionType(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum FeatureConditionType) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *FeatureConditionType) UnmarshalText(text []byte) error {
+	*enum = ExtractFeatureConditionType(string(text))
+	return nil
+}
+
+// ExtractFeatureConditionType extracts the value from a string.
+func ExtractFeatureConditionType(str string) FeatureConditionType {
+	for _, enum := range AllFeatureConditionType {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
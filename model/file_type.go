@@ -31,6 +31,7 @@ const (
 	EquipmentExt          = ".eqp"
 	EquipmentModifiersExt = ".eqm"
 	NotesExt              = ".not"
+	RandomTableExt        = ".rtbl"
 	SheetExt              = ".gcs"
 	SkillsExt             = ".skl"
 	SpellsExt             = ".spl"
@@ -47,7 +48,9 @@ const (
 	AttributesExtAlt2  = ".gas"
 	BodyExt            = ".body"
 	BodyExtAlt         = ".ghl"
+	BundleExt          = ".gcsb"
 	CalendarExt        = ".calendar"
+	CampaignExt        = ".campaign"
 	ColorSettingsExt   = ".colors"
 	FontSettingsExt    = ".fonts"
 	GeneralSettingsExt = ".general"
@@ -129,7 +132,9 @@ func GCSSecondaryExtensions() []string {
 		AttributesExtAlt2,
 		BodyExt,
 		BodyExtAlt,
+		BundleExt,
 		CalendarExt,
+		CampaignExt,
 		ColorSettingsExt,
 		FontSettingsExt,
 		GeneralSettingsExt,
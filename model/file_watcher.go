@@ -0,0 +1,153 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/unison"
+	"github.com/rjeczalik/notify"
+	"golang.org/x/exp/slices"
+)
+
+const fileWatchSettleTime = 250 * time.Millisecond
+
+var (
+	fileWatchLock sync.Mutex
+	fileWatchDirs = make(map[string]*dirWatch)
+)
+
+type dirWatch struct {
+	dir     string
+	events  chan notify.EventInfo
+	tokens  []*FileWatchToken
+	pending bool
+}
+
+// FileSignature holds enough information about a file to detect when it has been changed.
+type FileSignature struct {
+	ModTime time.Time
+	Size    int64
+	Exists  bool
+}
+
+// NewFileSignature returns the signature of the file at the given path.
+func NewFileSignature(filePath string) FileSignature {
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return FileSignature{}
+	}
+	return FileSignature{
+		ModTime: fi.ModTime(),
+		Size:    fi.Size(),
+		Exists:  true,
+	}
+}
+
+// Equal returns true if the two signatures describe the same state of a file.
+func (s FileSignature) Equal(other FileSignature) bool {
+	return s.Exists == other.Exists && s.Size == other.Size && s.ModTime.Equal(other.ModTime)
+}
+
+// FileWatchToken holds a token that can be used to stop a file watch.
+type FileWatchToken struct {
+	filePath  string
+	callback  func(filePath string, exists bool)
+	signature FileSignature
+}
+
+// WatchFile watches the file at the given path for changes, calling the callback on the UI thread whenever its
+// signature changes. Returns nil if the file's directory cannot be watched.
+func WatchFile(filePath string, callback func(filePath string, exists bool)) *FileWatchToken {
+	token := &FileWatchToken{
+		filePath:  filePath,
+		callback:  callback,
+		signature: NewFileSignature(filePath),
+	}
+	dir := filepath.Dir(filePath)
+	fileWatchLock.Lock()
+	defer fileWatchLock.Unlock()
+	w, exists := fileWatchDirs[dir]
+	if !exists {
+		w = &dirWatch{
+			dir:    dir,
+			events: make(chan notify.EventInfo, 16),
+		}
+		if err := notify.Watch(dir, w.events, notify.All); err != nil {
+			jot.Error(errs.NewWithCausef(err, "unable to watch filesystem path: %s", dir))
+			return nil
+		}
+		fileWatchDirs[dir] = w
+		go w.listenForEvents()
+	}
+	w.tokens = append(w.tokens, token)
+	return token
+}
+
+func (w *dirWatch) listenForEvents() {
+	for range w.events {
+		fileWatchLock.Lock()
+		if !w.pending {
+			w.pending = true
+			unison.InvokeTaskAfter(w.check, fileWatchSettleTime)
+		}
+		fileWatchLock.Unlock()
+	}
+}
+
+func (w *dirWatch) check() {
+	fileWatchLock.Lock()
+	w.pending = false
+	tokens := slices.Clone(w.tokens)
+	fileWatchLock.Unlock()
+	for _, token := range tokens {
+		if sig := NewFileSignature(token.filePath); !sig.Equal(token.signature) {
+			token.signature = sig
+			token.callback(token.filePath, sig.Exists)
+		}
+	}
+}
+
+// Path returns the path of the file being watched.
+func (t *FileWatchToken) Path() string {
+	return t.filePath
+}
+
+// Acknowledge records the current state of the file as the known state, so that changes made by this application
+// (such as saving) are not reported.
+func (t *FileWatchToken) Acknowledge() {
+	t.signature = NewFileSignature(t.filePath)
+}
+
+// Stop this watch.
+func (t *FileWatchToken) Stop() {
+	fileWatchLock.Lock()
+	defer fileWatchLock.Unlock()
+	dir := filepath.Dir(t.filePath)
+	w, exists := fileWatchDirs[dir]
+	if !exists {
+		return
+	}
+	if i := slices.Index(w.tokens, t); i != -1 {
+		w.tokens = slices.Delete(w.tokens, i, i+1)
+	}
+	if len(w.tokens) == 0 {
+		delete(fileWatchDirs, dir)
+		notify.Stop(w.events)
+		close(w.events)
+	}
+}
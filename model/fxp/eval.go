@@ -35,7 +35,12 @@ func NewEvaluator(resolver eval.VariableResolver) *eval.Evaluator {
 
 // EvaluateToNumber evaluates the provided expression and returns a number.
 func EvaluateToNumber(expression string, resolver eval.VariableResolver) Int {
-	result, err := NewEvaluator(resolver).Evaluate(expression)
+	return EvaluateToNumberWith(NewEvaluator(resolver), expression)
+}
+
+// EvaluateToNumberWith evaluates the provided expression using the evaluator and returns a number.
+func EvaluateToNumberWith(evaluator *eval.Evaluator, expression string) Int {
+	result, err := evaluator.Evaluate(expression)
 	if err != nil {
 		if dbg.VariableResolver {
 			jot.Warn(errs.NewWithCausef(err, "unable to resolve '%s'", expression))
@@ -57,6 +57,7 @@ var (
 	Ten            = From(10)
 	Twelve         = From(12)
 	Fifteen        = From(15)
+	Sixteen        = From(16)
 	Nineteen       = From(19)
 	Twenty         = From(20)
 	TwentyFour     = From(24)
@@ -68,6 +69,7 @@ var (
 	Eighty         = From(80)
 	NinetyNine     = From(99)
 	Hundred        = From(100)
+	TwoHundred     = From(200)
 	Thousand       = From(1000)
 	MaxBasePoints  = From(999999)
 	Max            = Int(f64.Max)
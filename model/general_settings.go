@@ -55,6 +55,9 @@ const (
 	AutoColWidthMin            = 50
 	AutoColWidthMax            = 9999
 	MaximumAutoColWidthDef     = 800
+	AutosaveIntervalDef        = 2
+	AutosaveIntervalMin        = 1
+	AutosaveIntervalMax        = 60
 )
 
 // GeneralSettings holds general settings for a sheet.
@@ -74,6 +77,7 @@ type GeneralSettings struct {
 	MaximumAutoColWidth   int     `json:"maximum_auto_col_width"`
 	ImageResolution       int     `json:"image_resolution"`
 	MonitorResolution     int     `json:"monitor_resolution,omitempty"`
+	AutosaveInterval      int     `json:"autosave_interval"`
 	AutoFillProfile       bool    `json:"auto_fill_profile"`
 	AutoAddNaturalAttacks bool    `json:"add_natural_attacks"`
 	GroupContainersOnSort bool    `json:"group_containers_on_sort"`
@@ -94,6 +98,7 @@ func NewGeneralSettings() *GeneralSettings {
 		InitialSheetUIScale:   InitialSheetUIScaleDef,
 		MaximumAutoColWidth:   MaximumAutoColWidthDef,
 		ImageResolution:       ImageResolutionDef,
+		AutosaveInterval:      AutosaveIntervalDef,
 		AutoFillProfile:       true,
 		AutoAddNaturalAttacks: true,
 	}
@@ -156,5 +161,6 @@ func (s *GeneralSettings) EnsureValidity() {
 	s.InitialEditorUIScale = fxp.ResetIfOutOfRangeInt(s.InitialEditorUIScale, InitialUIScaleMin, InitialUIScaleMax, InitialEditorUIScaleDef)
 	s.InitialSheetUIScale = fxp.ResetIfOutOfRangeInt(s.InitialSheetUIScale, InitialUIScaleMin, InitialUIScaleMax, InitialSheetUIScaleDef)
 	s.MaximumAutoColWidth = fxp.ResetIfOutOfRangeInt(s.MaximumAutoColWidth, AutoColWidthMin, AutoColWidthMax, MaximumAutoColWidthDef)
+	s.AutosaveInterval = fxp.ResetIfOutOfRangeInt(s.AutosaveInterval, AutosaveIntervalMin, AutosaveIntervalMax, AutosaveIntervalDef)
 	s.UpdateToolTipTiming()
 }
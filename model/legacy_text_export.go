@@ -833,6 +833,24 @@ func (ex *legacyExporter) processTraitLoop(buffer []byte, f func(*Trait) bool) {
 					ex.handleStyleIndentWarning(t.Depth(), t.UnsatisfiedReason == "")
 				case satisfiedExportKey:
 					ex.handleSatisfied(t.UnsatisfiedReason == "")
+				case "ACTIVE":
+					if t.CanToggleActive() && !t.Inactive() {
+						ex.writeEncodedText("✓")
+					}
+				case "ACTIVE_NUM":
+					if t.Inactive() {
+						ex.writeEncodedText("0")
+					} else {
+						ex.writeEncodedText("1")
+					}
+				case "ACTIVATION_COST":
+					if t.Switchable && t.ActivationCost > 0 {
+						ex.writeEncodedText(t.ActivationCost.String())
+					}
+				case "ACTIVATION_POOL":
+					if t.Switchable && t.ActivationCost > 0 {
+						ex.writeEncodedText(t.ActivationPoolAttribute())
+					}
 				default:
 					switch {
 					case strings.HasPrefix(key, "DESCRIPTION_MODIFIER_NOTES"):
@@ -1070,6 +1088,12 @@ func (ex *legacyExporter) processEquipmentLoop(buffer []byte, carried bool) {
 					ex.writeEncodedText(ex.entity.SheetSettings.DefaultWeightUnits.Format(eqp.AdjustedWeight(false, ex.entity.SheetSettings.DefaultWeightUnits)))
 				case "COST_SUMMARY":
 					ex.writeEncodedText(eqp.ExtendedValue().String())
+				case "COST_FORMATTED":
+					ex.writeEncodedText(ex.entity.SheetSettings.FormatCurrency(eqp.AdjustedValue()))
+				case "COST_SUMMARY_FORMATTED":
+					ex.writeEncodedText(ex.entity.SheetSettings.FormatCurrency(eqp.ExtendedValue()))
+				case "CURRENCY":
+					ex.writeEncodedText(eqp.Currency)
 				case "WEIGHT_SUMMARY":
 					ex.writeEncodedText(ex.entity.SheetSettings.DefaultWeightUnits.Format(eqp.ExtendedWeight(false, ex.entity.SheetSettings.DefaultWeightUnits)))
 				case "WEIGHT_RAW":
@@ -1089,6 +1113,30 @@ func (ex *legacyExporter) processEquipmentLoop(buffer []byte, carried bool) {
 					ex.writeEncodedText(strconv.Itoa(eqp.Uses))
 				case "MAX_USES":
 					ex.writeEncodedText(strconv.Itoa(eqp.MaxUses))
+				case "VOLUME":
+					ex.writeEncodedText(eqp.Volume.String())
+				case "CONTAINED_WEIGHT":
+					ex.writeEncodedText(ex.entity.SheetSettings.DefaultWeightUnits.Format(eqp.ContainedWeight(ex.entity.SheetSettings.DefaultWeightUnits)))
+				case "MAX_CONTAINED_WEIGHT":
+					if eqp.MaxContainedWeight > 0 {
+						ex.writeEncodedText(ex.entity.SheetSettings.DefaultWeightUnits.Format(eqp.MaxContainedWeight))
+					}
+				case "CONTAINED_VOLUME":
+					ex.writeEncodedText(eqp.ContainedVolume().String())
+				case "MAX_CONTAINED_VOLUME":
+					if eqp.MaxContainedVolume > 0 {
+						ex.writeEncodedText(eqp.MaxContainedVolume.String())
+					}
+				case "CONTAINED_QTY":
+					ex.writeEncodedText(eqp.ContainedQuantity().String())
+				case "MAX_CONTAINED_QTY":
+					if eqp.MaxContainedQuantity > 0 {
+						ex.writeEncodedText(eqp.MaxContainedQuantity.String())
+					}
+				case "OVER_CAPACITY":
+					ex.handleSatisfied(eqp.CapacityWarning() != "")
+				case "CAPACITY_WARNING":
+					ex.writeEncodedText(eqp.CapacityWarning())
 				default:
 					switch {
 					case strings.HasPrefix(key, "DESCRIPTION_MODIFIER_NOTES"):
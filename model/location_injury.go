@@ -0,0 +1,94 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+)
+
+// LocationInjury holds the injury recorded against a hit location. Since a body type may have more than one location
+// with the same ID (e.g. left and right arms), the location is identified by its table name.
+type LocationInjury struct {
+	Location string  `json:"location"`
+	Injury   fxp.Int `json:"injury,omitempty"`
+	Crippled bool    `json:"crippled,omitempty"`
+	Notes    string  `json:"notes,omitempty"`
+}
+
+// Empty returns true if nothing has been recorded.
+func (l *LocationInjury) Empty() bool {
+	return l.Injury == 0 && !l.Crippled && strings.TrimSpace(l.Notes) == ""
+}
+
+// CripplingThreshold returns the amount of injury the location can take before being crippled, per p. B420. Returns
+// false if the location can't be crippled.
+func (h *HitLocation) CripplingThreshold(entity *Entity) (threshold fxp.Int, ok bool) {
+	var divisor fxp.Int
+	switch strings.ToLower(h.LocID) {
+	case "arm", "leg", "limb", "wing":
+		divisor = fxp.Two
+	case "hand", "foot", "extremity", "tail", "fin":
+		divisor = fxp.Three
+	case "eye":
+		divisor = fxp.Ten
+	default:
+		return 0, false
+	}
+	if entity == nil {
+		return 0, false
+	}
+	attr := entity.ResolveAttribute("hp")
+	if attr == nil {
+		return 0, false
+	}
+	return attr.Maximum().Div(divisor), true
+}
+
+// LocationInjury returns the injury recorded against the hit location, or nil.
+func (e *Entity) LocationInjury(location *HitLocation) *LocationInjury {
+	for _, one := range e.LocationInjuries {
+		if strings.EqualFold(one.Location, location.TableName) {
+			return one
+		}
+	}
+	return nil
+}
+
+// SetLocationInjury replaces the injury recorded against its hit location. An empty injury removes the record.
+func (e *Entity) SetLocationInjury(injury *LocationInjury) {
+	list := make([]*LocationInjury, 0, len(e.LocationInjuries)+1)
+	for _, one := range e.LocationInjuries {
+		if !strings.EqualFold(one.Location, injury.Location) {
+			list = append(list, one)
+		}
+	}
+	if !injury.Empty() {
+		list = append(list, injury)
+	}
+	e.LocationInjuries = list
+}
+
+// IsLocationCrippled returns true if the hit location has been marked as crippled or has taken more injury than its
+// crippling threshold.
+func (e *Entity) IsLocationCrippled(location *HitLocation) bool {
+	injury := e.LocationInjury(location)
+	if injury == nil {
+		return false
+	}
+	if injury.Crippled {
+		return true
+	}
+	threshold, ok := location.CripplingThreshold(e)
+	return ok && injury.Injury > threshold
+}
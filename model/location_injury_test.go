@@ -0,0 +1,61 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationInjury(t *testing.T) {
+	entity := model.NewEntity(model.PC)
+	hp := entity.ResolveAttribute("hp").Maximum()
+	var rightArm, leftArm, torso *model.HitLocation
+	for _, one := range entity.SheetSettings.BodyType.Locations {
+		switch one.TableName {
+		case "Right Arm":
+			rightArm = one
+		case "Left Arm":
+			leftArm = one
+		case "Torso":
+			torso = one
+		}
+	}
+	if !assert.NotNil(t, rightArm) || !assert.NotNil(t, leftArm) || !assert.NotNil(t, torso) {
+		return
+	}
+
+	threshold, ok := rightArm.CripplingThreshold(entity)
+	assert.True(t, ok)
+	assert.Equal(t, hp.Div(fxp.Two), threshold)
+	_, ok = torso.CripplingThreshold(entity)
+	assert.False(t, ok)
+
+	entity.SetLocationInjury(&model.LocationInjury{Location: rightArm.TableName, Injury: threshold})
+	assert.NotNil(t, entity.LocationInjury(rightArm))
+	assert.Nil(t, entity.LocationInjury(leftArm))
+	assert.False(t, entity.IsLocationCrippled(rightArm))
+
+	entity.SetLocationInjury(&model.LocationInjury{Location: rightArm.TableName, Injury: threshold + fxp.One})
+	assert.Len(t, entity.LocationInjuries, 1)
+	assert.True(t, entity.IsLocationCrippled(rightArm))
+
+	entity.SetLocationInjury(&model.LocationInjury{Location: torso.TableName, Crippled: true})
+	assert.True(t, entity.IsLocationCrippled(torso))
+
+	entity.SetLocationInjury(&model.LocationInjury{Location: rightArm.TableName})
+	assert.Nil(t, entity.LocationInjury(rightArm))
+	assert.Len(t, entity.LocationInjuries, 1)
+}
@@ -14,6 +14,7 @@ package model
 import (
 	"context"
 	"io/fs"
+	"sort"
 	"strings"
 	"unicode/utf8"
 
@@ -35,6 +36,7 @@ type NameGenerator struct {
 	min          int
 	max          int
 	entries      map[string][]nameGenCharThreshold
+	starts       []string
 }
 
 // NewNameGeneratorFromFS creates a new NameGenerator from a file.
@@ -80,18 +82,20 @@ func (n *NameGenerator) initializeIfNeeded() {
 				}
 			}
 			n.entries = make(map[string][]nameGenCharThreshold)
+			n.starts = make([]string, 0, len(builders))
 			for k, v := range builders {
 				n.entries[k] = n.makeCharThresholdEntry(v)
+				n.starts = append(n.starts, k)
 			}
+			sort.Strings(n.starts)
 		}
 		n.initialized = true
 	}
 }
 
-// Generate a name.
-func (n *NameGenerator) Generate() string {
+// Generate a name, drawing random choices from the randomizer.
+func (n *NameGenerator) Generate(rnd rand.Randomizer) string {
 	n.initializeIfNeeded()
-	rnd := rand.NewCryptoRand()
 	switch n.Type {
 	case SimpleNameGenerationType:
 		if len(n.TrainingData) == 0 {
@@ -99,27 +103,25 @@ func (n *NameGenerator) Generate() string {
 		}
 		return txt.FirstToUpper(n.TrainingData[rnd.Intn(len(n.TrainingData))])
 	case MarkovChainNameGenerationType:
-		var buffer strings.Builder
-		var sub []rune
-		for k := range n.entries {
-			buffer.WriteString(txt.FirstToUpper(k))
-			sub = []rune(k)
-			break // Only want one, which is random
+		if len(n.starts) == 0 {
+			return ""
 		}
+		var buffer strings.Builder
+		start := n.starts[rnd.Intn(len(n.starts))]
+		buffer.WriteString(txt.FirstToUpper(start))
+		sub := []rune(start)
 		targetSize := n.min + rnd.Intn(n.max+1-n.min)
 		for i := 2; i < targetSize; i++ {
 			entry, exists := n.entries[string(sub)]
 			if !exists {
 				break
 			}
-			next := n.chooseCharacter(entry)
+			next := n.chooseCharacter(rnd, entry)
 			if next == 0 {
 				break
 			}
 			buffer.WriteRune(next)
-			//goland:noinspection GoNilness
 			sub[0] = sub[1]
-			//goland:noinspection GoNilness
 			sub[1] = next
 		}
 		return buffer.String()
@@ -129,21 +131,22 @@ func (n *NameGenerator) Generate() string {
 }
 
 func (n *NameGenerator) makeCharThresholdEntry(occurrences map[rune]int) []nameGenCharThreshold {
-	ct := make([]nameGenCharThreshold, len(occurrences))
-	i := 0
-	for k, v := range occurrences {
-		ct[i].ch = k
+	ct := make([]nameGenCharThreshold, 0, len(occurrences))
+	for k := range occurrences {
+		ct = append(ct, nameGenCharThreshold{ch: k})
+	}
+	sort.Slice(ct, func(i, j int) bool { return ct[i].ch < ct[j].ch })
+	for i := range ct {
+		ct[i].threshold = occurrences[ct[i].ch]
 		if i > 0 {
-			v += ct[i-1].threshold
+			ct[i].threshold += ct[i-1].threshold
 		}
-		ct[i].threshold = v
-		i++
 	}
 	return ct
 }
 
-func (n *NameGenerator) chooseCharacter(ct []nameGenCharThreshold) rune {
-	threshold := rand.NewCryptoRand().Intn(ct[len(ct)-1].threshold + 1)
+func (n *NameGenerator) chooseCharacter(rnd rand.Randomizer, ct []nameGenCharThreshold) rune {
+	threshold := rnd.Intn(ct[len(ct)-1].threshold + 1)
 	for i := range ct {
 		if ct[i].threshold >= threshold {
 			return ct[i].ch
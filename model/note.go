@@ -13,6 +13,7 @@ package model
 
 import (
 	"context"
+	"io"
 	"io/fs"
 
 	"github.com/richardwilkes/gcs/v5/model/jio"
@@ -69,6 +70,15 @@ func SaveNotes(notes []*Note, filePath string) error {
 	})
 }
 
+// SaveNotesTo writes the Note list to the writer as JSON.
+func SaveNotesTo(notes []*Note, w io.Writer) error {
+	return jio.Save(context.Background(), w, &noteListData{
+		Type:    noteListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    notes,
+	})
+}
+
 // NewNote creates a new Note.
 func NewNote(entity *Entity, parent *Note, container bool) *Note {
 	n := &Note{
@@ -0,0 +1,250 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	mathrand "math/rand"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath/rand"
+)
+
+const (
+	npcAttemptsPerCharacter = 25
+	npcPickerAttempts       = 100
+)
+
+// NPCGenerator holds the parameters used to generate random non-player characters.
+type NPCGenerator struct {
+	Ancestry  string
+	Templates []*Template
+	Points    fxp.Int
+	Count     int
+	Seed      int64
+}
+
+// Generate the NPCs. The same parameters and seed will always produce the same results. Template choices are resolved
+// randomly within their constraints, preferring combinations that fit within the point budget.
+func (g *NPCGenerator) Generate() ([]*Entity, error) {
+	if g.Count < 1 {
+		return nil, nil
+	}
+	rnd := mathrand.New(mathrand.NewSource(g.Seed)) //nolint:gosec // Reproducibility is the point here
+	list := make([]*Entity, 0, g.Count)
+	for i := 0; i < g.Count; i++ {
+		entity, err := g.generateOne(rnd)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, entity)
+	}
+	return list, nil
+}
+
+func (g *NPCGenerator) generateOne(rnd rand.Randomizer) (*Entity, error) {
+	var best *Entity
+	var bestOverage fxp.Int
+	for attempt := 0; attempt < npcAttemptsPerCharacter; attempt++ {
+		entity, err := g.build(rnd)
+		if err != nil {
+			return nil, err
+		}
+		overage := -entity.UnspentPoints()
+		if overage <= 0 || g.Points <= 0 {
+			return entity, nil
+		}
+		if best == nil || overage < bestOverage {
+			best = entity
+			bestOverage = overage
+		}
+	}
+	return best, nil
+}
+
+func (g *NPCGenerator) build(rnd rand.Randomizer) (*Entity, error) {
+	entity := NewEntity(PC)
+	if g.Points > 0 {
+		entity.TotalPoints = g.Points
+		entity.PointsRecord[0].Points = g.Points
+	}
+	for _, t := range g.Templates {
+		traits, err := resolveTemplatePickers(rnd, cloneNodesForEntity(entity, t.Traits))
+		if err != nil {
+			return nil, err
+		}
+		var skills []*Skill
+		if skills, err = resolveTemplatePickers(rnd, cloneNodesForEntity(entity, t.Skills)); err != nil {
+			return nil, err
+		}
+		var spells []*Spell
+		if spells, err = resolveTemplatePickers(rnd, cloneNodesForEntity(entity, t.Spells)); err != nil {
+			return nil, err
+		}
+		entity.Traits = append(entity.Traits, traits...)
+		entity.Skills = append(entity.Skills, skills...)
+		entity.Spells = append(entity.Spells, spells...)
+		entity.CarriedEquipment = append(entity.CarriedEquipment, cloneNodesForEntity(entity, t.Equipment)...)
+		entity.Notes = append(entity.Notes, cloneNodesForEntity(entity, t.Notes)...)
+	}
+	if g.Ancestry != "" {
+		g.ensureAncestry(entity)
+	}
+	entity.Recalculate()
+	entity.Profile.AutoFill(entity, rnd)
+	entity.Recalculate()
+	return entity, nil
+}
+
+func (g *NPCGenerator) ensureAncestry(entity *Entity) {
+	found := false
+	Traverse(func(t *Trait) bool {
+		if t.Container() && t.ContainerType == RaceContainerType {
+			found = true
+			if t.Ancestry == "" {
+				t.Ancestry = g.Ancestry
+			}
+			return true
+		}
+		return false
+	}, true, false, entity.Traits...)
+	if !found {
+		t := NewTrait(entity, nil, true)
+		t.ContainerType = RaceContainerType
+		t.Name = g.Ancestry
+		t.Ancestry = g.Ancestry
+		entity.Traits = append([]*Trait{t}, entity.Traits...)
+	}
+}
+
+// cloneNodesForEntity creates clones of the provided nodes, owned by the entity.
+func cloneNodesForEntity[T NodeTypes](entity *Entity, nodes []T) []T {
+	list := make([]T, 0, len(nodes))
+	var zero T
+	for _, one := range nodes {
+		list = append(list, AsNode(one).Clone(entity, zero, false))
+	}
+	return list
+}
+
+func resolveTemplatePickers[T NodeTypes](rnd rand.Randomizer, nodes []T) ([]T, error) {
+	var zero T
+	revised := make([]T, 0, len(nodes))
+	for _, one := range nodes {
+		result, err := resolveTemplatePicker(rnd, one)
+		if err != nil {
+			return nil, err
+		}
+		for _, replacement := range result {
+			AsNode(replacement).SetParent(zero)
+		}
+		revised = append(revised, result...)
+	}
+	return revised, nil
+}
+
+func resolveTemplatePicker[T NodeTypes](rnd rand.Randomizer, node T) ([]T, error) {
+	n := AsNode(node)
+	if !n.Container() {
+		return []T{node}, nil
+	}
+	children := n.NodeChildren()
+	tpp, ok := n.(TemplatePickerProvider)
+	if !ok || tpp.TemplatePickerData().ShouldOmit() {
+		rowChildren := make([]T, 0, len(children))
+		for _, child := range children {
+			result, err := resolveTemplatePicker(rnd, child)
+			if err != nil {
+				return nil, err
+			}
+			rowChildren = append(rowChildren, result...)
+		}
+		for _, child := range rowChildren {
+			AsNode(child).SetParent(node)
+		}
+		n.SetChildren(rowChildren)
+		return []T{node}, nil
+	}
+	picked := pickTemplateChoices(rnd, tpp.TemplatePickerData(), children)
+	if picked == nil {
+		return nil, errs.Newf(i18n.Text("unable to satisfy the choice \"%s\" of %v"),
+			tpp.TemplatePickerData().Description(), node)
+	}
+	rowChildren := make([]T, 0, len(picked))
+	for _, child := range picked {
+		result, err := resolveTemplatePicker(rnd, child)
+		if err != nil {
+			return nil, err
+		}
+		rowChildren = append(rowChildren, result...)
+	}
+	parent := n.Parent()
+	for _, child := range rowChildren {
+		AsNode(child).SetParent(parent)
+	}
+	return rowChildren, nil
+}
+
+// pickTemplateChoices returns a random selection of the choices that satisfies the picker, or nil if no selection
+// could be found.
+func pickTemplateChoices[T NodeTypes](rnd rand.Randomizer, tp *TemplatePicker, choices []T) []T {
+	switch tp.Type {
+	case CountTemplatePickerType:
+		var counts []int
+		for i := 1; i <= len(choices); i++ {
+			if tp.Qualifier.Matches(fxp.From(i)) {
+				counts = append(counts, i)
+			}
+		}
+		if len(counts) == 0 {
+			if tp.Qualifier.Matches(0) {
+				return []T{}
+			}
+			return nil
+		}
+		return shuffledTemplateChoices(rnd, choices)[:counts[rnd.Intn(len(counts))]]
+	case PointsTemplatePickerType:
+		upperBounded := tp.Qualifier.Compare == EqualsNumber || tp.Qualifier.Compare == AtMostNumber
+		for attempt := 0; attempt < npcPickerAttempts; attempt++ {
+			var total fxp.Int
+			picked := make([]T, 0, len(choices))
+			for _, choice := range shuffledTemplateChoices(rnd, choices) {
+				points := TemplatePickerPoints(choice)
+				if upperBounded && total+points > tp.Qualifier.Qualifier {
+					continue
+				}
+				total += points
+				picked = append(picked, choice)
+				if tp.Qualifier.Compare != AtMostNumber && tp.Qualifier.Matches(total) {
+					break
+				}
+			}
+			if tp.Qualifier.Matches(total) {
+				return picked
+			}
+		}
+		return nil
+	default:
+		return choices
+	}
+}
+
+func shuffledTemplateChoices[T NodeTypes](rnd rand.Randomizer, choices []T) []T {
+	list := make([]T, len(choices))
+	copy(list, choices)
+	for i := len(list) - 1; i > 0; i-- {
+		j := rnd.Intn(i + 1)
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNPCGenerator(t *testing.T) {
+	tmpl := model.NewTemplate()
+	choice := model.NewTrait(nil, nil, true)
+	choice.TemplatePicker.Type = model.CountTemplatePickerType
+	choice.TemplatePicker.Qualifier.Compare = model.EqualsNumber
+	choice.TemplatePicker.Qualifier.Qualifier = fxp.Two
+	for i := 0; i < 6; i++ {
+		trait := model.NewTrait(nil, choice, false)
+		trait.Name = fmt.Sprintf("Trait %d", i)
+		trait.BasePoints = fxp.Five
+		choice.Children = append(choice.Children, trait)
+	}
+	tmpl.Traits = append(tmpl.Traits, choice)
+
+	g := &model.NPCGenerator{
+		Templates: []*model.Template{tmpl},
+		Points:    fxp.From(50),
+		Count:     3,
+		Seed:      42,
+	}
+	first, err := g.Generate()
+	assert.NoError(t, err)
+	assert.Len(t, first, 3)
+	second, err := g.Generate()
+	assert.NoError(t, err)
+	for i, entity := range first {
+		assert.Equal(t, fxp.From(50), entity.TotalPoints)
+		picked := 0
+		for _, trait := range entity.Traits {
+			if strings.HasPrefix(trait.Name, "Trait ") {
+				picked++
+			}
+		}
+		assert.Equal(t, 2, picked)
+		assert.Equal(t, entity.Profile.Name, second[i].Profile.Name)
+		for j, trait := range entity.Traits {
+			assert.Equal(t, trait.Name, second[i].Traits[j].Name)
+		}
+	}
+}
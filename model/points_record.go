@@ -18,9 +18,10 @@ import (
 
 // PointsRecord holds information about when and why points were adjusted.
 type PointsRecord struct {
-	When   jio.Time `json:"when"`
-	Points fxp.Int  `json:"points"`
-	Reason string   `json:"reason,omitempty"`
+	When     jio.Time `json:"when"`
+	GameDate string   `json:"game_date,omitempty"`
+	Points   fxp.Int  `json:"points"`
+	Reason   string   `json:"reason,omitempty"`
 }
 
 // ClonePointsRecordList creates a clone of the provided PointsRecord list.
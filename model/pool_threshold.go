@@ -17,6 +17,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/json"
 	"github.com/richardwilkes/toolbox/eval"
+	"github.com/richardwilkes/toolbox/txt"
 	"golang.org/x/exp/slices"
 )
 
@@ -28,10 +29,13 @@ type PoolThreshold struct {
 
 // PoolThresholdData holds the data that will be serialized for the PoolThreshold.
 type PoolThresholdData struct {
-	State       string        `json:"state"`
-	Expression  string        `json:"expression"`
-	Explanation string        `json:"explanation,omitempty"`
-	Ops         []ThresholdOp `json:"ops,omitempty"`
+	State        string        `json:"state"`
+	Expression   string        `json:"expression"`
+	Explanation  string        `json:"explanation,omitempty"`
+	Ops          []ThresholdOp `json:"ops,omitempty"`
+	Features     Features      `json:"features,omitempty"`
+	RequiredRoll string        `json:"required_roll,omitempty"`
+	DisabledTags []string      `json:"disabled_tags,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -107,6 +111,8 @@ func (p *PoolThreshold) Clone() *PoolThreshold {
 		clone.Ops = make([]ThresholdOp, len(p.Ops))
 		copy(clone.Ops, p.Ops)
 	}
+	clone.Features = p.Features.Clone()
+	clone.DisabledTags = txt.CloneStringSlice(p.DisabledTags)
 	return &clone
 }
 
@@ -115,6 +121,16 @@ func (p *PoolThreshold) Threshold(resolver eval.VariableResolver) fxp.Int {
 	return fxp.EvaluateToNumber(p.Expression, resolver)
 }
 
+// DisablesTrait returns true if this PoolThreshold disables traits with any of the given tags.
+func (p *PoolThreshold) DisablesTrait(tags []string) bool {
+	for _, one := range p.DisabledTags {
+		if HasTag(one, tags) {
+			return true
+		}
+	}
+	return false
+}
+
 // ContainsOp returns true if this PoolThreshold contains the specified ThresholdOp.
 func (p *PoolThreshold) ContainsOp(op ThresholdOp) bool {
 	return slices.Contains(p.Ops, op)
@@ -143,9 +159,64 @@ func (p *PoolThreshold) crc64(c uint64) uint64 {
 	for _, one := range p.Ops {
 		c = CRCByte(c, byte(one))
 	}
+	if len(p.Features) != 0 {
+		if data, err := json.Marshal(p.Features); err == nil {
+			c = CRCBytes(c, data)
+		}
+	}
+	c = CRCString(c, p.RequiredRoll)
+	c = CRCNumber(c, len(p.DisabledTags))
+	for _, one := range p.DisabledTags {
+		c = CRCString(c, one)
+	}
 	return c
 }
 
 func (p *PoolThreshold) String() string {
 	return p.State
 }
+
+type activeThreshold struct {
+	def       *AttributeDef
+	threshold *PoolThreshold
+}
+
+func (a *activeThreshold) String() string {
+	return a.def.Name + " [" + a.threshold.State + "]"
+}
+
+// updateActiveThresholds records the current threshold of each pool, so that their effects can be applied, returning
+// true if any of them changed since the last update.
+func (e *Entity) updateActiveThresholds() bool {
+	prev := e.activeThresholds
+	e.activeThresholds = nil
+	for _, attr := range e.Attributes.List() {
+		if threshold := attr.CurrentThreshold(); threshold != nil {
+			if def := attr.AttributeDef(); def != nil && def.Type == PoolAttributeType {
+				e.activeThresholds = append(e.activeThresholds, &activeThreshold{def: def, threshold: threshold})
+			}
+		}
+	}
+	if len(prev) != len(e.activeThresholds) {
+		return true
+	}
+	for i, one := range e.activeThresholds {
+		if prev[i].def != one.def || prev[i].threshold != one.threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// TraitTagsDisabledByThreshold returns true if a current pool threshold disables traits with any of the given tags.
+func (e *Entity) TraitTagsDisabledByThreshold(tags []string) bool {
+	if len(tags) == 0 {
+		return false
+	}
+	for _, one := range e.activeThresholds {
+		if one.threshold.DisablesTrait(tags) {
+			return true
+		}
+	}
+	return false
+}
@@ -30,7 +30,8 @@ const (
 	EquippedEquipmentPrereqType
 	SkillPrereqType
 	SpellPrereqType
-	LastPrereqType = SpellPrereqType
+	ExpressionPrereqType
+	LastPrereqType = ExpressionPrereqType
 )
 
 // AllPrereqType holds all possible values.
@@ -43,6 +44,7 @@ var AllPrereqType = []PrereqType{
 	EquippedEquipmentPrereqType,
 	SkillPrereqType,
 	SpellPrereqType,
+	ExpressionPrereqType,
 }
 
 // PrereqType holds the type of a Prereq.
@@ -75,6 +77,8 @@ func (enum PrereqType) Key() string {
 		return "skill_prereq"
 	case SpellPrereqType:
 		return "spell_prereq"
+	case ExpressionPrereqType:
+		return "expression_prereq"
 	default:
 		return PrereqType(0).Key()
 	}
@@ -98,6 +102,8 @@ func (enum PrereqType) oldKeys() []string {
 		return nil
 	case SpellPrereqType:
 		return nil
+	case ExpressionPrereqType:
+		return nil
 	default:
 		return PrereqType(0).oldKeys()
 	}
@@ -122,6 +128,8 @@ func (enum PrereqType) String() string {
 		return i18n.Text("a skill")
 	case SpellPrereqType:
 		return i18n.Text("spell(s)")
+	case ExpressionPrereqType:
+		return i18n.Text("satisfies the expression")
 	default:
 		return PrereqType(0).String()
 	}
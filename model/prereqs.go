@@ -52,6 +52,8 @@ func (p *Prereqs) UnmarshalJSON(data []byte) error {
 			pr = &SkillPrereq{}
 		case SpellPrereqType:
 			pr = &SpellPrereq{}
+		case ExpressionPrereqType:
+			pr = &ExpressionPrereq{}
 		default:
 			return errs.Newf(i18n.Text("Unknown prerequisite type: %s"), typeData.Type)
 		}
@@ -17,6 +17,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/toolbox/errs"
 	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 	"github.com/richardwilkes/unison"
 )
 
@@ -81,20 +82,20 @@ func (p *Profile) SetAdjustedSizeModifier(value int) {
 }
 
 // AutoFill fills in the default profile entries.
-func (p *Profile) AutoFill(entity *Entity) {
+func (p *Profile) AutoFill(entity *Entity, rnd rand.Randomizer) {
 	globalSettings := GlobalSettings()
 	generalSettings := globalSettings.GeneralSettings()
-	p.TechLevel = generalSettings.DefaultTechLevel
+	p.TechLevel = globalSettings.DefaultTechLevelFor(entity)
 	p.PlayerName = generalSettings.DefaultPlayerName
 	a := entity.Ancestry()
-	p.Gender = a.RandomGender("")
-	p.Age = strconv.Itoa(a.RandomAge(entity, p.Gender, 0))
-	p.Eyes = a.RandomEyes(p.Gender, "")
-	p.Hair = a.RandomHair(p.Gender, "")
-	p.Skin = a.RandomSkin(p.Gender, "")
-	p.Handedness = a.RandomHandedness(p.Gender, "")
-	p.Height = a.RandomHeight(entity, p.Gender, 0)
-	p.Weight = a.RandomWeight(entity, p.Gender, 0)
-	p.Name = a.RandomName(AvailableNameGenerators(globalSettings.Libraries()), p.Gender)
-	p.Birthday = generalSettings.CalendarRef(globalSettings.Libraries()).RandomBirthday(p.Birthday)
+	p.Gender = a.RandomGender(rnd, "")
+	p.Age = strconv.Itoa(a.RandomAge(rnd, entity, p.Gender, 0))
+	p.Eyes = a.RandomEyes(rnd, p.Gender, "")
+	p.Hair = a.RandomHair(rnd, p.Gender, "")
+	p.Skin = a.RandomSkin(rnd, p.Gender, "")
+	p.Handedness = a.RandomHandedness(rnd, p.Gender, "")
+	p.Height = a.RandomHeight(rnd, entity, p.Gender, 0)
+	p.Weight = a.RandomWeight(rnd, entity, p.Gender, 0)
+	p.Name = a.RandomName(rnd, AvailableNameGenerators(globalSettings.Libraries()), p.Gender)
+	p.Birthday = globalSettings.CalendarRefFor(entity).RandomBirthday(rnd, p.Birthday)
 }
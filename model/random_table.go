@@ -0,0 +1,464 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/rpgtools/dice"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/toolbox/xmath/rand"
+)
+
+const (
+	randomTableTypeKey            = "random_table"
+	randomTableBuiltInDir         = "embedded_data"
+	randomTableResultSeparator    = " → "
+	randomTableMaxSubTableNesting = 16
+)
+
+var randomTableLookup struct {
+	lock       sync.RWMutex
+	generation int
+	refs       map[string]*NamedFileRef
+	tables     map[string]*RandomTable
+}
+
+// RandomTable holds a dice roll and the entries that the results of that roll map to.
+type RandomTable struct {
+	Name        string              `json:"name,omitempty"`
+	Roll        *dice.Dice          `json:"roll"`
+	Entries     []*RandomTableEntry `json:"entries,omitempty"`
+	KeyPrefix   string              `json:"-"`
+	owningEntry *RandomTableEntry
+}
+
+// RandomTableEntry holds a single entry within a RandomTable.
+type RandomTableEntry struct {
+	Slots       int          `json:"slots,omitempty"`
+	Text        string       `json:"text,omitempty"`
+	Value       fxp.Int      `json:"value,omitempty"`
+	Modifier    int          `json:"modifier,omitempty"`
+	SubTable    *RandomTable `json:"sub_table,omitempty"`
+	KeyPrefix   string       `json:"-"`
+	RollRange   string       `json:"-"`
+	owningTable *RandomTable
+	first       int
+}
+
+// RandomTableResult holds the result of rolling on a RandomTable.
+type RandomTableResult struct {
+	Table *RandomTable
+	Entry *RandomTableEntry
+	Total int
+	Sub   *RandomTableResult
+}
+
+type randomTableData struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	*RandomTable
+}
+
+// NewRandomTable creates a new RandomTable with a single entry.
+func NewRandomTable() *RandomTable {
+	t := &RandomTable{Roll: dice.New("3d")}
+	t.AddEntry(&RandomTableEntry{Slots: 16})
+	t.Update()
+	return t
+}
+
+// NewRandomTableFromFile loads a RandomTable from a file.
+func NewRandomTableFromFile(fileSystem fs.FS, filePath string) (*RandomTable, error) {
+	var data randomTableData
+	if err := jio.LoadFromFS(context.Background(), fileSystem, filePath, &data); err != nil {
+		return nil, errs.NewWithCause(invalidFileDataMsg(), err)
+	}
+	if data.Type != randomTableTypeKey || data.RandomTable == nil {
+		return nil, errs.New(unexpectedFileDataMsg())
+	}
+	if err := CheckVersion(data.Version); err != nil {
+		return nil, err
+	}
+	if data.Roll == nil {
+		data.Roll = dice.New("3d")
+	}
+	data.RandomTable.Update()
+	return data.RandomTable, nil
+}
+
+// AvailableRandomTables scans the libraries and returns the available random tables. Unlike most other named files,
+// random tables may be placed anywhere within a library.
+func AvailableRandomTables(libraries Libraries) []*NamedFileSet {
+	return scanForAvailableRandomTables(randomTableLibraryDirs(libraries))
+}
+
+type randomTableLibraryDir struct {
+	title string
+	path  string
+}
+
+func randomTableLibraryDirs(libraries Libraries) []randomTableLibraryDir {
+	libs := libraries.List()
+	dirs := make([]randomTableLibraryDir, 0, len(libs))
+	for _, lib := range libs {
+		dirs = append(dirs, randomTableLibraryDir{title: lib.Title, path: lib.Path()})
+	}
+	return dirs
+}
+
+func scanForAvailableRandomTables(dirs []randomTableLibraryDir) []*NamedFileSet {
+	set := make(map[string]bool)
+	list := make([]*NamedFileSet, 0)
+	for _, dir := range dirs {
+		if refs := scanForRandomTables(os.DirFS(dir.path), ".", set); len(refs) != 0 {
+			list = append(list, &NamedFileSet{
+				Name: dir.title,
+				List: refs,
+			})
+		}
+	}
+	if refs := scanForRandomTables(embeddedFS, randomTableBuiltInDir, set); len(refs) != 0 {
+		list = append(list, &NamedFileSet{
+			Name: i18n.Text("Built-in"),
+			List: refs,
+		})
+	}
+	return list
+}
+
+// BuiltInRandomTables returns the random tables that ship with GCS.
+func BuiltInRandomTables() []*NamedFileRef {
+	return scanForRandomTables(embeddedFS, randomTableBuiltInDir, make(map[string]bool))
+}
+
+func scanForRandomTables(fileSystem fs.FS, dirPath string, set map[string]bool) []*NamedFileRef {
+	list := make([]*NamedFileRef, 0)
+	_ = fs.WalkDir(fileSystem, dirPath, func(p string, d fs.DirEntry, err error) error { //nolint:errcheck // Intentionally ignored the error result
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if p != dirPath && strings.HasPrefix(name, ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && strings.EqualFold(path.Ext(name), RandomTableExt) {
+			shortName := xfs.TrimExtension(name)
+			if key := strings.ToLower(shortName); !set[key] {
+				set[key] = true
+				list = append(list, &NamedFileRef{
+					Name:       shortName,
+					FileSystem: fileSystem,
+					FilePath:   p,
+				})
+			}
+		}
+		return nil
+	})
+	return list
+}
+
+// LookupRandomTable returns the random table with the given name, or nil if it cannot be found. Tables within the
+// libraries take precedence over the built-in tables. The returned table is shared and must not be modified.
+func LookupRandomTable(name string, libraries Libraries) *RandomTable {
+	key := strings.ToLower(strings.TrimSpace(name))
+	randomTableLookup.lock.RLock()
+	indexed := randomTableLookup.refs != nil
+	t, cached := randomTableLookup.tables[key]
+	ref, exists := randomTableLookup.refs[key]
+	generation := randomTableLookup.generation
+	randomTableLookup.lock.RUnlock()
+	if !indexed {
+		setRandomTableLookups(scanForAvailableRandomTables(randomTableLibraryDirs(libraries)))
+		return LookupRandomTable(name, libraries)
+	}
+	if cached {
+		return t
+	}
+	if !exists {
+		return nil
+	}
+	var err error
+	if t, err = NewRandomTableFromFile(ref.FileSystem, ref.FilePath); err != nil {
+		jot.Warn(err)
+		t = nil
+	}
+	randomTableLookup.lock.Lock()
+	if generation == randomTableLookup.generation {
+		randomTableLookup.tables[key] = t
+	}
+	randomTableLookup.lock.Unlock()
+	return t
+}
+
+// RefreshRandomTableLookups discards any cached random tables and rebuilds the index used by LookupRandomTable in the
+// background. Should be called whenever the libraries or their contents change.
+func RefreshRandomTableLookups(libraries Libraries) {
+	dirs := randomTableLibraryDirs(libraries)
+	go setRandomTableLookups(scanForAvailableRandomTables(dirs))
+}
+
+func setRandomTableLookups(sets []*NamedFileSet) {
+	refs := make(map[string]*NamedFileRef)
+	for _, set := range sets {
+		for _, one := range set.List {
+			refs[strings.ToLower(one.Name)] = one
+		}
+	}
+	randomTableLookup.lock.Lock()
+	randomTableLookup.generation++
+	randomTableLookup.refs = refs
+	randomTableLookup.tables = make(map[string]*RandomTable)
+	randomTableLookup.lock.Unlock()
+}
+
+// Save writes the RandomTable to the file as JSON.
+func (t *RandomTable) Save(filePath string) error {
+	return jio.SaveToFile(context.Background(), filePath, &randomTableData{
+		Type:        randomTableTypeKey,
+		Version:     CurrentDataVersion,
+		RandomTable: t,
+	})
+}
+
+// SaveTo writes the RandomTable to the writer as JSON.
+func (t *RandomTable) SaveTo(w io.Writer) error {
+	return jio.Save(context.Background(), w, &randomTableData{
+		Type:        randomTableTypeKey,
+		Version:     CurrentDataVersion,
+		RandomTable: t,
+	})
+}
+
+// Clone a copy of this.
+func (t *RandomTable) Clone(owningEntry *RandomTableEntry) *RandomTable {
+	clone := &RandomTable{
+		Name:        t.Name,
+		Roll:        dice.New(t.Roll.String()),
+		Entries:     make([]*RandomTableEntry, len(t.Entries)),
+		KeyPrefix:   t.KeyPrefix,
+		owningEntry: owningEntry,
+	}
+	for i, one := range t.Entries {
+		clone.Entries[i] = one.Clone(clone)
+	}
+	clone.Update()
+	return clone
+}
+
+// Update the roll ranges and owner references.
+func (t *RandomTable) Update() {
+	start := t.Roll.Minimum(false)
+	for _, one := range t.Entries {
+		one.owningTable = t
+		start = one.updateRollRange(start)
+		if one.SubTable != nil {
+			one.SubTable.owningEntry = one
+			one.SubTable.Update()
+		}
+	}
+}
+
+// OwningEntry returns the owning entry, or nil if this is the top-level table.
+func (t *RandomTable) OwningEntry() *RandomTableEntry {
+	return t.owningEntry
+}
+
+// AddEntry adds an entry to the end of list.
+func (t *RandomTable) AddEntry(entry *RandomTableEntry) {
+	t.Entries = append(t.Entries, entry)
+	entry.owningTable = t
+}
+
+// RemoveEntry removes an entry.
+func (t *RandomTable) RemoveEntry(entry *RandomTableEntry) {
+	for i, one := range t.Entries {
+		if one == entry {
+			copy(t.Entries[i:], t.Entries[i+1:])
+			t.Entries[len(t.Entries)-1] = nil
+			t.Entries = t.Entries[:len(t.Entries)-1]
+			entry.owningTable = nil
+			break
+		}
+	}
+}
+
+// Lookup returns the entry that covers the given total. Totals that fall before the first entry or after the last are
+// treated as if they had rolled the first or last entry, respectively, so that modified rolls always produce a result.
+// Returns nil if the table has no entries with slots.
+func (t *RandomTable) Lookup(total int) *RandomTableEntry {
+	var first, last *RandomTableEntry
+	for _, one := range t.Entries {
+		if one.Slots < 1 {
+			continue
+		}
+		if first == nil {
+			first = one
+		}
+		last = one
+		if total >= one.first && total < one.first+one.Slots {
+			return one
+		}
+	}
+	if first != nil && total < first.first {
+		return first
+	}
+	return last
+}
+
+// RollWithModifier rolls the dice, adds the modifier, and returns the result of the lookup. Sub-tables are rolled as
+// needed.
+func (t *RandomTable) RollWithModifier(modifier int) *RandomTableResult {
+	return t.RollWithRandomizer(rand.NewCryptoRand(), modifier)
+}
+
+// RollWithRandomizer is the same as RollWithModifier, but draws the rolls from the provided randomizer.
+func (t *RandomTable) RollWithRandomizer(rnd rand.Randomizer, modifier int) *RandomTableResult {
+	return t.rollAt(rnd, t.Roll.RollWithRandomizer(rnd, false)+modifier, 0)
+}
+
+// Result returns the result for the given total, rolling on any sub-tables as needed.
+func (t *RandomTable) Result(total int) *RandomTableResult {
+	return t.rollAt(rand.NewCryptoRand(), total, 0)
+}
+
+func (t *RandomTable) rollAt(rnd rand.Randomizer, total, depth int) *RandomTableResult {
+	result := &RandomTableResult{
+		Table: t,
+		Entry: t.Lookup(total),
+		Total: total,
+	}
+	if result.Entry != nil && result.Entry.SubTable != nil && depth < randomTableMaxSubTableNesting {
+		sub := result.Entry.SubTable
+		result.Sub = sub.rollAt(rnd, sub.Roll.RollWithRandomizer(rnd, false)+result.Entry.Modifier, depth+1)
+	}
+	return result
+}
+
+// CRC64 computes a CRC-64 value for the content of this table.
+func (t *RandomTable) CRC64() uint64 {
+	return t.crc64(0)
+}
+
+func (t *RandomTable) crc64(c uint64) uint64 {
+	c = CRCString(c, t.Name)
+	c = CRCString(c, t.Roll.String())
+	c = CRCNumber(c, len(t.Entries))
+	for _, one := range t.Entries {
+		c = one.crc64(c)
+	}
+	return c
+}
+
+// ResetTargetKeyPrefixes assigns new key prefixes for all data within this table.
+func (t *RandomTable) ResetTargetKeyPrefixes(prefixProvider func() string) {
+	t.KeyPrefix = prefixProvider()
+	for _, one := range t.Entries {
+		one.KeyPrefix = prefixProvider()
+		if one.SubTable != nil {
+			one.SubTable.ResetTargetKeyPrefixes(prefixProvider)
+		}
+	}
+}
+
+// Clone a copy of this.
+func (e *RandomTableEntry) Clone(owningTable *RandomTable) *RandomTableEntry {
+	clone := *e
+	clone.owningTable = owningTable
+	if e.SubTable != nil {
+		clone.SubTable = e.SubTable.Clone(&clone)
+	}
+	return &clone
+}
+
+// OwningTable returns the owning table.
+func (e *RandomTableEntry) OwningTable() *RandomTable {
+	return e.owningTable
+}
+
+func (e *RandomTableEntry) updateRollRange(start int) int {
+	e.first = start
+	switch e.Slots {
+	case 0:
+		e.RollRange = "-"
+	case 1:
+		e.RollRange = strconv.Itoa(start)
+	default:
+		e.RollRange = fmt.Sprintf("%d-%d", start, start+e.Slots-1)
+	}
+	if e.Slots < 0 {
+		return start
+	}
+	return start + e.Slots
+}
+
+func (e *RandomTableEntry) crc64(c uint64) uint64 {
+	c = CRCNumber(c, e.Slots)
+	c = CRCString(c, e.Text)
+	c = CRCNumber(c, e.Value)
+	c = CRCNumber(c, e.Modifier)
+	if e.SubTable != nil {
+		c = e.SubTable.crc64(c)
+	}
+	return c
+}
+
+// Text returns the text of the entries that were selected, including those from sub-tables.
+func (r *RandomTableResult) Text() string {
+	var list []string
+	for one := r; one != nil; one = one.Sub {
+		if one.Entry != nil && strings.TrimSpace(one.Entry.Text) != "" {
+			list = append(list, strings.TrimSpace(one.Entry.Text))
+		}
+	}
+	return strings.Join(list, randomTableResultSeparator)
+}
+
+// Value returns the value of the most deeply nested entry that was selected which has a non-zero value.
+func (r *RandomTableResult) Value() fxp.Int {
+	var value fxp.Int
+	for one := r; one != nil; one = one.Sub {
+		if one.Entry != nil && one.Entry.Value != 0 {
+			value = one.Entry.Value
+		}
+	}
+	return value
+}
+
+// Totals returns the totals rolled, including those from sub-tables, e.g. "12, 4".
+func (r *RandomTableResult) Totals() string {
+	var list []string
+	for one := r; one != nil; one = one.Sub {
+		list = append(list, strconv.Itoa(one.Total))
+	}
+	return strings.Join(list, ", ")
+}
+
+func (r *RandomTableResult) String() string {
+	return r.Totals() + ": " + r.Text()
+}
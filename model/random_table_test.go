@@ -0,0 +1,83 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/rpgtools/dice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomTable(t *testing.T) {
+	table := &model.RandomTable{Roll: dice.New("2d")}
+	low := &model.RandomTableEntry{Slots: 3, Text: "low"}
+	none := &model.RandomTableEntry{Text: "never"}
+	mid := &model.RandomTableEntry{Slots: 1, Text: "mid", Modifier: 1}
+	high := &model.RandomTableEntry{Slots: 7, Text: "high"}
+	table.AddEntry(low)
+	table.AddEntry(none)
+	table.AddEntry(mid)
+	table.AddEntry(high)
+	mid.SubTable = &model.RandomTable{Roll: dice.New("1d1")}
+	mid.SubTable.AddEntry(&model.RandomTableEntry{Slots: 1, Text: "one", Value: 5})
+	mid.SubTable.AddEntry(&model.RandomTableEntry{Slots: 1, Text: "two"})
+	table.Update()
+
+	assert.Equal(t, "2-4", low.RollRange)
+	assert.Equal(t, "-", none.RollRange)
+	assert.Equal(t, "5", mid.RollRange)
+	assert.Equal(t, "6-12", high.RollRange)
+	assert.Equal(t, mid, mid.SubTable.OwningEntry())
+
+	assert.Equal(t, low, table.Lookup(-3))
+	assert.Equal(t, low, table.Lookup(4))
+	assert.Equal(t, mid, table.Lookup(5))
+	assert.Equal(t, high, table.Lookup(12))
+	assert.Equal(t, high, table.Lookup(40))
+
+	result := table.Result(5)
+	assert.Equal(t, 2, result.Sub.Total, "the entry's modifier applies to the sub-table roll")
+	assert.Equal(t, "mid → two", result.Text())
+	assert.Equal(t, "5, 2", result.Totals())
+	assert.Equal(t, 0, int(result.Value()))
+
+	mid.Modifier = 0
+	result = table.Result(5)
+	assert.Equal(t, "mid → one", result.Text())
+	assert.NotZero(t, result.Value())
+
+	clone := table.Clone(nil)
+	assert.Equal(t, table.CRC64(), clone.CRC64())
+	clone.Entries[2].SubTable.Entries[0].Text = "changed"
+	assert.NotEqual(t, table.CRC64(), clone.CRC64())
+	assert.Equal(t, "one", mid.SubTable.Entries[0].Text)
+
+	for _, name := range []string{"Critical Hit", "Critical Miss", "Fright Check"} {
+		builtIn := model.LookupRandomTable(name, model.NewLibraries())
+		if assert.NotNil(t, builtIn, name) {
+			assert.Equal(t, name, builtIn.Name)
+			assert.Equal(t, "3d", builtIn.Roll.String())
+		}
+	}
+	fright := model.LookupRandomTable("fright check", model.NewLibraries())
+	if assert.NotNil(t, fright) {
+		assert.Equal(t, "Acquire a new mental quirk.", fright.Lookup(13).Text)
+		assert.Equal(t, fright.Entries[len(fright.Entries)-1], fright.Lookup(45))
+		assert.Equal(t, fright.Entries[len(fright.Entries)-1], fright.Lookup(40))
+		for total := 25; total < 40; total++ {
+			assert.NotEqual(t, fright.Lookup(total), fright.Lookup(total+1), total)
+		}
+		assert.Same(t, fright, model.LookupRandomTable("Fright Check", model.NewLibraries()))
+	}
+}
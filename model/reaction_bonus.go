@@ -26,6 +26,7 @@ type ReactionBonus struct {
 	Type      FeatureType `json:"type"`
 	Situation string      `json:"situation,omitempty"`
 	LeveledAmount
+	FeatureConditionHolder
 	owner fmt.Stringer
 }
 
@@ -0,0 +1,125 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	DisastrousReactionLevel ReactionLevel = iota
+	VeryBadReactionLevel
+	BadReactionLevel
+	PoorReactionLevel
+	NeutralReactionLevel
+	GoodReactionLevel
+	VeryGoodReactionLevel
+	ExcellentReactionLevel
+	LastReactionLevel = ExcellentReactionLevel
+)
+
+// AllReactionLevel holds all possible values.
+var AllReactionLevel = []ReactionLevel{
+	DisastrousReactionLevel,
+	VeryBadReactionLevel,
+	BadReactionLevel,
+	PoorReactionLevel,
+	NeutralReactionLevel,
+	GoodReactionLevel,
+	VeryGoodReactionLevel,
+	ExcellentReactionLevel,
+}
+
+// ReactionLevel holds the attitude that results from a reaction roll.
+type ReactionLevel byte
+
+// EnsureValid ensures this is of a known value.
+func (enum ReactionLevel) EnsureValid() ReactionLevel {
+	if enum <= LastReactionLevel {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum ReactionLevel) Key() string {
+	switch enum {
+	case DisastrousReactionLevel:
+		return "disastrous"
+	case VeryBadReactionLevel:
+		return "very_bad"
+	case BadReactionLevel:
+		return "bad"
+	case PoorReactionLevel:
+		return "poor"
+	case NeutralReactionLevel:
+		return "neutral"
+	case GoodReactionLevel:
+		return "good"
+	case VeryGoodReactionLevel:
+		return "very_good"
+	case ExcellentReactionLevel:
+		return "excellent"
+	default:
+		return ReactionLevel(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum ReactionLevel) String() string {
+	switch enum {
+	case DisastrousReactionLevel:
+		return i18n.Text("Disastrous")
+	case VeryBadReactionLevel:
+		return i18n.Text("Very Bad")
+	case BadReactionLevel:
+		return i18n.Text("Bad")
+	case PoorReactionLevel:
+		return i18n.Text("Poor")
+	case NeutralReactionLevel:
+		return i18n.Text("Neutral")
+	case GoodReactionLevel:
+		return i18n.Text("Good")
+	case VeryGoodReactionLevel:
+		return i18n.Text("Very Good")
+	case ExcellentReactionLevel:
+		return i18n.Text("Excellent")
+	default:
+		return ReactionLevel(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum ReactionLevel) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *ReactionLevel) UnmarshalText(text []byte) error {
+	*enum = ExtractReactionLevel(string(text))
+	return nil
+}
+
+// ExtractReactionLevel extracts the value from a string.
+func ExtractReactionLevel(str string) ReactionLevel {
+	for _, enum := range AllReactionLevel {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
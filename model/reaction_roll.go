@@ -0,0 +1,145 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/rpgtools/dice"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath/rand"
+)
+
+// ReactionRoll holds the result of a reaction roll. See p. B560.
+type ReactionRoll struct {
+	Situation ReactionSituation
+	Roll      int
+	Modifier  int
+}
+
+// RollReaction rolls 3d for a reaction in the given situation.
+func RollReaction(situation ReactionSituation, modifier int) *ReactionRoll {
+	return &ReactionRoll{
+		Situation: situation,
+		Roll:      dice.New("3d").RollWithRandomizer(rand.NewCryptoRand(), false),
+		Modifier:  modifier,
+	}
+}
+
+// ReactionLevelFor returns the reaction level for the given modified roll.
+func ReactionLevelFor(total int) ReactionLevel {
+	switch {
+	case total <= 0:
+		return DisastrousReactionLevel
+	case total <= 3:
+		return VeryBadReactionLevel
+	case total <= 6:
+		return BadReactionLevel
+	case total <= 9:
+		return PoorReactionLevel
+	case total <= 12:
+		return NeutralReactionLevel
+	case total <= 15:
+		return GoodReactionLevel
+	case total <= 18:
+		return VeryGoodReactionLevel
+	default:
+		return ExcellentReactionLevel
+	}
+}
+
+// Total returns the modified roll.
+func (r *ReactionRoll) Total() int {
+	return r.Roll + r.Modifier
+}
+
+// Level returns the reaction level.
+func (r *ReactionRoll) Level() ReactionLevel {
+	return ReactionLevelFor(r.Total())
+}
+
+// Summary returns the roll and its result, e.g. "Good (11+3=14)".
+func (r *ReactionRoll) Summary() string {
+	return fmt.Sprintf("%s (%d%+d=%d)", r.Level(), r.Roll, r.Modifier, r.Total())
+}
+
+// NoteText returns text suitable for recording the reaction in an NPC's notes. The date may be empty.
+func (r *ReactionRoll) NoteText(subject, date string) string {
+	text := fmt.Sprintf(i18n.Text("Reaction to %s (%s): %s"), subject, r.Situation, r.Summary())
+	if date != "" {
+		text = date + ": " + text
+	}
+	return text + "\n" + r.Level().Description(r.Situation)
+}
+
+// Description returns a short description of how someone with this attitude behaves in the situation.
+func (enum ReactionLevel) Description(situation ReactionSituation) string {
+	var list []string
+	switch situation {
+	case CombatReactionSituation:
+		list = []string{
+			i18n.Text("Attacks at once and fights ferociously."),
+			i18n.Text("Attacks, retreating only if clearly outmatched."),
+			i18n.Text("Attacks or threatens, but may be talked down or bought off."),
+			i18n.Text("Makes threats and demands something to let them go."),
+			i18n.Text("Goes about their business and lets them do the same."),
+			i18n.Text("Is friendly and may offer help or a truce."),
+			i18n.Text("Is friendly and may join them for a while."),
+			i18n.Text("Is friendly and will help, even at some risk."),
+		}
+	case CommerceReactionSituation:
+		list = []string{
+			i18n.Text("Refuses to deal at all, and may try to cheat, rob or report them."),
+			i18n.Text("Refuses to deal, or demands an outrageous price."),
+			i18n.Text("Demands a much higher price or offers much less than usual."),
+			i18n.Text("Asks somewhat more or offers somewhat less than usual."),
+			i18n.Text("Deals at the going rate."),
+			i18n.Text("Offers a slightly better deal than usual."),
+			i18n.Text("Offers a noticeably better deal than usual."),
+			i18n.Text("Offers the best deal possible and may throw in something extra."),
+		}
+	case AidReactionSituation:
+		list = []string{
+			i18n.Text("Refuses and may actively work to make things worse."),
+			i18n.Text("Refuses, and may make trouble."),
+			i18n.Text("Refuses."),
+			i18n.Text("Refuses unless offered something in return."),
+			i18n.Text("Helps only if it is easy and costs nothing."),
+			i18n.Text("Helps if the request is reasonable."),
+			i18n.Text("Helps willingly, even at some inconvenience."),
+			i18n.Text("Helps wholeheartedly, even at real cost or risk."),
+		}
+	case InformationReactionSituation:
+		list = []string{
+			i18n.Text("Lies maliciously, giving harmful information."),
+			i18n.Text("Lies or deliberately misleads."),
+			i18n.Text("Claims to know nothing, or gives useless answers."),
+			i18n.Text("Answers only for payment, and may be vague."),
+			i18n.Text("Answers simple questions, but won't go out of their way."),
+			i18n.Text("Answers questions fully and truthfully."),
+			i18n.Text("Answers fully and volunteers useful related information."),
+			i18n.Text("Tells everything they know and offers to find out more."),
+		}
+	default:
+		list = []string{
+			i18n.Text("Hates them and will act against their interests by any means available."),
+			i18n.Text("Dislikes them and will act against them if it is convenient."),
+			i18n.Text("Doesn't care for them and will act against them if there is something to gain."),
+			i18n.Text("Is unimpressed, and may make threats or demand a bribe before helping."),
+			i18n.Text("Ignores them as much as possible, being neither helpful nor hostile."),
+			i18n.Text("Likes them and will be helpful within normal limits."),
+			i18n.Text("Thinks highly of them and will be quite helpful and friendly."),
+			i18n.Text("Is extremely impressed and will act in their best interests at nearly all times."),
+		}
+	}
+	return list[enum.EnsureValid()]
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReactionRoll(t *testing.T) {
+	assert.Equal(t, model.DisastrousReactionLevel, model.ReactionLevelFor(-2))
+	assert.Equal(t, model.DisastrousReactionLevel, model.ReactionLevelFor(0))
+	assert.Equal(t, model.VeryBadReactionLevel, model.ReactionLevelFor(1))
+	assert.Equal(t, model.BadReactionLevel, model.ReactionLevelFor(6))
+	assert.Equal(t, model.PoorReactionLevel, model.ReactionLevelFor(7))
+	assert.Equal(t, model.NeutralReactionLevel, model.ReactionLevelFor(12))
+	assert.Equal(t, model.GoodReactionLevel, model.ReactionLevelFor(13))
+	assert.Equal(t, model.VeryGoodReactionLevel, model.ReactionLevelFor(18))
+	assert.Equal(t, model.ExcellentReactionLevel, model.ReactionLevelFor(19))
+
+	r := &model.ReactionRoll{
+		Situation: model.CommerceReactionSituation,
+		Roll:      11,
+		Modifier:  3,
+	}
+	assert.Equal(t, "Good (11+3=14)", r.Summary())
+	assert.Equal(t, "9/20/1200: Reaction to Bob (Commercial Transaction): Good (11+3=14)\n"+
+		model.GoodReactionLevel.Description(model.CommerceReactionSituation), r.NoteText("Bob", "9/20/1200"))
+
+	r.Modifier = -12
+	assert.Equal(t, "Disastrous (11-12=-1)", r.Summary())
+	for _, situation := range model.AllReactionSituation {
+		for _, level := range model.AllReactionLevel {
+			assert.NotEmpty(t, level.Description(situation))
+		}
+	}
+}
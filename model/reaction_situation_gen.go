@@ -0,0 +1,107 @@
+// Code generated from "enum.go.tmpl" - DO NOT EDIT.
+
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// Possible values.
+const (
+	GeneralReactionSituation ReactionSituation = iota
+	CombatReactionSituation
+	CommerceReactionSituation
+	AidReactionSituation
+	InformationReactionSituation
+	LastReactionSituation = InformationReactionSituation
+)
+
+// AllReactionSituation holds all possible values.
+var AllReactionSituation = []ReactionSituation{
+	GeneralReactionSituation,
+	CombatReactionSituation,
+	CommerceReactionSituation,
+	AidReactionSituation,
+	InformationReactionSituation,
+}
+
+// ReactionSituation holds the situation a reaction roll is being made for.
+type ReactionSituation byte
+
+// EnsureValid ensures this is of a known value.
+func (enum ReactionSituation) EnsureValid() ReactionSituation {
+	if enum <= LastReactionSituation {
+		return enum
+	}
+	return 0
+}
+
+// Key returns the key used in serialization.
+func (enum ReactionSituation) Key() string {
+	switch enum {
+	case GeneralReactionSituation:
+		return "general"
+	case CombatReactionSituation:
+		return "combat"
+	case CommerceReactionSituation:
+		return "commerce"
+	case AidReactionSituation:
+		return "aid"
+	case InformationReactionSituation:
+		return "information"
+	default:
+		return ReactionSituation(0).Key()
+	}
+}
+
+// String implements fmt.Stringer.
+func (enum ReactionSituation) String() string {
+	switch enum {
+	case GeneralReactionSituation:
+		return i18n.Text("General")
+	case CombatReactionSituation:
+		return i18n.Text("Potential Combat")
+	case CommerceReactionSituation:
+		return i18n.Text("Commercial Transaction")
+	case AidReactionSituation:
+		return i18n.Text("Request for Aid")
+	case InformationReactionSituation:
+		return i18n.Text("Request for Information")
+	default:
+		return ReactionSituation(0).String()
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (enum ReactionSituation) MarshalText() (text []byte, err error) {
+	return []byte(enum.Key()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (enum *ReactionSituation) UnmarshalText(text []byte) error {
+	*enum = ExtractReactionSituation(string(text))
+	return nil
+}
+
+// ExtractReactionSituation extracts the value from a string.
+func ExtractReactionSituation(str string) ReactionSituation {
+	for _, enum := range AllReactionSituation {
+		if strings.EqualFold(enum.Key(), str) {
+			return enum
+		}
+	}
+	return 0
+}
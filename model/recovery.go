@@ -0,0 +1,177 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/toolbox/xio/fs/safe"
+)
+
+const recoveryInfoExt = ".recovery"
+
+// RecoveryPath holds the directory that autosave snapshots are written into. Autosave is disabled if this is empty.
+var RecoveryPath string
+
+// RecoveryInfo holds information about a snapshot of a modified document, taken so that the edits can be recovered
+// if the application exits without saving them.
+type RecoveryInfo struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	OriginalPath string    `json:"original_path"`
+	Taken        time.Time `json:"taken"`
+	Untitled     bool      `json:"untitled,omitempty"`
+	lock         sync.Mutex
+	removed      bool
+}
+
+// NewRecoveryInfo creates a new RecoveryInfo with a unique ID.
+func NewRecoveryInfo(title, originalPath string, untitled bool) *RecoveryInfo {
+	return &RecoveryInfo{
+		ID:           uuid.New().String(),
+		Title:        title,
+		OriginalPath: originalPath,
+		Untitled:     untitled,
+	}
+}
+
+// RecoveryInfos returns the recovery information for all snapshots found in the RecoveryPath, most recent first.
+func RecoveryInfos() []*RecoveryInfo {
+	if RecoveryPath == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(RecoveryPath)
+	if err != nil {
+		return nil
+	}
+	var list []*RecoveryInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != recoveryInfoExt {
+			continue
+		}
+		var info RecoveryInfo
+		if err = jio.LoadFromFile(context.Background(), filepath.Join(RecoveryPath, name), &info); err != nil {
+			jot.Warn(errs.NewWithCause("unable to load recovery info "+name, err))
+			continue
+		}
+		if info.ID == "" || !fs.FileIsReadable(info.SnapshotPath()) {
+			info.Remove()
+			continue
+		}
+		list = append(list, &info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Taken.After(list[j].Taken) })
+	return list
+}
+
+// SnapshotPath returns the path to the snapshot of the document's contents.
+func (r *RecoveryInfo) SnapshotPath() string {
+	return filepath.Join(RecoveryPath, r.ID+filepath.Ext(r.OriginalPath))
+}
+
+func (r *RecoveryInfo) infoPath() string {
+	return filepath.Join(RecoveryPath, r.ID+recoveryInfoExt)
+}
+
+// Update the information about the document being snapshotted.
+func (r *RecoveryInfo) Update(title, originalPath string, untitled bool) {
+	r.lock.Lock()
+	r.Title = title
+	r.OriginalPath = originalPath
+	r.Untitled = untitled
+	r.lock.Unlock()
+}
+
+// Snapshot writes the data as a new snapshot of the document and updates the recovery information. This may be called
+// from a background goroutine; nothing is written once the snapshot has been removed.
+func (r *RecoveryInfo) Snapshot(data []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if RecoveryPath == "" || r.removed {
+		return nil
+	}
+	if err := os.MkdirAll(RecoveryPath, 0o750); err != nil {
+		return errs.NewWithCause(RecoveryPath, err)
+	}
+	snapshotPath := r.SnapshotPath()
+	if err := safe.WriteFileWithMode(snapshotPath, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return errs.Wrap(err)
+	}, 0o640); err != nil {
+		return errs.NewWithCause(snapshotPath, err)
+	}
+	r.Taken = time.Now()
+	return jio.SaveToFile(context.Background(), r.infoPath(), r)
+}
+
+// Remove the snapshot and its recovery information.
+func (r *RecoveryInfo) Remove() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.removed = true
+	if RecoveryPath == "" {
+		return
+	}
+	for _, p := range []string{r.SnapshotPath(), r.infoPath()} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			jot.Warn(errs.NewWithCause("unable to remove "+p, err))
+		}
+	}
+}
+
+// DiskVersionTime returns the modification time of the document's file on disk, if any.
+func (r *RecoveryInfo) DiskVersionTime() (time.Time, bool) {
+	if r.Untitled {
+		return time.Time{}, false
+	}
+	fi, err := os.Stat(r.OriginalPath)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return fi.ModTime(), true
+}
+
+// CompareToDisk returns a unified diff between the document's file on disk and the snapshot.
+func (r *RecoveryInfo) CompareToDisk() (string, error) {
+	var disk []byte
+	if _, exists := r.DiskVersionTime(); exists {
+		var err error
+		if disk, err = os.ReadFile(r.OriginalPath); err != nil {
+			return "", errs.NewWithCause(r.OriginalPath, err)
+		}
+	}
+	snapshot, err := os.ReadFile(r.SnapshotPath())
+	if err != nil {
+		return "", errs.NewWithCause(r.SnapshotPath(), err)
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.ReplaceAll(string(disk), "\r\n", "\n")),
+		B:        difflib.SplitLines(strings.ReplaceAll(string(snapshot), "\r\n", "\n")),
+		FromFile: r.OriginalPath,
+		ToFile:   r.Title,
+		Context:  3,
+	})
+}
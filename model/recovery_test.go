@@ -0,0 +1,47 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecovery(t *testing.T) {
+	saved := model.RecoveryPath
+	defer func() { model.RecoveryPath = saved }()
+	model.RecoveryPath = filepath.Join(t.TempDir(), "recovery")
+	assert.Empty(t, model.RecoveryInfos())
+
+	docPath := filepath.Join(t.TempDir(), "notes.md")
+	assert.NoError(t, os.WriteFile(docPath, []byte("one\ntwo\n"), 0o640))
+	info := model.NewRecoveryInfo("notes", docPath, false)
+	assert.NoError(t, info.Snapshot([]byte("one\nthree\n")))
+	assert.Equal(t, ".md", filepath.Ext(info.SnapshotPath()))
+
+	infos := model.RecoveryInfos()
+	if assert.Len(t, infos, 1) {
+		assert.Equal(t, info.ID, infos[0].ID)
+		assert.Equal(t, docPath, infos[0].OriginalPath)
+		diff, err := infos[0].CompareToDisk()
+		assert.NoError(t, err)
+		assert.Contains(t, diff, "-two")
+		assert.Contains(t, diff, "+three")
+	}
+
+	info.Remove()
+	assert.Empty(t, model.RecoveryInfos())
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model/jio"
+)
+
+// RowDifference describes a top-level row that differs between two versions of a list. Rows are matched by their IDs.
+// Mine is nil if the row only exists in the other version and Theirs is nil if the row only exists in mine.
+type RowDifference[T NodeTypes] struct {
+	Mine      T
+	Theirs    T
+	UseTheirs bool
+}
+
+// DiffRows returns the top-level rows that differ between mine and theirs. By default, rows that only exist in theirs
+// are marked to be added and all other differences keep mine.
+func DiffRows[T NodeTypes](mine, theirs []T) []*RowDifference[T] {
+	theirsByID := make(map[uuid.UUID]T, len(theirs))
+	for _, one := range theirs {
+		theirsByID[AsNode(one).UUID()] = one
+	}
+	mineIDs := make(map[uuid.UUID]bool, len(mine))
+	var diffs []*RowDifference[T]
+	for _, one := range mine {
+		id := AsNode(one).UUID()
+		mineIDs[id] = true
+		other, exists := theirsByID[id]
+		if !exists {
+			diffs = append(diffs, &RowDifference[T]{Mine: one})
+		} else if rowCRC64(one) != rowCRC64(other) {
+			diffs = append(diffs, &RowDifference[T]{Mine: one, Theirs: other})
+		}
+	}
+	for _, one := range theirs {
+		if !mineIDs[AsNode(one).UUID()] {
+			diffs = append(diffs, &RowDifference[T]{Theirs: one, UseTheirs: true})
+		}
+	}
+	return diffs
+}
+
+// MergeRows produces a new list from mine, applying the differences marked to use theirs. Rows taken from theirs are
+// cloned into the given entity. Rows added from theirs are placed after the row that precedes them in theirs.
+func MergeRows[T NodeTypes](entity *Entity, mine, theirs []T, diffs []*RowDifference[T]) []T {
+	var zero T
+	replace := make(map[uuid.UUID]T)
+	remove := make(map[uuid.UUID]bool)
+	add := make(map[uuid.UUID]bool)
+	for _, diff := range diffs {
+		if !diff.UseTheirs {
+			continue
+		}
+		switch {
+		case diff.Theirs == zero:
+			remove[AsNode(diff.Mine).UUID()] = true
+		case diff.Mine == zero:
+			add[AsNode(diff.Theirs).UUID()] = true
+		default:
+			replace[AsNode(diff.Mine).UUID()] = diff.Theirs
+		}
+	}
+	result := make([]T, 0, len(mine)+len(add))
+	for _, one := range mine {
+		id := AsNode(one).UUID()
+		if remove[id] {
+			continue
+		}
+		if other, exists := replace[id]; exists {
+			one = AsNode(other).Clone(entity, zero, true)
+		}
+		result = append(result, one)
+	}
+	for i, one := range theirs {
+		node := AsNode(one)
+		if !add[node.UUID()] {
+			continue
+		}
+		insertAt := 0
+		for j := i - 1; j >= 0 && insertAt == 0; j-- {
+			precedingID := AsNode(theirs[j]).UUID()
+			for k, existing := range result {
+				if AsNode(existing).UUID() == precedingID {
+					insertAt = k + 1
+					break
+				}
+			}
+		}
+		result = append(result, zero)
+		copy(result[insertAt+1:], result[insertAt:])
+		result[insertAt] = node.Clone(entity, zero, true)
+	}
+	return result
+}
+
+func rowCRC64(data any) uint64 {
+	var buffer bytes.Buffer
+	if err := jio.Save(context.Background(), &buffer, data); err != nil {
+		return 0
+	}
+	return CRCBytes(0, buffer.Bytes())
+}
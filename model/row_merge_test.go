@@ -0,0 +1,65 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowMerge(t *testing.T) {
+	newNote := func(text string) *model.Note {
+		n := model.NewNote(nil, nil, false)
+		n.Text = text
+		return n
+	}
+	same := newNote("same")
+	changed := newNote("changed")
+	onlyMine := newNote("only mine")
+	mine := []*model.Note{same, changed, onlyMine}
+
+	theirs := model.CloneNodes[*model.Note](nil, nil, true, []model.Node[*model.Note]{same, changed})
+	theirs[1].Text = "changed on disk"
+	added := newNote("added on disk")
+	theirs = append(theirs[:1], append([]*model.Note{added}, theirs[1:]...)...)
+
+	diffs := model.DiffRows(mine, theirs)
+	if !assert.Len(t, diffs, 3) {
+		return
+	}
+	assert.Equal(t, changed, diffs[0].Mine)
+	assert.False(t, diffs[0].UseTheirs)
+	assert.Equal(t, onlyMine, diffs[1].Mine)
+	assert.Nil(t, diffs[1].Theirs)
+	assert.Nil(t, diffs[2].Mine)
+	assert.True(t, diffs[2].UseTheirs)
+
+	merged := model.MergeRows(nil, mine, theirs, diffs)
+	texts := make([]string, len(merged))
+	for i, one := range merged {
+		texts[i] = one.Text
+	}
+	assert.Equal(t, []string{"same", "added on disk", "changed", "only mine"}, texts)
+
+	diffs[0].UseTheirs = true
+	diffs[1].UseTheirs = true
+	diffs[2].UseTheirs = false
+	merged = model.MergeRows(nil, mine, theirs, diffs)
+	texts = texts[:0]
+	for _, one := range merged {
+		texts = append(texts, one.Text)
+	}
+	assert.Equal(t, []string{"same", "changed on disk"}, texts)
+	assert.Equal(t, changed.ID, merged[1].ID)
+}
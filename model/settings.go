@@ -45,6 +45,7 @@ var global *Settings
 type NavigatorSettings struct {
 	DividerPosition float32  `json:"divider_position"`
 	OpenRowKeys     []string `json:"open_row_keys,omitempty"`
+	CampaignOnly    bool     `json:"campaign_only,omitempty"`
 }
 
 // Settings holds the application settings.
@@ -54,6 +55,7 @@ type Settings struct {
 	LibrarySet         Libraries         `json:"libraries,omitempty"`
 	LibraryExplorer    NavigatorSettings `json:"library_explorer"`
 	RecentFiles        []string          `json:"recent_files,omitempty"`
+	RecentCommands     []string          `json:"recent_commands,omitempty"`
 	LastDirs           map[string]string `json:"last_dirs,omitempty"`
 	PageRefs           PageRefs          `json:"page_refs,omitempty"`
 	KeyBindings        KeyBindings       `json:"key_bindings,omitempty"`
@@ -62,6 +64,7 @@ type Settings struct {
 	Fonts              Fonts             `json:"fonts"`
 	QuickExports       *QuickExports     `json:"quick_exports,omitempty"`
 	Sheet              *SheetSettings    `json:"sheet_settings,omitempty"`
+	Campaign           *Campaign         `json:"campaign,omitempty"`
 	ColorMode          unison.ColorMode  `json:"color_mode"`
 }
 
@@ -124,6 +127,9 @@ func (s *Settings) EnsureValidity() {
 	} else {
 		s.Sheet.EnsureValidity()
 	}
+	if s.Campaign != nil {
+		s.Campaign.EnsureValidity()
+	}
 }
 
 // LastDir returns the last directory used for the given key.
@@ -15,8 +15,11 @@ import (
 	"context"
 	"io/fs"
 
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/json"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // SheetSettingsResponder defines the method required to be notified of updates to the SheetSettings.
@@ -29,25 +32,31 @@ type SheetSettingsResponder interface {
 
 // SheetSettingsData holds the SheetSettings data that is written to disk.
 type SheetSettingsData struct {
-	Page                          *PageSettings     `json:"page,omitempty"`
-	BlockLayout                   *BlockLayout      `json:"block_layout,omitempty"`
-	Attributes                    *AttributeDefs    `json:"attributes,omitempty"`
-	BodyType                      *Body             `json:"body_type,alt=hit_locations,omitempty"`
-	DamageProgression             DamageProgression `json:"damage_progression"`
-	DefaultLengthUnits            LengthUnits       `json:"default_length_units"`
-	DefaultWeightUnits            WeightUnits       `json:"default_weight_units"`
-	UserDescriptionDisplay        DisplayOption     `json:"user_description_display"`
-	ModifiersDisplay              DisplayOption     `json:"modifiers_display"`
-	NotesDisplay                  DisplayOption     `json:"notes_display"`
-	SkillLevelAdjDisplay          DisplayOption     `json:"skill_level_adj_display"`
-	UseMultiplicativeModifiers    bool              `json:"use_multiplicative_modifiers,omitempty"`
-	UseModifyingDicePlusAdds      bool              `json:"use_modifying_dice_plus_adds,omitempty"`
-	UseHalfStatDefaults           bool              `json:"use_half_stat_defaults,omitempty"`
-	ShowTraitModifierAdj          bool              `json:"show_trait_modifier_adj,alt=show_advantage_modifier_adj,omitempty"`
-	ShowEquipmentModifierAdj      bool              `json:"show_equipment_modifier_adj,omitempty"`
-	ShowSpellAdj                  bool              `json:"show_spell_adj,omitempty"`
-	UseTitleInFooter              bool              `json:"use_title_in_footer,omitempty"`
-	ExcludeUnspentPointsFromTotal bool              `json:"exclude_unspent_points_from_total"`
+	Page                          *PageSettings       `json:"page,omitempty"`
+	BlockLayout                   *BlockLayout        `json:"block_layout,omitempty"`
+	Attributes                    *AttributeDefs      `json:"attributes,omitempty"`
+	BodyType                      *Body               `json:"body_type,alt=hit_locations,omitempty"`
+	DamageProgression             DamageProgression   `json:"damage_progression"`
+	DefaultLengthUnits            LengthUnits         `json:"default_length_units"`
+	DefaultWeightUnits            WeightUnits         `json:"default_weight_units"`
+	Currencies                    []*Currency         `json:"currencies,omitempty"`
+	DefaultCurrency               string              `json:"default_currency,omitempty"`
+	UserDescriptionDisplay        DisplayOption       `json:"user_description_display"`
+	ModifiersDisplay              DisplayOption       `json:"modifiers_display"`
+	NotesDisplay                  DisplayOption       `json:"notes_display"`
+	SkillLevelAdjDisplay          DisplayOption       `json:"skill_level_adj_display"`
+	UseMultiplicativeModifiers    bool                `json:"use_multiplicative_modifiers,omitempty"`
+	UseModifyingDicePlusAdds      bool                `json:"use_modifying_dice_plus_adds,omitempty"`
+	UseHalfStatDefaults           bool                `json:"use_half_stat_defaults,omitempty"`
+	ShowTraitModifierAdj          bool                `json:"show_trait_modifier_adj,alt=show_advantage_modifier_adj,omitempty"`
+	ShowEquipmentModifierAdj      bool                `json:"show_equipment_modifier_adj,omitempty"`
+	ShowSpellAdj                  bool                `json:"show_spell_adj,omitempty"`
+	UseTitleInFooter              bool                `json:"use_title_in_footer,omitempty"`
+	ShowBodyDiagram               bool                `json:"show_body_diagram,omitempty"`
+	ExcludeUnspentPointsFromTotal bool                `json:"exclude_unspent_points_from_total"`
+	StudyHoursPerPoint            fxp.Int             `json:"study_hours_per_point,omitempty"`
+	CustomBlocks                  []*CustomBlock      `json:"custom_blocks,omitempty"`
+	HiddenColumns                 map[string][]string `json:"hidden_columns,omitempty"`
 }
 
 // SheetSettings holds sheet settings.
@@ -129,6 +138,108 @@ func (s *SheetSettings) EnsureValidity() {
 	s.ModifiersDisplay = s.ModifiersDisplay.EnsureValid()
 	s.NotesDisplay = s.NotesDisplay.EnsureValid()
 	s.SkillLevelAdjDisplay = s.SkillLevelAdjDisplay.EnsureValid()
+	s.StudyHoursPerPoint = s.StudyHoursPerPoint.Max(0)
+	currencies := s.Currencies[:0]
+	for _, one := range s.Currencies {
+		if one != nil && one.ID != "" {
+			currencies = append(currencies, one)
+		}
+	}
+	s.Currencies = currencies
+	blocks := s.CustomBlocks[:0]
+	used := make(map[string]bool)
+	for _, one := range s.CustomBlocks {
+		if one != nil {
+			if one.ID = SanitizeCustomBlockID(one.ID); one.ID != "" && !used[one.ID] {
+				used[one.ID] = true
+				one.Type = one.Type.EnsureValid()
+				blocks = append(blocks, one)
+			}
+		}
+	}
+	s.CustomBlocks = blocks
+	s.BlockLayout.SyncCustomKeys(s.CustomBlockKeys())
+	for k, v := range s.HiddenColumns {
+		valid := maps.Values(columnKeysForBlock(k))
+		list := make([]string, 0, len(v))
+		for _, one := range v {
+			if slices.Contains(valid, one) && !slices.Contains(list, one) {
+				list = append(list, one)
+			}
+		}
+		if len(list) == 0 {
+			delete(s.HiddenColumns, k)
+		} else {
+			slices.Sort(list)
+			s.HiddenColumns[k] = list
+		}
+	}
+}
+
+// CustomBlockKeys returns the block layout keys of the custom blocks.
+func (s *SheetSettings) CustomBlockKeys() []string {
+	keys := make([]string, len(s.CustomBlocks))
+	for i, one := range s.CustomBlocks {
+		keys[i] = one.Key()
+	}
+	return keys
+}
+
+// CustomBlockFor returns the custom block with the given block layout key, or nil.
+func (s *SheetSettings) CustomBlockFor(key string) *CustomBlock {
+	for _, one := range s.CustomBlocks {
+		if one.Key() == key {
+			return one
+		}
+	}
+	return nil
+}
+
+// ColumnHidden returns true if the column with the given ID has been hidden in the block with the given key.
+func (s *SheetSettings) ColumnHidden(blockKey string, columnID int) bool {
+	key := ColumnKey(blockKey, columnID)
+	return key != "" && slices.Contains(s.HiddenColumns[blockKey], key)
+}
+
+// HiddenColumnIDs returns the IDs of the columns that have been hidden in the block with the given key.
+func (s *SheetSettings) HiddenColumnIDs(blockKey string) []int {
+	var ids []int
+	for id, key := range columnKeysForBlock(blockKey) {
+		if slices.Contains(s.HiddenColumns[blockKey], key) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// SetColumnHidden sets whether the column with the given ID is hidden in the block with the given key.
+func (s *SheetSettings) SetColumnHidden(blockKey string, columnID int, hidden bool) {
+	key := ColumnKey(blockKey, columnID)
+	if key == "" {
+		return
+	}
+	list := s.HiddenColumns[blockKey]
+	if i := slices.Index(list, key); i != -1 {
+		if hidden {
+			return
+		}
+		list = slices.Delete(slices.Clone(list), i, i+1)
+	} else {
+		if !hidden {
+			return
+		}
+		list = append(slices.Clone(list), key)
+		slices.Sort(list)
+	}
+	if len(list) == 0 {
+		delete(s.HiddenColumns, blockKey)
+		return
+	}
+	if s.HiddenColumns == nil {
+		s.HiddenColumns = make(map[string][]string)
+	}
+	s.HiddenColumns[blockKey] = list
 }
 
 // MarshalJSON implements json.Marshaler.
@@ -153,6 +264,14 @@ func (s *SheetSettings) Clone(entity *Entity) *SheetSettings {
 	clone.BlockLayout = s.BlockLayout.Clone()
 	clone.Attributes = s.Attributes.Clone()
 	clone.BodyType = s.BodyType.Clone(entity, nil)
+	clone.Currencies = CloneCurrencies(s.Currencies)
+	clone.CustomBlocks = CloneCustomBlocks(s.CustomBlocks)
+	if s.HiddenColumns != nil {
+		clone.HiddenColumns = make(map[string][]string, len(s.HiddenColumns))
+		for k, v := range s.HiddenColumns {
+			clone.HiddenColumns[k] = slices.Clone(v)
+		}
+	}
 	return &clone
 }
 
@@ -14,6 +14,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"strings"
 
@@ -95,6 +96,15 @@ func SaveSkills(skills []*Skill, filePath string) error {
 	})
 }
 
+// SaveSkillsTo writes the Skill list to the writer as JSON.
+func SaveSkillsTo(skills []*Skill, w io.Writer) error {
+	return jio.Save(context.Background(), w, &skillListData{
+		Type:    skillListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    skills,
+	})
+}
+
 // NewSkill creates a new Skill.
 func NewSkill(entity *Entity, parent *Skill, container bool) *Skill {
 	return newSkill(entity, parent, SkillID, container)
@@ -213,6 +223,7 @@ func (s *Skill) CellData(columnID int, data *CellData) {
 		data.Primary = s.Description()
 		data.Secondary = s.SecondaryText(func(option DisplayOption) bool { return option.Inline() })
 		data.UnsatisfiedReason = s.UnsatisfiedReason
+		data.ComplianceWarning = ComplianceWarning(s.ComplianceIssues())
 		data.Tooltip = s.SecondaryText(func(option DisplayOption) bool { return option.Tooltip() })
 		data.TemplateInfo = s.TemplatePicker.Description()
 	case SkillDifficultyColumn:
@@ -375,7 +386,7 @@ func (s *Skill) SecondaryText(optionChecker func(DisplayOption) bool) string {
 			}
 			buffer.WriteString(text)
 		}
-		if study := StudyHoursProgressText(ResolveStudyHours(s.Study)); study != "" {
+		if study := StudyHoursProgressText(ResolveStudyHours(s.Study), StudyHoursPerPointFor(s.Entity)); study != "" {
 			if buffer.Len() != 0 {
 				buffer.WriteByte('\n')
 			}
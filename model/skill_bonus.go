@@ -28,6 +28,7 @@ type SkillBonus struct {
 	SpecializationCriteria StringCriteria     `json:"specialization,omitempty"`
 	TagsCriteria           StringCriteria     `json:"tags,alt=category,omitempty"`
 	LeveledAmount
+	FeatureConditionHolder
 	owner fmt.Stringer
 }
 
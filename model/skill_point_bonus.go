@@ -28,6 +28,7 @@ type SkillPointBonus struct {
 	SpecializationCriteria StringCriteria `json:"specialization,omitempty"`
 	TagsCriteria           StringCriteria `json:"tags,alt=category,omitempty"`
 	LeveledAmount
+	FeatureConditionHolder
 	owner fmt.Stringer
 }
 
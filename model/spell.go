@@ -14,6 +14,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"strings"
 
@@ -93,6 +94,15 @@ func SaveSpells(spells []*Spell, filePath string) error {
 	})
 }
 
+// SaveSpellsTo writes the Spell list to the writer as JSON.
+func SaveSpellsTo(spells []*Spell, w io.Writer) error {
+	return jio.Save(context.Background(), w, &spellListData{
+		Type:    spellListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    spells,
+	})
+}
+
 // NewSpell creates a new Spell.
 func NewSpell(entity *Entity, parent *Spell, container bool) *Spell {
 	s := newSpell(entity, parent, SpellID, container)
@@ -211,6 +221,7 @@ func (s *Spell) CellData(columnID int, data *CellData) {
 		data.Primary = s.Description()
 		data.Secondary = s.SecondaryText(func(option DisplayOption) bool { return option.Inline() })
 		data.UnsatisfiedReason = s.UnsatisfiedReason
+		data.ComplianceWarning = ComplianceWarning(s.ComplianceIssues())
 		data.Tooltip = s.SecondaryText(func(option DisplayOption) bool { return option.Tooltip() })
 		data.TemplateInfo = s.TemplatePicker.Description()
 	case SpellResistColumn:
@@ -232,11 +243,13 @@ func (s *Spell) CellData(columnID int, data *CellData) {
 		if !s.Container() {
 			data.Type = TextCellType
 			data.Primary = s.CastingCost
+			data.Tooltip = s.reducedCostTooltip(s.CastingData().Cost)
 		}
 	case SpellMaintainCostColumn:
 		if !s.Container() {
 			data.Type = TextCellType
 			data.Primary = s.MaintenanceCost
+			data.Tooltip = s.reducedCostTooltip(s.CastingData().Maintenance)
 		}
 	case SpellCastTimeColumn:
 		if !s.Container() {
@@ -378,6 +391,17 @@ func (s *Spell) UpdateLevel() bool {
 	return saved != s.LevelData
 }
 
+func (s *Spell) reducedCostTooltip(cost SpellCost) string {
+	if reduction := SpellCostReduction(s.LevelData.Level); reduction > 0 && cost.Parsed && cost.Base > 0 {
+		reduced := (cost.Base - reduction).Max(0)
+		if cost.PerUnit != "" {
+			return fmt.Sprintf(i18n.Text("Reduced by %v for skill level: %v per %s"), reduction, reduced, cost.PerUnit)
+		}
+		return fmt.Sprintf(i18n.Text("Reduced by %v for skill level: %v"), reduction, reduced)
+	}
+	return ""
+}
+
 // CalculateLevel returns the computed level without updating it.
 func (s *Spell) CalculateLevel() Level {
 	if strings.HasPrefix(s.Type, SpellID) {
@@ -650,7 +674,7 @@ func (s *Spell) SecondaryText(optionChecker func(DisplayOption) bool) string {
 			}
 			buffer.WriteString(rituals)
 		}
-		if study := StudyHoursProgressText(ResolveStudyHours(s.Study)); study != "" {
+		if study := StudyHoursProgressText(ResolveStudyHours(s.Study), StudyHoursPerPointFor(s.Entity)); study != "" {
 			if buffer.Len() != 0 {
 				buffer.WriteByte('\n')
 			}
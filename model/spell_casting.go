@@ -0,0 +1,190 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// DefaultSpellEnergySource is the attribute that energy is drawn from when a spell has not designated one.
+const DefaultSpellEnergySource = "fp"
+
+var (
+	spellCostRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)(?:\s*(?:/|per)\s*(.+?))?$`)
+	spellTimeRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-z]+)\.?$`)
+	spellTimeUnits = map[string]fxp.Int{
+		"s":       fxp.One,
+		"sec":     fxp.One,
+		"secs":    fxp.One,
+		"second":  fxp.One,
+		"seconds": fxp.One,
+		"m":       fxp.From(60),
+		"min":     fxp.From(60),
+		"mins":    fxp.From(60),
+		"minute":  fxp.From(60),
+		"minutes": fxp.From(60),
+		"h":       fxp.From(3600),
+		"hr":      fxp.From(3600),
+		"hrs":     fxp.From(3600),
+		"hour":    fxp.From(3600),
+		"hours":   fxp.From(3600),
+		"day":     fxp.From(86400),
+		"days":    fxp.From(86400),
+		"week":    fxp.From(604800),
+		"weeks":   fxp.From(604800),
+	}
+)
+
+// SpellCost holds a casting or maintenance cost parsed from a spell's text.
+type SpellCost struct {
+	Text    string
+	Base    fxp.Int
+	PerUnit string
+	Parsed  bool
+}
+
+// SpellTime holds a casting time or duration parsed from a spell's text.
+type SpellTime struct {
+	Text    string
+	Seconds fxp.Int
+	Parsed  bool
+}
+
+// SpellCastingData holds the structured casting information for a spell.
+type SpellCastingData struct {
+	Cost          SpellCost
+	Maintenance   SpellCost
+	CastingTime   SpellTime
+	Duration      SpellTime
+	CostReduction fxp.Int
+}
+
+// ParseSpellCost parses the text of a casting or maintenance cost. Recognized forms are a plain number ("3"), a number
+// per unit ("2/level", "1 per yard radius") and "none". Anything else is left unparsed.
+func ParseSpellCost(text string) SpellCost {
+	c := SpellCost{Text: text}
+	s := strings.ToLower(strings.TrimSpace(text))
+	if s == "none" || s == "0" {
+		c.Parsed = true
+		return c
+	}
+	if parts := spellCostRegex.FindStringSubmatch(s); parts != nil {
+		var err error
+		if c.Base, err = fxp.FromString(parts[1]); err == nil {
+			c.PerUnit = parts[2]
+			c.Parsed = true
+		}
+	}
+	return c
+}
+
+// ParseSpellTime parses the text of a casting time or duration, such as "1 sec", "10 min" or "instant". Anything else
+// is left unparsed.
+func ParseSpellTime(text string) SpellTime {
+	t := SpellTime{Text: text}
+	s := strings.ToLower(strings.TrimSpace(text))
+	if s == "instant" || s == "instantaneous" {
+		t.Parsed = true
+		return t
+	}
+	if parts := spellTimeRegex.FindStringSubmatch(s); parts != nil {
+		if multiplier, ok := spellTimeUnits[parts[2]]; ok {
+			if value, err := fxp.FromString(parts[1]); err == nil {
+				t.Seconds = value.Mul(multiplier)
+				t.Parsed = true
+			}
+		}
+	}
+	return t
+}
+
+// SpellCostReduction returns the reduction in energy cost for casting or maintaining a spell at the given skill level.
+func SpellCostReduction(level fxp.Int) fxp.Int {
+	if level < fxp.Fifteen {
+		return 0
+	}
+	return (level - fxp.Ten).Div(fxp.Five).Trunc()
+}
+
+// CastingData returns the structured casting information for the spell.
+func (s *Spell) CastingData() SpellCastingData {
+	data := SpellCastingData{
+		Cost:          ParseSpellCost(s.CastingCost),
+		CastingTime:   ParseSpellTime(s.CastingTime),
+		Duration:      ParseSpellTime(s.Duration),
+		CostReduction: SpellCostReduction(s.LevelData.Level),
+	}
+	maintenance := strings.ToLower(strings.TrimSpace(s.MaintenanceCost))
+	switch {
+	case strings.HasPrefix(maintenance, "same"):
+		data.Maintenance = data.Cost
+		data.Maintenance.Text = s.MaintenanceCost
+	case strings.HasPrefix(maintenance, "half"):
+		data.Maintenance = data.Cost
+		data.Maintenance.Text = s.MaintenanceCost
+		data.Maintenance.Base = data.Cost.Base.Div(fxp.Two).Ceil()
+	default:
+		data.Maintenance = ParseSpellCost(s.MaintenanceCost)
+	}
+	return data
+}
+
+// Effective returns the energy required, multiplying the base cost by the number of units for costs that are per unit
+// and then applying the cost reduction once to the total. Returns 0 if the cost could not be parsed.
+func (c SpellCost) Effective(units, reduction fxp.Int) fxp.Int {
+	if !c.Parsed {
+		return 0
+	}
+	cost := c.Base
+	if c.PerUnit != "" {
+		cost = cost.Mul(units.Max(fxp.One))
+	}
+	return (cost - reduction).Max(0)
+}
+
+// String implements fmt.Stringer.
+func (c SpellCost) String() string {
+	if !c.Parsed {
+		return c.Text
+	}
+	if c.PerUnit != "" {
+		return fmt.Sprintf(i18n.Text("%v per %s"), c.Base, c.PerUnit)
+	}
+	return c.Base.String()
+}
+
+// EnergySourceAttribute returns the ID of the pool attribute that energy for casting the spell is drawn from.
+func (s *Spell) EnergySourceAttribute() string {
+	if s.EnergySource != "" {
+		return s.EnergySource
+	}
+	return DefaultSpellEnergySource
+}
+
+// SpendEnergy deducts the amount from the pool attribute with the given ID.
+func (e *Entity) SpendEnergy(attrID string, amount fxp.Int) error {
+	attr, ok := e.Attributes.Set[attrID]
+	if !ok {
+		return errs.Newf(i18n.Text("no attribute with the ID '%s'"), attrID)
+	}
+	if def := attr.AttributeDef(); def == nil || def.Type != PoolAttributeType {
+		return errs.Newf(i18n.Text("the attribute '%s' is not a pool"), attrID)
+	}
+	attr.Damage = (attr.Damage + amount).Max(0)
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpellCasting(t *testing.T) {
+	cost := model.ParseSpellCost("2/yard radius")
+	assert.True(t, cost.Parsed)
+	assert.Equal(t, fxp.Two, cost.Base)
+	assert.Equal(t, "yard radius", cost.PerUnit)
+	assert.Equal(t, fxp.Seven, cost.Effective(fxp.Four, fxp.One))
+	assert.Equal(t, fxp.Int(0), cost.Effective(fxp.One, fxp.Three))
+	assert.Equal(t, fxp.Three, model.ParseSpellCost("3").Effective(fxp.Ten, 0))
+	assert.False(t, model.ParseSpellCost("Varies").Parsed)
+	assert.Equal(t, "Varies", model.ParseSpellCost("Varies").String())
+
+	assert.Equal(t, fxp.From(600), model.ParseSpellTime("10 min").Seconds)
+	assert.Equal(t, fxp.Two, model.ParseSpellTime("2 sec.").Seconds)
+	assert.True(t, model.ParseSpellTime("Instant").Parsed)
+	assert.False(t, model.ParseSpellTime("Permanent").Parsed)
+
+	assert.Equal(t, fxp.Int(0), model.SpellCostReduction(fxp.Fifteen-fxp.One))
+	assert.Equal(t, fxp.One, model.SpellCostReduction(fxp.Fifteen))
+	assert.Equal(t, fxp.Two, model.SpellCostReduction(fxp.Twenty))
+
+	entity := model.NewEntity(model.PC)
+	spell := model.NewSpell(entity, nil, false)
+	spell.CastingCost = "3"
+	spell.MaintenanceCost = "Half"
+	data := spell.CastingData()
+	assert.Equal(t, fxp.Two, data.Maintenance.Base)
+	assert.NoError(t, entity.SpendEnergy(spell.EnergySourceAttribute(), fxp.Three))
+	assert.Equal(t, fxp.Seven, entity.Attributes.Current("fp"))
+	assert.Error(t, entity.SpendEnergy("st", fxp.One))
+}
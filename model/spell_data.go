@@ -40,6 +40,7 @@ func (d *SpellData) ClearUnusedFieldsForType() {
 		d.MaintenanceCost = ""
 		d.CastingTime = ""
 		d.Duration = ""
+		d.EnergySource = ""
 		d.RitualSkillName = ""
 		d.RitualPrereqCount = 0
 		d.Points = 0
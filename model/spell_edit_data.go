@@ -35,6 +35,7 @@ type SpellEditData struct {
 	MaintenanceCost   string              `json:"maintenance_cost,omitempty"` // Non-container only
 	CastingTime       string              `json:"casting_time,omitempty"`     // Non-container only
 	Duration          string              `json:"duration,omitempty"`         // Non-container only
+	EnergySource      string              `json:"energy_source,omitempty"`    // Non-container only
 	RitualSkillName   string              `json:"base_skill,omitempty"`       // Non-container only
 	RitualPrereqCount int                 `json:"prereq_count,omitempty"`     // Non-container only
 	Points            fxp.Int             `json:"points,omitempty"`           // Non-container only
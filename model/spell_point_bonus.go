@@ -27,6 +27,7 @@ type SpellPointBonus struct {
 	NameCriteria   StringCriteria `json:"name,omitempty"`
 	TagsCriteria   StringCriteria `json:"tags,alt=category,omitempty"`
 	LeveledAmount
+	FeatureConditionHolder
 	owner fmt.Stringer
 }
 
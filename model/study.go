@@ -18,11 +18,16 @@ import (
 	"github.com/richardwilkes/toolbox/i18n"
 )
 
+// DefaultStudyHoursPerPoint is the number of study hours required to gain a point when no other value has been set.
+var DefaultStudyHoursPerPoint = fxp.TwoHundred
+
 // Study holds data about a single study session.
 type Study struct {
-	Type  StudyType `json:"type"`
-	Hours fxp.Int   `json:"hours"`
-	Note  string    `json:"note,omitempty"`
+	Type    StudyType `json:"type"`
+	Hours   fxp.Int   `json:"hours"`
+	Date    string    `json:"date,omitempty"`
+	Teacher string    `json:"teacher,omitempty"`
+	Note    string    `json:"note,omitempty"`
 }
 
 // Clone creates a copy of the TemplatePicker.
@@ -41,9 +46,17 @@ func ResolveStudyHours(study []*Study) fxp.Int {
 }
 
 // StudyHoursProgressText returns the progress text or an empty string.
-func StudyHoursProgressText(hours fxp.Int) string {
+func StudyHoursProgressText(hours, hoursPerPoint fxp.Int) string {
 	if hours <= 0 {
 		return ""
 	}
-	return fmt.Sprintf(i18n.Text("Studied %v/200 hours"), hours)
+	return fmt.Sprintf(i18n.Text("Studied %v/%v hours"), hours, hoursPerPoint)
+}
+
+// StudyHoursPerPointFor returns the number of study hours required to gain a point for the entity.
+func StudyHoursPerPointFor(entity *Entity) fxp.Int {
+	if hours := SheetSettingsFor(entity).StudyHoursPerPoint; hours > 0 {
+		return hours
+	}
+	return DefaultStudyHoursPerPoint
 }
@@ -0,0 +1,204 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/rpgtools/calendar"
+	"github.com/richardwilkes/toolbox/errs"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+const maxStudyLevelSteps = 100
+
+// StudyPlan holds the parameters used to project how long it will take to reach a study goal.
+type StudyPlan struct {
+	Type          StudyType
+	HoursPerDay   fxp.Int
+	PointsNeeded  fxp.Int
+	HoursStudied  fxp.Int
+	HoursPerPoint fxp.Int
+	Calendar      *calendar.Calendar
+	Start         calendar.Date
+}
+
+// StudyProjection holds the result of projecting a StudyPlan.
+type StudyProjection struct {
+	HoursPerDay    fxp.Int
+	HoursRemaining fxp.Int
+	Days           int
+	Completion     calendar.Date
+}
+
+// NewStudyPlan creates a new StudyPlan for the skill, spell or trait, starting on the given date.
+func NewStudyPlan(target any, cal *calendar.Calendar, start calendar.Date) (*StudyPlan, error) {
+	study, entity, ok := StudyTargetData(target)
+	if !ok {
+		return nil, errs.New(i18n.Text("only skills, spells and traits may be studied"))
+	}
+	return &StudyPlan{
+		Type:          SelfStudyType,
+		HoursPerDay:   SelfStudyType.MaxHoursPerDay(),
+		HoursStudied:  ResolveStudyHours(study),
+		HoursPerPoint: StudyHoursPerPointFor(entity),
+		Calendar:      cal,
+		Start:         start,
+	}, nil
+}
+
+// Project the date the goal of the plan will be reached. The hours per day are limited to the maximum permitted by the
+// type of study.
+func (p *StudyPlan) Project() StudyProjection {
+	var proj StudyProjection
+	proj.HoursPerDay = p.HoursPerDay.Min(p.Type.MaxHoursPerDay()).Max(0)
+	proj.HoursRemaining = (p.PointsNeeded.Mul(p.HoursPerPoint) - p.HoursStudied).Max(0)
+	proj.Completion = p.Start
+	if proj.HoursRemaining > 0 {
+		effective := proj.HoursPerDay.Mul(p.Type.Multiplier())
+		if effective <= 0 {
+			proj.Days = -1
+			return proj
+		}
+		proj.Days = fxp.As[int](proj.HoursRemaining.Div(effective).Ceil())
+		proj.Completion = p.Calendar.NewDateByDays(p.Start.Days + proj.Days - 1)
+	}
+	return proj
+}
+
+// StudyPointsForLevel returns the number of additional points the skill, spell or trait needs to reach the target
+// level. For traits, the level is the trait's level count.
+func StudyPointsForLevel(target any, level fxp.Int) (fxp.Int, error) {
+	switch t := target.(type) {
+	case *Skill:
+		if t.Container() {
+			break
+		}
+		clone := t.Clone(t.Entity, nil, false)
+		for i := 0; i < maxStudyLevelSteps && clone.CalculateLevel().Level < level; i++ {
+			clone.IncrementSkillLevel()
+		}
+		if clone.CalculateLevel().Level < level {
+			return 0, errs.New(i18n.Text("the target level cannot be reached"))
+		}
+		return (clone.Points - t.Points).Max(0), nil
+	case *Spell:
+		if t.Container() {
+			break
+		}
+		clone := t.Clone(t.Entity, nil, false)
+		for i := 0; i < maxStudyLevelSteps && clone.CalculateLevel().Level < level; i++ {
+			clone.IncrementSkillLevel()
+		}
+		if clone.CalculateLevel().Level < level {
+			return 0, errs.New(i18n.Text("the target level cannot be reached"))
+		}
+		return (clone.Points - t.Points).Max(0), nil
+	case *Trait:
+		if !t.IsLeveled() {
+			return 0, errs.New(i18n.Text("the trait does not have levels"))
+		}
+		return (traitPointsAtLevel(t, level) - traitPointsAtLevel(t, t.Levels)).Max(0), nil
+	}
+	return 0, errs.New(i18n.Text("only skills, spells and traits may be studied"))
+}
+
+func traitPointsAtLevel(t *Trait, levels fxp.Int) fxp.Int {
+	return AdjustedPoints(t.Entity, t.CanLevel, t.BasePoints, levels, t.PointsPerLevel, t.CR, t.AllModifiers(),
+		t.RoundCostDown)
+}
+
+// ConvertStudyToPoints converts as many of the completed study hours of the skill, spell or trait as possible into
+// points on it, consuming the oldest study first. Returns the number of points that were added.
+func ConvertStudyToPoints(target any) fxp.Int {
+	study, entity, ok := StudyTargetData(target)
+	if !ok {
+		return 0
+	}
+	hoursPerPoint := StudyHoursPerPointFor(entity)
+	available := ResolveStudyHours(study).Div(hoursPerPoint).Trunc()
+	if available <= 0 {
+		return 0
+	}
+	var used fxp.Int
+	switch t := target.(type) {
+	case *Skill:
+		used = available
+		t.SetRawPoints(t.Points + used)
+	case *Spell:
+		used = available
+		t.SetRawPoints(t.Points + used)
+	case *Trait:
+		if !t.IsLeveled() {
+			return 0
+		}
+		for {
+			cost := traitPointsAtLevel(t, t.Levels+fxp.One) - traitPointsAtLevel(t, t.Levels)
+			if cost <= 0 || used+cost > available {
+				break
+			}
+			used += cost
+			t.Levels += fxp.One
+		}
+	}
+	if used <= 0 {
+		return 0
+	}
+	remaining := consumeStudyHours(study, used.Mul(hoursPerPoint))
+	switch t := target.(type) {
+	case *Skill:
+		t.Study = remaining
+	case *Spell:
+		t.Study = remaining
+	case *Trait:
+		t.Study = remaining
+	}
+	return used
+}
+
+// consumeStudyHours removes the given number of effective study hours from the list, starting with the oldest entries
+// (those at the end of the list), and returns the remaining entries.
+func consumeStudyHours(study []*Study, hours fxp.Int) []*Study {
+	remaining := make([]*Study, len(study))
+	copy(remaining, study)
+	for i := len(remaining) - 1; i >= 0 && hours > 0; i-- {
+		one := remaining[i]
+		effective := one.Hours.Mul(one.Type.Multiplier())
+		if effective <= hours {
+			hours -= effective
+			remaining = remaining[:i]
+			continue
+		}
+		clone := one.Clone()
+		clone.Hours = (effective - hours).Div(one.Type.Multiplier())
+		remaining[i] = clone
+		hours = 0
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	return remaining
+}
+
+// StudyTargetData returns the study list and owning entity of the skill, spell or trait. ok will be false if the target
+// is not something that can be studied.
+func StudyTargetData(target any) (study []*Study, entity *Entity, ok bool) {
+	switch t := target.(type) {
+	case *Skill:
+		return t.Study, t.Entity, !t.Container()
+	case *Spell:
+		return t.Study, t.Entity, !t.Container()
+	case *Trait:
+		return t.Study, t.Entity, !t.Container()
+	default:
+		return nil, nil, false
+	}
+}
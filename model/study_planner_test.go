@@ -0,0 +1,55 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/rpgtools/calendar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudyPlanner(t *testing.T) {
+	entity := model.NewEntity(model.PC)
+	entity.SheetSettings.StudyHoursPerPoint = fxp.Hundred
+	skill := model.NewSkill(entity, nil, false)
+	skill.Name = "Research"
+	skill.Points = fxp.One
+	skill.Study = []*model.Study{
+		{Type: model.TeacherStudyType, Hours: fxp.Thirty},
+		{Type: model.SelfStudyType, Hours: fxp.From(300), Date: "January 1, 2000"},
+	}
+	entity.Skills = append(entity.Skills, skill)
+	entity.Recalculate()
+
+	cal := calendar.Default
+	start := cal.MustNewDate(1, 1, 2000)
+	plan, err := model.NewStudyPlan(skill, cal, start)
+	assert.NoError(t, err)
+	assert.Equal(t, fxp.From(180), plan.HoursStudied)
+	plan.Type = model.TeacherStudyType
+	plan.HoursPerDay = fxp.Twelve
+	plan.PointsNeeded = fxp.Three
+	proj := plan.Project()
+	assert.Equal(t, fxp.Eight, proj.HoursPerDay, "capped hours per day")
+	assert.Equal(t, fxp.From(120), proj.HoursRemaining)
+	assert.Equal(t, 15, proj.Days)
+	assert.Equal(t, start.Days+14, proj.Completion.Days)
+
+	assert.Equal(t, fxp.One, model.ConvertStudyToPoints(skill))
+	assert.Equal(t, fxp.Two, skill.Points)
+	assert.Len(t, skill.Study, 2)
+	assert.Equal(t, fxp.Hundred, skill.Study[1].Hours, "oldest study consumed first")
+	assert.Equal(t, fxp.From(80), model.ResolveStudyHours(skill.Study))
+}
@@ -34,6 +34,22 @@ func (enum StudyType) Multiplier() fxp.Int {
 	}
 }
 
+// MaxHoursPerDay returns the maximum number of hours per day that may be spent on this type of study. For study that
+// depends upon the student's job, this is the maximum allowed with the most favorable job situation.
+func (enum StudyType) MaxHoursPerDay() fxp.Int {
+	switch enum.EnsureValid() {
+	case SelfStudyType:
+		return fxp.Twelve
+	case JobStudyType, TeacherStudyType:
+		return fxp.Eight
+	case IntensiveStudyType:
+		return fxp.Sixteen
+	default:
+		jot.Fatal(1, "need handler for unknown study type")
+		return 0
+	}
+}
+
 // Limitations returns a list of strings describing the limitations when doing this type of study.
 func (enum StudyType) Limitations() []string {
 	switch enum.EnsureValid() {
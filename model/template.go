@@ -14,6 +14,7 @@ package model
 import (
 	"bytes"
 	"context"
+	"io"
 	"io/fs"
 
 	"github.com/google/uuid"
@@ -72,6 +73,12 @@ func (t *Template) Save(filePath string) error {
 	return jio.SaveToFile(context.Background(), filePath, t)
 }
 
+// SaveTo writes the Template to the writer as JSON.
+func (t *Template) SaveTo(w io.Writer) error {
+	t.Version = CurrentDataVersion
+	return jio.Save(context.Background(), w, t)
+}
+
 // TraitList implements ListProvider
 func (t *Template) TraitList() []*Trait {
 	return t.Traits
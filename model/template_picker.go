@@ -64,3 +64,29 @@ func (t *TemplatePicker) Description() string {
 		return ""
 	}
 }
+
+// TemplatePickerPoints returns the points a choice offered by a points-based TemplatePicker is worth.
+func TemplatePickerPoints(choice any) fxp.Int {
+	switch nc := choice.(type) {
+	case *Skill:
+		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == PointsTemplatePickerType &&
+			nc.TemplatePicker.Qualifier.Compare == EqualsNumber {
+			return nc.TemplatePicker.Qualifier.Qualifier
+		}
+		return nc.RawPoints()
+	case *Spell:
+		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == PointsTemplatePickerType &&
+			nc.TemplatePicker.Qualifier.Compare == EqualsNumber {
+			return nc.TemplatePicker.Qualifier.Qualifier
+		}
+		return nc.RawPoints()
+	case *Trait:
+		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == PointsTemplatePickerType &&
+			nc.TemplatePicker.Qualifier.Compare == EqualsNumber {
+			return nc.TemplatePicker.Qualifier.Qualifier
+		}
+		return nc.AdjustedPoints()
+	default:
+		return 0
+	}
+}
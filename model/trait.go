@@ -13,6 +13,8 @@ package model
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"io/fs"
 	"strings"
 
@@ -37,8 +39,13 @@ const (
 	TraitPointsColumn
 	TraitTagsColumn
 	TraitReferenceColumn
+	TraitActiveColumn
 )
 
+// DefaultTraitActivationPool is the attribute that a trait's activation cost is drawn from when it has not designated
+// one.
+const DefaultTraitActivationPool = "fp"
+
 const (
 	traitListTypeKey = "trait_list"
 	traitTypeKey     = "trait"
@@ -84,6 +91,15 @@ func SaveTraits(traits []*Trait, filePath string) error {
 	})
 }
 
+// SaveTraitsTo writes the Trait list to the writer as JSON.
+func SaveTraitsTo(traits []*Trait, w io.Writer) error {
+	return jio.Save(context.Background(), w, &traitListData{
+		Type:    traitListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    traits,
+	})
+}
+
 // NewTrait creates a new Trait.
 func NewTrait(entity *Entity, parent *Trait, container bool) *Trait {
 	a := &Trait{
@@ -223,6 +239,7 @@ func (a *Trait) CellData(columnID int, data *CellData) {
 		data.Type = TextCellType
 		data.Primary = a.AdjustedPoints().String()
 		data.Alignment = unison.EndAlignment
+		data.Tooltip = a.AlternativeAbilityCostTooltip()
 	case TraitTagsColumn:
 		data.Type = TagsCellType
 		data.Primary = CombineTags(a.Tags)
@@ -230,6 +247,25 @@ func (a *Trait) CellData(columnID int, data *CellData) {
 		data.Type = PageRefCellType
 		data.Primary = a.PageRef
 		data.Secondary = a.Name
+	case TraitActiveColumn:
+		if a.CanToggleActive() {
+			data.Type = ToggleCellType
+			data.Checked = a.switchedOn()
+			data.Alignment = unison.MiddleAlignment
+			if a.Switchable && a.ActivationCost > 0 {
+				pool := a.ActivationPoolAttribute()
+				if a.Entity != nil {
+					if attr, ok := a.Entity.Attributes.Set[pool]; ok {
+						if def := attr.AttributeDef(); def != nil {
+							pool = def.Name
+						}
+					}
+				}
+				data.Tooltip = fmt.Sprintf(i18n.Text("Costs %v %s to activate"), a.ActivationCost, pool)
+			}
+		} else {
+			data.Type = TextCellType
+		}
 	}
 }
 
@@ -276,6 +312,175 @@ func (a *Trait) IsLeveled() bool {
 	return a.CanLevel && !a.Container()
 }
 
+// Inactive returns true if the Trait, or one of its parents, is switched off or is an alternative ability that is not
+// currently in use.
+func (a *Trait) Inactive() bool {
+	for p := a; p != nil; p = p.parent {
+		if !p.switchedOn() {
+			return true
+		}
+	}
+	return false
+}
+
+// switchedOn returns true if the Trait itself is both switched on, if switchable, and in use, if an alternative ability.
+func (a *Trait) switchedOn() bool {
+	if a.Switchable && !a.Active && !a.Container() {
+		return false
+	}
+	return !a.IsAlternative() || slices.Contains(a.parent.ActiveAlternatives(), a)
+}
+
+// IsAlternative returns true if the Trait is one of the children of an alternative abilities container.
+func (a *Trait) IsAlternative() bool {
+	return a.parent != nil && a.parent.Container() && a.parent.ContainerType == AlternativeAbilitiesContainerType
+}
+
+// CanToggleActive returns true if the Trait can be switched on and off or selected as the alternative in use.
+func (a *Trait) CanToggleActive() bool {
+	return a.IsAlternative() || (a.Switchable && !a.Container())
+}
+
+// HasToggleableTraits returns true if any of the traits, or their descendants, can be switched on and off or selected
+// as the alternative in use.
+func HasToggleableTraits(traits []*Trait) bool {
+	found := false
+	Traverse(func(a *Trait) bool {
+		found = a.CanToggleActive()
+		return found
+	}, false, false, traits...)
+	return found
+}
+
+// AvailableActiveSlots returns the number of children of an alternative abilities container that may be in use at once.
+func (a *Trait) AvailableActiveSlots() int {
+	if a.ActiveSlots < 1 {
+		return 1
+	}
+	return a.ActiveSlots
+}
+
+// ActiveAlternatives returns the children of an alternative abilities container that are currently in use. If none have
+// been selected, the first ones are used.
+func (a *Trait) ActiveAlternatives() []*Trait {
+	if !a.Container() || a.ContainerType != AlternativeAbilitiesContainerType {
+		return nil
+	}
+	slots := a.AvailableActiveSlots()
+	list := make([]*Trait, 0, slots)
+	for _, one := range a.Children {
+		if one.InUse && len(list) < slots {
+			list = append(list, one)
+		}
+	}
+	if len(list) == 0 {
+		for _, one := range a.Children {
+			if len(list) == slots {
+				break
+			}
+			list = append(list, one)
+		}
+	}
+	return list
+}
+
+// ActivationPoolAttribute returns the ID of the pool attribute the activation cost is drawn from.
+func (a *Trait) ActivationPoolAttribute() string {
+	if a.ActivationPool != "" {
+		return a.ActivationPool
+	}
+	return DefaultTraitActivationPool
+}
+
+// SetActive switches the Trait on or off. Switching it on deducts the activation cost, if any, from its pool. For an
+// alternative ability, switching it on also puts it into use, taking the place of the earliest selected sibling when
+// all of the container's slots are already in use.
+func (a *Trait) SetActive(active bool) error {
+	if !a.CanToggleActive() {
+		return errs.New(i18n.Text("the trait cannot be switched on and off"))
+	}
+	var inUse []*Trait
+	if a.IsAlternative() {
+		inUse = a.parent.ActiveAlternatives()
+		for _, one := range a.parent.Children {
+			one.InUse = slices.Contains(inUse, one)
+		}
+	}
+	if active && !a.switchedOn() && a.Switchable && a.ActivationCost > 0 && a.Entity != nil {
+		if err := a.Entity.SpendEnergy(a.ActivationPoolAttribute(), a.ActivationCost); err != nil {
+			return err
+		}
+	}
+	if a.IsAlternative() {
+		if active && !a.InUse && len(inUse) >= a.parent.AvailableActiveSlots() {
+			inUse[0].InUse = false
+		}
+		if active || !a.Switchable {
+			a.InUse = active
+		}
+	}
+	if a.Switchable && !a.Container() {
+		a.Active = active
+	}
+	return nil
+}
+
+// AlternativeAbilityCost holds the cost one child contributes to an alternative abilities container.
+type AlternativeAbilityCost struct {
+	Trait *Trait
+	Base  fxp.Int
+	Cost  fxp.Int
+	Full  bool
+}
+
+// AlternativeAbilityCosts returns the cost breakdown for an alternative abilities container: the most expensive child
+// is paid for in full, while the rest cost 1/5 of their normal amount.
+func (a *Trait) AlternativeAbilityCosts() []AlternativeAbilityCost {
+	if !a.Container() || a.ContainerType != AlternativeAbilitiesContainerType {
+		return nil
+	}
+	costs := make([]AlternativeAbilityCost, len(a.Children))
+	var max fxp.Int
+	for i, one := range a.Children {
+		costs[i].Trait = one
+		costs[i].Base = one.AdjustedPoints()
+		if costs[i].Base > max {
+			max = costs[i].Base
+		}
+	}
+	found := false
+	for i := range costs {
+		if !found && costs[i].Base == max {
+			found = true
+			costs[i].Full = true
+			costs[i].Cost = costs[i].Base
+		} else {
+			costs[i].Cost = fxp.ApplyRounding(calculateModifierPoints(costs[i].Base, fxp.Twenty), a.RoundCostDown)
+		}
+	}
+	return costs
+}
+
+// AlternativeAbilityCostTooltip returns a description of the cost breakdown for an alternative abilities container.
+func (a *Trait) AlternativeAbilityCostTooltip() string {
+	costs := a.AlternativeAbilityCosts()
+	if len(costs) == 0 {
+		return ""
+	}
+	var buffer strings.Builder
+	for _, one := range costs {
+		if buffer.Len() != 0 {
+			buffer.WriteByte('\n')
+		}
+		if one.Full {
+			fmt.Fprintf(&buffer, i18n.Text("%s: %v (full cost)"), one.Trait.String(), one.Cost)
+		} else {
+			fmt.Fprintf(&buffer, i18n.Text("%s: %v (1/5 of %v)"), one.Trait.String(), one.Cost, one.Base)
+		}
+	}
+	return buffer.String()
+}
+
 // AdjustedPoints returns the total points, taking levels and modifiers into account.
 func (a *Trait) AdjustedPoints() fxp.Int {
 	if a.EffectivelyDisabled() {
@@ -286,21 +491,8 @@ func (a *Trait) AdjustedPoints() fxp.Int {
 	}
 	var points fxp.Int
 	if a.ContainerType == AlternativeAbilitiesContainerType {
-		values := make([]fxp.Int, len(a.Children))
-		for i, one := range a.Children {
-			values[i] = one.AdjustedPoints()
-			if values[i] > points {
-				points = values[i]
-			}
-		}
-		max := points
-		found := false
-		for _, v := range values {
-			if !found && max == v {
-				found = true
-			} else {
-				points += fxp.ApplyRounding(calculateModifierPoints(v, fxp.Twenty), a.RoundCostDown)
-			}
+		for _, one := range a.AlternativeAbilityCosts() {
+			points += one.Cost
 		}
 	} else {
 		for _, one := range a.Children {
@@ -324,15 +516,10 @@ func (a *Trait) AllModifiers() []*TraitModifier {
 
 // Enabled returns true if this Trait and all of its parents are enabled.
 func (a *Trait) Enabled() bool {
-	if a.Disabled {
-		return false
-	}
-	p := a.parent
-	for p != nil {
-		if p.Disabled {
+	for p := a; p != nil; p = p.parent {
+		if p.Disabled || (a.Entity != nil && a.Entity.TraitTagsDisabledByThreshold(p.Tags)) {
 			return false
 		}
-		p = p.parent
 	}
 	return true
 }
@@ -458,7 +645,7 @@ func (a *Trait) SecondaryText(optionChecker func(DisplayOption) bool) string {
 			}
 			buffer.WriteString(text)
 		}
-		if study := StudyHoursProgressText(ResolveStudyHours(a.Study)); study != "" {
+		if study := StudyHoursProgressText(ResolveStudyHours(a.Study), StudyHoursPerPointFor(a.Entity)); study != "" {
 			if buffer.Len() != 0 {
 				buffer.WriteByte('\n')
 			}
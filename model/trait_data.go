@@ -38,16 +38,29 @@ func (d *TraitData) ClearUnusedFieldsForType() {
 		d.Weapons = nil
 		d.Features = nil
 		d.RoundCostDown = false
+		d.Switchable = false
+		d.Active = false
+		d.ActivationCost = 0
+		d.ActivationPool = ""
+		if d.ContainerType != AlternativeAbilitiesContainerType {
+			d.ActiveSlots = 0
+		}
 		if d.TemplatePicker == nil {
 			d.TemplatePicker = &TemplatePicker{}
 		}
 	} else {
 		d.ContainerType = 0
+		d.ActiveSlots = 0
 		d.TemplatePicker = nil
 		d.Ancestry = ""
 		if !d.CanLevel {
 			d.Levels = 0
 			d.PointsPerLevel = 0
 		}
+		if !d.Switchable {
+			d.Active = false
+			d.ActivationCost = 0
+			d.ActivationPool = ""
+		}
 	}
 }
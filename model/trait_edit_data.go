@@ -39,9 +39,15 @@ type TraitEditData struct {
 	CR             SelfControlRoll    `json:"cr,omitempty"`
 	CRAdj          SelfControlRollAdj `json:"cr_adj,omitempty"`
 	ContainerType  ContainerType      `json:"container_type,omitempty"` // Container only
+	ActiveSlots    int                `json:"active_slots,omitempty"`   // Container only
 	Disabled       bool               `json:"disabled,omitempty"`
-	RoundCostDown  bool               `json:"round_down,omitempty"` // Non-container only
-	CanLevel       bool               `json:"can_level,omitempty"`  // Non-container only
+	InUse          bool               `json:"in_use,omitempty"`
+	RoundCostDown  bool               `json:"round_down,omitempty"`      // Non-container only
+	CanLevel       bool               `json:"can_level,omitempty"`       // Non-container only
+	Switchable     bool               `json:"switchable,omitempty"`      // Non-container only
+	Active         bool               `json:"active,omitempty"`          // Non-container only
+	ActivationCost fxp.Int            `json:"activation_cost,omitempty"` // Non-container only
+	ActivationPool string             `json:"activation_pool,omitempty"` // Non-container only
 }
 
 // CopyFrom implements node.EditorData.
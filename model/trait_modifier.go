@@ -13,6 +13,7 @@ package model
 
 import (
 	"context"
+	"io"
 	"io/fs"
 	"strings"
 
@@ -90,6 +91,15 @@ func SaveTraitModifiers(modifiers []*TraitModifier, filePath string) error {
 	})
 }
 
+// SaveTraitModifiersTo writes the TraitModifier list to the writer as JSON.
+func SaveTraitModifiersTo(modifiers []*TraitModifier, w io.Writer) error {
+	return jio.Save(context.Background(), w, &traitModifierListData{
+		Type:    traitModifierListTypeKey,
+		Version: CurrentDataVersion,
+		Rows:    modifiers,
+	})
+}
+
 // NewTraitModifier creates a TraitModifier.
 func NewTraitModifier(entity *Entity, parent *TraitModifier, container bool) *TraitModifier {
 	a := &TraitModifier{
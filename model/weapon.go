@@ -342,12 +342,12 @@ func (w *Weapon) skillLevelBaseAdjustment(entity *Entity, tooltip *xio.ByteBuffe
 		adj += bonus.AdjustedAmount()
 	}
 	for _, f := range w.Owner.FeatureList() {
-		adj += w.extractSkillBonusForThisWeapon(f, tooltip)
+		adj += w.extractSkillBonusForThisWeapon(entity, f, tooltip)
 	}
 	if t, ok := w.Owner.(*Trait); ok {
 		Traverse(func(mod *TraitModifier) bool {
 			for _, f := range mod.Features {
-				adj += w.extractSkillBonusForThisWeapon(f, tooltip)
+				adj += w.extractSkillBonusForThisWeapon(entity, f, tooltip)
 			}
 			return false
 		}, true, true, t.Modifiers...)
@@ -355,7 +355,7 @@ func (w *Weapon) skillLevelBaseAdjustment(entity *Entity, tooltip *xio.ByteBuffe
 	if eqp, ok := w.Owner.(*Equipment); ok {
 		Traverse(func(mod *EquipmentModifier) bool {
 			for _, f := range mod.Features {
-				adj += w.extractSkillBonusForThisWeapon(f, tooltip)
+				adj += w.extractSkillBonusForThisWeapon(entity, f, tooltip)
 			}
 			return false
 		}, true, true, eqp.Modifiers...)
@@ -386,8 +386,8 @@ func (w *Weapon) EncumbrancePenalty(entity *Entity, tooltip *xio.ByteBuffer) fxp
 	return penalty
 }
 
-func (w *Weapon) extractSkillBonusForThisWeapon(f Feature, tooltip *xio.ByteBuffer) fxp.Int {
-	if sb, ok := f.(*SkillBonus); ok {
+func (w *Weapon) extractSkillBonusForThisWeapon(entity *Entity, f Feature, tooltip *xio.ByteBuffer) fxp.Int {
+	if sb, ok := f.(*SkillBonus); ok && entity.FeatureActive(f) {
 		if sb.SelectionType.EnsureValid() == ThisWeaponSkillSelectionType {
 			if sb.SpecializationCriteria.Matches(w.Usage) {
 				sb.AddToTooltip(tooltip)
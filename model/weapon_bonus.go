@@ -31,6 +31,7 @@ type WeaponBonus struct {
 	RelativeLevelCriteria  NumericCriteria     `json:"level,omitempty"`
 	TagsCriteria           StringCriteria      `json:"tags,alt=category,omitempty"`
 	LeveledAmount
+	FeatureConditionHolder
 	owner fmt.Stringer
 }
 
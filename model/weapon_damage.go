@@ -218,12 +218,12 @@ func (w *WeaponDamage) ResolvedDamage(tooltip *xio.ByteBuffer) string {
 	nameQualifier := w.Owner.String()
 	pc.AddNamedWeaponBonusesFor(nameQualifier, w.Owner.Usage, tags, base.Count, levels, tooltip, bonusSet)
 	for _, f := range w.Owner.Owner.FeatureList() {
-		w.extractWeaponBonus(f, bonusSet, fxp.From(base.Count), levels, tooltip)
+		w.extractWeaponBonus(pc, f, bonusSet, fxp.From(base.Count), levels, tooltip)
 	}
 	if tOK {
 		Traverse(func(mod *TraitModifier) bool {
 			for _, f := range mod.Features {
-				w.extractWeaponBonus(f, bonusSet, fxp.From(base.Count), levels, tooltip)
+				w.extractWeaponBonus(pc, f, bonusSet, fxp.From(base.Count), levels, tooltip)
 			}
 			return false
 		}, true, true, t.Modifiers...)
@@ -231,7 +231,7 @@ func (w *WeaponDamage) ResolvedDamage(tooltip *xio.ByteBuffer) string {
 	if eqp, ok := w.Owner.Owner.(*Equipment); ok {
 		Traverse(func(mod *EquipmentModifier) bool {
 			for _, f := range mod.Features {
-				w.extractWeaponBonus(f, bonusSet, fxp.From(base.Count), levels, tooltip)
+				w.extractWeaponBonus(pc, f, bonusSet, fxp.From(base.Count), levels, tooltip)
 			}
 			return false
 		}, true, true, eqp.Modifiers...)
@@ -313,8 +313,8 @@ func (w *WeaponDamage) ResolvedDamage(tooltip *xio.ByteBuffer) string {
 	return buffer.String()
 }
 
-func (w *WeaponDamage) extractWeaponBonus(f Feature, set map[*WeaponBonus]bool, dieCount, levels fxp.Int, tooltip *xio.ByteBuffer) {
-	if bonus, ok := f.(*WeaponBonus); ok {
+func (w *WeaponDamage) extractWeaponBonus(pc *Entity, f Feature, set map[*WeaponBonus]bool, dieCount, levels fxp.Int, tooltip *xio.ByteBuffer) {
+	if bonus, ok := f.(*WeaponBonus); ok && pc.FeatureActive(f) {
 		level := bonus.LeveledAmount.Level
 		if bonus.Type == WeaponBonusFeatureType {
 			bonus.LeveledAmount.Level = dieCount
@@ -27,3 +27,11 @@ func (enum WeaponType) SVG() *unison.SVG {
 		return nil
 	}
 }
+
+// BlockLayoutKey returns the key of the sheet block that lists weapons of this type.
+func (enum WeaponType) BlockLayoutKey() string {
+	if enum == RangedWeaponType {
+		return BlockLayoutRangedKey
+	}
+	return BlockLayoutMeleeKey
+}
@@ -63,3 +63,21 @@ func (enum WeightUnits) ToPounds(weight fxp.Int) fxp.Int {
 		return Pound.ToPounds(weight)
 	}
 }
+
+// FromPounds converts the weight into a value expressed in this WeightUnits.
+func (enum WeightUnits) FromPounds(weight Weight) fxp.Int {
+	switch enum {
+	case Pound, PoundAlt:
+		return fxp.Int(weight)
+	case Ounce:
+		return fxp.Int(weight).Mul(fxp.From(16))
+	case Ton, TonAlt:
+		return fxp.Int(weight).Div(fxp.From(2000))
+	case Kilogram:
+		return fxp.Int(weight).Div(fxp.From(2))
+	case Gram:
+		return fxp.Int(weight).Mul(fxp.From(500))
+	default:
+		return Pound.FromPounds(weight)
+	}
+}
@@ -11,9 +11,7 @@
 
 package model
 
-import (
-	"github.com/richardwilkes/toolbox/xmath/rand"
-)
+import "github.com/richardwilkes/toolbox/xmath/rand"
 
 // WeightedAncestryOptions is a string that has a weight associated with it.
 type WeightedAncestryOptions struct {
@@ -27,7 +25,7 @@ func (o *WeightedAncestryOptions) Valid() bool {
 }
 
 // ChooseWeightedAncestryOptions selects a string option from the available set.
-func ChooseWeightedAncestryOptions(options []*WeightedAncestryOptions, omitter func(*AncestryOptions) bool) *AncestryOptions {
+func ChooseWeightedAncestryOptions(rnd rand.Randomizer, options []*WeightedAncestryOptions, omitter func(*AncestryOptions) bool) *AncestryOptions {
 	total := 0
 	for _, one := range options {
 		if omitter == nil || !omitter(one.Value) {
@@ -35,7 +33,7 @@ func ChooseWeightedAncestryOptions(options []*WeightedAncestryOptions, omitter f
 		}
 	}
 	if total > 0 {
-		choice := 1 + rand.NewCryptoRand().Intn(total)
+		choice := 1 + rnd.Intn(total)
 		for _, one := range options {
 			if omitter == nil || !omitter(one.Value) {
 				choice -= one.Weight
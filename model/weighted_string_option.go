@@ -11,9 +11,7 @@
 
 package model
 
-import (
-	"github.com/richardwilkes/toolbox/xmath/rand"
-)
+import "github.com/richardwilkes/toolbox/xmath/rand"
 
 // WeightedStringOption is a string that has a weight associated with it.
 type WeightedStringOption struct {
@@ -27,7 +25,7 @@ func (o *WeightedStringOption) Valid() bool {
 }
 
 // ChooseWeightedStringOption selects a string option from the available set.
-func ChooseWeightedStringOption(options []*WeightedStringOption, not string) string {
+func ChooseWeightedStringOption(rnd rand.Randomizer, options []*WeightedStringOption, not string) string {
 	total := 0
 	for _, one := range options {
 		if one.Value != not {
@@ -35,7 +33,7 @@ func ChooseWeightedStringOption(options []*WeightedStringOption, not string) str
 		}
 	}
 	if total > 0 {
-		choice := 1 + rand.NewCryptoRand().Intn(total)
+		choice := 1 + rnd.Intn(total)
 		for _, one := range options {
 			if one.Value != not {
 				choice -= one.Weight
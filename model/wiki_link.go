@@ -0,0 +1,217 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// WikiLinkScheme is the scheme used for the markdown links that wiki links are expanded into.
+const WikiLinkScheme = "wiki:"
+
+// Wiki link kinds.
+const (
+	WikiSheet     = "sheet"
+	WikiAttribute = "attr"
+	WikiTrait     = "trait"
+	WikiSkill     = "skill"
+	WikiSpell     = "spell"
+	WikiEquipment = "equipment"
+	WikiNote      = "note"
+	WikiPageRef   = "ref"
+	WikiLibrary   = "lib"
+)
+
+var wikiLinkRegex = regexp.MustCompile(`\[\[([A-Za-z]+):([^\]|]+)(?:\|([^\]]*))?]]`)
+
+// WikiLink holds a wiki-style link of the form [[kind:target]] or [[kind:target|label]].
+type WikiLink struct {
+	Kind   string
+	Target string
+	Label  string
+}
+
+// ExpandWikiLinks replaces the wiki links in the markdown text with regular markdown links that use WikiLinkScheme.
+// The resolver provides the text to display for each link. Links within fenced code blocks are left alone.
+func ExpandWikiLinks(text string, resolver func(link *WikiLink) string) string {
+	if !strings.Contains(text, "[[") {
+		return text
+	}
+	lines := strings.SplitAfter(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = wikiLinkRegex.ReplaceAllStringFunc(line, func(match string) string {
+			parts := wikiLinkRegex.FindStringSubmatch(match)
+			link := &WikiLink{
+				Kind:   strings.ToLower(parts[1]),
+				Target: strings.TrimSpace(parts[2]),
+				Label:  strings.TrimSpace(parts[3]),
+			}
+			return fmt.Sprintf(`[%s](%s "%s")`, escapeMarkdownLinkText(resolver(link)), link.URL(),
+				strings.ReplaceAll(link.Kind+":"+link.Target, `"`, `\"`))
+		})
+	}
+	return strings.Join(lines, "")
+}
+
+func escapeMarkdownLinkText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(text)
+}
+
+// ParseWikiLinkURL parses a link target produced by ExpandWikiLinks. Returns nil if the target isn't a wiki link.
+func ParseWikiLinkURL(target string) *WikiLink {
+	if !strings.HasPrefix(strings.ToLower(target), WikiLinkScheme) {
+		return nil
+	}
+	kind, rest, found := strings.Cut(target[len(WikiLinkScheme):], ":")
+	if !found {
+		return nil
+	}
+	if revised, err := url.PathUnescape(rest); err == nil {
+		rest = revised
+	}
+	return &WikiLink{
+		Kind:   strings.ToLower(kind),
+		Target: rest,
+	}
+}
+
+// URL returns the markdown link target for this wiki link.
+func (l *WikiLink) URL() string {
+	return WikiLinkScheme + l.Kind + ":" + url.PathEscape(l.Target)
+}
+
+// Owner returns the portion of the target that identifies the sheet or library, i.e. everything before the first
+// slash.
+func (l *WikiLink) Owner() string {
+	owner, _, _ := strings.Cut(l.Target, "/")
+	return strings.TrimSpace(owner)
+}
+
+// Item returns the portion of the target that identifies the item within its owner, i.e. everything after the first
+// slash. Returns an empty string if there is no slash.
+func (l *WikiLink) Item() string {
+	_, item, _ := strings.Cut(l.Target, "/")
+	return strings.TrimSpace(item)
+}
+
+// WikiText returns the live text to display for the link within this entity, along with the ID of the row it refers
+// to, if any. Returns false if the link cannot be resolved.
+func (e *Entity) WikiText(link *WikiLink) (text string, rowID uuid.UUID, ok bool) {
+	item := link.Item()
+	switch link.Kind {
+	case WikiSheet:
+		text = e.Profile.Name
+		if text == "" {
+			text = link.Target
+		}
+		var pools []string
+		for _, attrID := range []string{"hp", "fp"} {
+			if attr := e.ResolveAttribute(attrID); attr != nil && attr.AttributeDef() != nil {
+				pools = append(pools, fmt.Sprintf("%s %s/%s", attr.AttributeDef().Name, attr.Current().String(),
+					attr.Maximum().String()))
+			}
+		}
+		if len(pools) != 0 {
+			text += " (" + strings.Join(pools, ", ") + ")"
+		}
+		return text, uuid.UUID{}, true
+	case WikiAttribute:
+		for _, attr := range e.Attributes.List() {
+			def := attr.AttributeDef()
+			if def == nil || !matchesWikiName(item, attr.AttrID, def.Name, def.FullName) {
+				continue
+			}
+			if def.Type == PoolAttributeType {
+				return fmt.Sprintf("%s %s/%s", def.Name, attr.Current().String(), attr.Maximum().String()), uuid.UUID{}, true
+			}
+			return fmt.Sprintf("%s %s", def.Name, attr.Maximum().String()), uuid.UUID{}, true
+		}
+	case WikiTrait:
+		if t := FindWikiRow(item, e.Traits); t != nil {
+			return t.String(), t.ID, true
+		}
+	case WikiSkill:
+		if s := FindWikiRow(item, e.Skills); s != nil {
+			return fmt.Sprintf("%s-%s", s.String(), s.CalculateLevel().LevelAsString(s.Container())), s.ID, true
+		}
+	case WikiSpell:
+		if s := FindWikiRow(item, e.Spells); s != nil {
+			return fmt.Sprintf("%s-%s", s.String(), s.CalculateLevel().LevelAsString(s.Container())), s.ID, true
+		}
+	case WikiEquipment:
+		if eqp := FindWikiRow(item, e.CarriedEquipment); eqp != nil {
+			return eqp.String(), eqp.ID, true
+		}
+		if eqp := FindWikiRow(item, e.OtherEquipment); eqp != nil {
+			return eqp.String(), eqp.ID, true
+		}
+	case WikiNote:
+		if n := FindWikiRow(item, e.Notes); n != nil {
+			return n.String(), n.ID, true
+		}
+	}
+	return "", uuid.UUID{}, false
+}
+
+// FindWikiRow returns the first row within the list (including nested rows) whose name matches.
+func FindWikiRow[T NodeTypes](name string, list []T) T {
+	var found T
+	Traverse(func(row T) bool {
+		names := []string{AsNode(row).String()}
+		switch r := any(row).(type) {
+		case *Trait:
+			names = append(names, r.Name)
+		case *Skill:
+			names = append(names, r.Name, skillLikeName(r.Name, r.Specialization))
+		case *Spell:
+			names = append(names, r.Name)
+		case *Equipment:
+			names = append(names, r.Name)
+		}
+		if matchesWikiName(name, names...) {
+			found = row
+			return true
+		}
+		return false
+	}, false, false, list...)
+	return found
+}
+
+func skillLikeName(name, specialization string) string {
+	if specialization == "" {
+		return name
+	}
+	return name + " (" + specialization + ")"
+}
+
+func matchesWikiName(target string, names ...string) bool {
+	target = strings.TrimSpace(target)
+	for _, name := range names {
+		if name != "" && strings.EqualFold(target, strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWikiLinks(t *testing.T) {
+	var links []*model.WikiLink
+	text := model.ExpandWikiLinks("See [[skill:Arden/Guns (Pistol)]] and [[ref:B123|the rules]].\n```\n[[sheet:Arden]]\n```\n",
+		func(link *model.WikiLink) string {
+			links = append(links, link)
+			if link.Label != "" {
+				return link.Label
+			}
+			return "Guns (Pistol)-14"
+		})
+	assert.Equal(t, "See [Guns (Pistol)-14](wiki:skill:Arden%2FGuns%20%28Pistol%29 \"skill:Arden/Guns (Pistol)\") and [the rules](wiki:ref:B123 \"ref:B123\").\n```\n[[sheet:Arden]]\n```\n", text)
+	if assert.Len(t, links, 2) {
+		assert.Equal(t, "Arden", links[0].Owner())
+		assert.Equal(t, "Guns (Pistol)", links[0].Item())
+		parsed := model.ParseWikiLinkURL(links[0].URL())
+		if assert.NotNil(t, parsed) {
+			assert.Equal(t, model.WikiSkill, parsed.Kind)
+			assert.Equal(t, "Arden/Guns (Pistol)", parsed.Target)
+		}
+	}
+	assert.Nil(t, model.ParseWikiLinkURL("https://gurpscharactersheet.com"))
+
+	entity := model.NewEntity(model.PC)
+	entity.Profile.Name = "Arden"
+	skill := model.NewSkill(entity, nil, false)
+	skill.Name = "Broadsword"
+	entity.SetSkillList([]*model.Skill{skill})
+	_, rowID, ok := entity.WikiText(&model.WikiLink{Kind: model.WikiSkill, Target: "Arden/broadsword"})
+	assert.True(t, ok)
+	assert.Equal(t, skill.ID, rowID)
+	_, _, ok = entity.WikiText(&model.WikiLink{Kind: model.WikiSkill, Target: "Arden/Axe/Mace"})
+	assert.False(t, ok)
+}
@@ -33,6 +33,9 @@ var licenseMarkdownContent string
 var (
 	addNaturalAttacksAction             *unison.Action
 	applyTemplateAction                 *unison.Action
+	campaignSettingsAction              *unison.Action
+	campaignClockAction                 *unison.Action
+	castSpellAction                     *unison.Action
 	clearPortraitAction                 *unison.Action
 	closeTabAction                      *unison.Action
 	colorSettingsAction                 *unison.Action
@@ -48,16 +51,21 @@ var (
 	defaultBodyTypeSettingsAction       *unison.Action
 	defaultSheetSettingsAction          *unison.Action
 	duplicateAction                     *unison.Action
+	exportAsBundleAction                *unison.Action
 	exportAsJPEGAction                  *unison.Action
 	exportAsPDFAction                   *unison.Action
+	exportCardsAction                   *unison.Action
 	exportAsPNGAction                   *unison.Action
 	exportAsWEBPAction                  *unison.Action
 	fontSettingsAction                  *unison.Action
 	generalSettingsAction               *unison.Action
+	generateNPCsAction                  *unison.Action
 	increaseSkillLevelAction            *unison.Action
 	increaseTechLevelAction             *unison.Action
 	increaseUsesAction                  *unison.Action
+	importBundleAction                  *unison.Action
 	incrementAction                     *unison.Action
+	linkToCampaignAction                *unison.Action
 	menuKeySettingsAction               *unison.Action
 	newCarriedEquipmentAction           *unison.Action
 	newCarriedEquipmentContainerAction  *unison.Action
@@ -74,6 +82,7 @@ var (
 	newNotesLibraryAction               *unison.Action
 	newOtherEquipmentAction             *unison.Action
 	newOtherEquipmentContainerAction    *unison.Action
+	newRandomTableAction                *unison.Action
 	newRangedWeaponAction               *unison.Action
 	newRitualMagicSpellAction           *unison.Action
 	newSkillAction                      *unison.Action
@@ -98,7 +107,10 @@ var (
 	perSheetBodyTypeSettingsAction      *unison.Action
 	perSheetSettingsAction              *unison.Action
 	printAction                         *unison.Action
+	reactionRollAction                  *unison.Action
 	redoAction                          *unison.Action
+	searchRulebooksAction               *unison.Action
+	commandPaletteAction                *unison.Action
 	saveAction                          *unison.Action
 	saveAsAction                        *unison.Action
 	scale25Action                       *unison.Action
@@ -113,7 +125,9 @@ var (
 	scaleDefaultAction                  *unison.Action
 	scaleDownAction                     *unison.Action
 	scaleUpAction                       *unison.Action
+	studyPlannerAction                  *unison.Action
 	swapDefaultsAction                  *unison.Action
+	toggleFeaturesAction                *unison.Action
 	toggleStateAction                   *unison.Action
 	undoAction                          *unison.Action
 )
@@ -153,6 +167,22 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	campaignSettingsAction = registerKeyBindableAction("settings.campaign", &unison.Action{
+		ID:              CampaignSettingsItemID,
+		Title:           i18n.Text("Campaign Settings…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowCampaignSettings() },
+	})
+	campaignClockAction = registerKeyBindableAction("campaign.clock", &unison.Action{
+		ID:              CampaignClockItemID,
+		Title:           i18n.Text("Campaign Clock…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowCampaignClock() },
+	})
+	castSpellAction = registerKeyBindableAction("cast.spell", &unison.Action{
+		ID:              CastSpellItemID,
+		Title:           i18n.Text("Cast Spell…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	clearPortraitAction = registerKeyBindableAction("clear.portrait", &unison.Action{
 		ID:              ClearPortraitItemID,
 		Title:           i18n.Text("Clear Portrait"),
@@ -275,6 +305,16 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	exportAsBundleAction = registerKeyBindableAction("export.bundle", &unison.Action{
+		ID:              ExportAsBundleItemID,
+		Title:           i18n.Text("Character Bundle…"),
+		EnabledCallback: actionEnabledForSheet,
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if s := ActiveSheet(); s != nil {
+				ExportCharacterBundle(s)
+			}
+		},
+	})
 	exportAsJPEGAction = registerKeyBindableAction("export.jpeg", &unison.Action{
 		ID:              ExportAsJPEGItemID,
 		Title:           i18n.Text("JPEG"),
@@ -288,6 +328,12 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	exportCardsAction = registerKeyBindableAction("export.cards", &unison.Action{
+		ID:              ExportCardsItemID,
+		Title:           i18n.Text("Cards (PDF)…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	exportAsPNGAction = registerKeyBindableAction("export.png", &unison.Action{
 		ID:              ExportAsPNGItemID,
 		Title:           i18n.Text("PNG"),
@@ -310,6 +356,11 @@ func registerActions() {
 		Title:           i18n.Text("General Settings…"),
 		ExecuteCallback: func(_ *unison.Action, _ any) { ShowGeneralSettings() },
 	})
+	generateNPCsAction = registerKeyBindableAction("new.npcs", &unison.Action{
+		ID:              GenerateNPCsItemID,
+		Title:           i18n.Text("Generate NPCs…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowNPCGenerator() },
+	})
 	increaseSkillLevelAction = registerKeyBindableAction("inc.sl", &unison.Action{
 		ID:              IncrementSkillLevelItemID,
 		Title:           i18n.Text("Increase Skill Level"),
@@ -331,6 +382,11 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	importBundleAction = registerKeyBindableAction("import.bundle", &unison.Action{
+		ID:              ImportBundleItemID,
+		Title:           i18n.Text("Import Character Bundle…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ImportCharacterBundle() },
+	})
 	incrementAction = registerKeyBindableAction("inc", &unison.Action{
 		ID:              IncrementItemID,
 		Title:           i18n.Text("Increment"),
@@ -338,6 +394,24 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	linkToCampaignAction = registerKeyBindableAction("settings.campaign.link", &unison.Action{
+		ID:    LinkToCampaignItemID,
+		Title: i18n.Text("Link to Campaign"),
+		EnabledCallback: func(_ *unison.Action, _ any) bool {
+			return ActiveSheet() != nil && model.GlobalSettings().ActiveCampaign() != nil
+		},
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			if s := ActiveSheet(); s != nil {
+				if c := model.GlobalSettings().ActiveCampaign(); c != nil {
+					if unison.QuestionDialog(fmt.Sprintf(i18n.Text("Link this sheet to the campaign %s?"), c.Name),
+						i18n.Text("The sheet's settings will be replaced by the campaign's settings.")) == unison.ModalResponseOK {
+						c.ApplyTo(s.Entity())
+						s.SheetSettingsUpdated(s.Entity(), true)
+					}
+				}
+			}
+		},
+	})
 	menuKeySettingsAction = registerKeyBindableAction("settings.keys", &unison.Action{
 		ID:              MenuKeySettingsItemID,
 		Title:           i18n.Text("Menu Keys…"),
@@ -442,6 +516,13 @@ func registerActions() {
 			DisplayNewDockable(nil, NewNoteTableDockable("Notes"+model.NotesExt, nil))
 		},
 	})
+	newRandomTableAction = registerKeyBindableAction("new.rtbl", &unison.Action{
+		ID:    NewRandomTableItemID,
+		Title: i18n.Text("New Random Table"),
+		ExecuteCallback: func(_ *unison.Action, _ any) {
+			DisplayNewDockable(nil, NewUntitledRandomTableDockable("untitled", model.NewRandomTable(), true))
+		},
+	})
 	newOtherEquipmentAction = registerKeyBindableAction("new.eqp.other", &unison.Action{
 		ID:              NewOtherEquipmentItemID,
 		Title:           i18n.Text("New Other Equipment"),
@@ -603,6 +684,17 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	searchRulebooksAction = registerKeyBindableAction("pageref.search", &unison.Action{
+		ID:              SearchRulebooksItemID,
+		Title:           i18n.Text("Search Rulebooks…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowPDFSearch() },
+	})
+	commandPaletteAction = registerKeyBindableAction("command.palette", &unison.Action{
+		ID:              CommandPaletteItemID,
+		Title:           i18n.Text("Command Palette…"),
+		KeyBinding:      unison.KeyBinding{KeyCode: unison.KeyP, Modifiers: unison.OptionModifier | unison.OSMenuCmdModifier()},
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowCommandPalette() },
+	})
 	pageRefMappingsAction = registerKeyBindableAction("settings.pagerefs", &unison.Action{
 		ID:              PageRefMappingsItemID,
 		Title:           i18n.Text("Page Reference Mappings…"),
@@ -646,6 +738,11 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	reactionRollAction = registerKeyBindableAction("reaction.roll", &unison.Action{
+		ID:              ReactionRollItemID,
+		Title:           i18n.Text("Reaction Roll…"),
+		ExecuteCallback: func(_ *unison.Action, _ any) { ShowReactionRoll() },
+	})
 	redoAction = registerKeyBindableAction("redo", &unison.Action{
 		ID:         RedoItemID,
 		Title:      unison.CannotRedoTitle(),
@@ -766,6 +863,12 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	studyPlannerAction = registerKeyBindableAction("study.planner", &unison.Action{
+		ID:              StudyPlannerItemID,
+		Title:           i18n.Text("Study Planner…"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	swapDefaultsAction = registerKeyBindableAction("swap.defaults", &unison.Action{
 		ID:              SwapDefaultsItemID,
 		Title:           i18n.Text("Swap Defaults"),
@@ -773,6 +876,12 @@ func registerActions() {
 		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
 		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
 	})
+	toggleFeaturesAction = registerKeyBindableAction("toggle.features", &unison.Action{
+		ID:              ToggleFeaturesItemID,
+		Title:           i18n.Text("Toggle Situational Features"),
+		EnabledCallback: unison.RouteActionToFocusEnabledFunc,
+		ExecuteCallback: unison.RouteActionToFocusExecuteFunc,
+	})
 	toggleStateAction = registerKeyBindableAction("toggle", &unison.Action{
 		ID:              ToggleStateItemID,
 		Title:           i18n.Text("Toggle State"),
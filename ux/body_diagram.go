@@ -0,0 +1,304 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio"
+	"github.com/richardwilkes/toolbox/xmath"
+	"github.com/richardwilkes/unison"
+)
+
+const (
+	bodyDiagramWidth       = 10
+	bodyDiagramHeight      = 15
+	bodyDiagramExtraWidth  = 4
+	bodyDiagramExtraHeight = 1
+	bodyDiagramUnit        = 12
+)
+
+// bodyDiagramSlots maps hit location IDs to the areas they occupy on a schematic figure, in units of the diagram's
+// grid. When more than one location shares an ID, each takes the next area in turn (the character's right side is
+// drawn on the viewer's left); a lone location takes all of them.
+var bodyDiagramSlots = map[string][]unison.Rect{
+	"skull":     {{Point: unison.Point{X: 4, Y: 0}, Size: unison.Size{Width: 2, Height: 1}}},
+	"eye":       {{Point: unison.Point{X: 4, Y: 1}, Size: unison.Size{Width: 2, Height: 0.6}}},
+	"face":      {{Point: unison.Point{X: 4, Y: 1.6}, Size: unison.Size{Width: 2, Height: 0.9}}},
+	"neck":      {{Point: unison.Point{X: 4.4, Y: 2.5}, Size: unison.Size{Width: 1.2, Height: 0.7}}},
+	"torso":     {{Point: unison.Point{X: 3, Y: 3.2}, Size: unison.Size{Width: 4, Height: 4}}},
+	"vitals":    {{Point: unison.Point{X: 4, Y: 4.2}, Size: unison.Size{Width: 2, Height: 1.6}}},
+	"groin":     {{Point: unison.Point{X: 3.4, Y: 7.2}, Size: unison.Size{Width: 3.2, Height: 1.2}}},
+	"arm":       bodyDiagramPair(1.5, 3.2, 1.4, 4.5, 7.1),
+	"limb":      bodyDiagramPair(1.5, 3.2, 1.4, 4.5, 7.1),
+	"hand":      bodyDiagramPair(1.5, 7.8, 1.4, 1, 7.1),
+	"extremity": bodyDiagramPair(1.5, 7.8, 1.4, 1, 7.1),
+	"wing":      bodyDiagramPair(0, 3.2, 1.4, 3, 8.6),
+	"fin":       bodyDiagramPair(0, 3.2, 1.4, 3, 8.6),
+	"leg":       bodyDiagramPair(3.2, 8.5, 1.7, 5.5, 5.1),
+	"foot":      bodyDiagramPair(2.7, 14, 2.2, 1, 5.1),
+	"tail":      {{Point: unison.Point{X: 7.2, Y: 8.6}, Size: unison.Size{Width: 2.6, Height: 0.8}}},
+}
+
+func bodyDiagramPair(x, y, width, height, otherX float32) []unison.Rect {
+	return []unison.Rect{
+		{Point: unison.Point{X: x, Y: y}, Size: unison.Size{Width: width, Height: height}},
+		{Point: unison.Point{X: otherX, Y: y}, Size: unison.Size{Width: width, Height: height}},
+	}
+}
+
+// BodyDiagram draws the top-level hit locations of the body type on a schematic figure, showing the DR of each and
+// highlighting those that are injured or crippled. Locations the figure has no place for are drawn in a column beside
+// it.
+type BodyDiagram struct {
+	unison.Panel
+	entity *model.Entity
+	boxes  []*bodyDiagramBox
+	width  float32
+	height float32
+}
+
+type bodyDiagramBox struct {
+	location *model.HitLocation
+	areas    []unison.Rect
+}
+
+// NewBodyDiagram creates a new body diagram.
+func NewBodyDiagram(entity *model.Entity) *BodyDiagram {
+	d := &BodyDiagram{entity: entity}
+	d.Self = d
+	d.SetSizer(d.sizer)
+	d.DrawCallback = d.draw
+	d.UpdateTooltipCallback = d.updateTooltip
+	d.MouseDownCallback = d.mouseDown
+	d.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  6,
+		HAlign: unison.MiddleAlignment,
+	})
+	d.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(4)))
+	d.rebuild(model.SheetSettingsFor(entity).BodyType)
+	return d
+}
+
+func (d *BodyDiagram) rebuild(bodyType *model.Body) {
+	d.boxes = nil
+	d.width = bodyDiagramWidth
+	d.height = bodyDiagramHeight
+	counts := make(map[string]int)
+	for _, location := range bodyType.Locations {
+		counts[strings.ToLower(location.LocID)]++
+	}
+	used := make(map[string]int)
+	var extraY float32
+	for _, location := range bodyType.Locations {
+		id := strings.ToLower(location.LocID)
+		box := &bodyDiagramBox{location: location}
+		if slots := bodyDiagramSlots[id]; len(slots) != 0 {
+			switch {
+			case counts[id] == 1:
+				box.areas = slots
+			case used[id] < len(slots):
+				box.areas = slots[used[id] : used[id]+1]
+			}
+			used[id]++
+		}
+		if len(box.areas) == 0 {
+			box.areas = []unison.Rect{{
+				Point: unison.Point{X: bodyDiagramWidth + 0.5, Y: extraY},
+				Size:  unison.Size{Width: bodyDiagramExtraWidth, Height: bodyDiagramExtraHeight},
+			}}
+			extraY += bodyDiagramExtraHeight + 0.2
+			d.width = bodyDiagramWidth + 0.5 + bodyDiagramExtraWidth
+			d.height = xmath.Max(d.height, extraY)
+		}
+		d.boxes = append(d.boxes, box)
+	}
+}
+
+func (d *BodyDiagram) sizer(_ unison.Size) (minSize, prefSize, maxSize unison.Size) {
+	prefSize.Width = d.width * bodyDiagramUnit
+	prefSize.Height = d.height * bodyDiagramUnit
+	if border := d.Border(); border != nil {
+		insets := border.Insets()
+		prefSize.Width += insets.Left + insets.Right
+		prefSize.Height += insets.Top + insets.Bottom
+	}
+	return prefSize, prefSize, prefSize
+}
+
+// scale returns the scale and origin used to map the diagram's grid units into the panel's coordinates.
+func (d *BodyDiagram) scale() (scale float32, origin unison.Point) {
+	r := d.ContentRect(false)
+	scale = xmath.Min(r.Width/d.width, r.Height/d.height)
+	origin.X = r.X + (r.Width-d.width*scale)/2
+	origin.Y = r.Y + (r.Height-d.height*scale)/2
+	return scale, origin
+}
+
+func (d *BodyDiagram) areaRect(area unison.Rect, scale float32, origin unison.Point) unison.Rect {
+	return unison.Rect{
+		Point: unison.Point{X: origin.X + area.X*scale, Y: origin.Y + area.Y*scale},
+		Size:  unison.Size{Width: area.Width * scale, Height: area.Height * scale},
+	}
+}
+
+func (d *BodyDiagram) draw(gc *unison.Canvas, _ unison.Rect) {
+	scale, origin := d.scale()
+	for _, box := range d.boxes {
+		bg, fg := d.colors(box.location)
+		text := unison.NewText(box.location.DisplayDR(d.entity, nil), &unison.TextDecoration{
+			Font:       model.PageFieldSecondaryFont,
+			Foreground: fg,
+		})
+		for _, area := range box.areas {
+			r := d.areaRect(area, scale, origin)
+			gc.DrawRect(r, bg.Paint(gc, r, unison.Fill))
+			gc.DrawRect(r, unison.InteriorDividerColor.Paint(gc, r, unison.Stroke))
+			if text.Width() <= r.Width && text.Height() <= r.Height {
+				text.Draw(gc, r.X+(r.Width-text.Width())/2, r.Y+(r.Height-text.Height())/2+text.Baseline())
+			}
+		}
+	}
+}
+
+func (d *BodyDiagram) colors(location *model.HitLocation) (bg, fg unison.Ink) {
+	switch {
+	case d.entity.IsLocationCrippled(location):
+		return unison.ErrorColor, unison.OnErrorColor
+	case d.entity.LocationInjury(location) != nil:
+		return unison.WarningColor, unison.OnWarningColor
+	default:
+		return unison.ContentColor, unison.OnContentColor
+	}
+}
+
+func (d *BodyDiagram) boxAt(where unison.Point) (*bodyDiagramBox, unison.Rect) {
+	scale, origin := d.scale()
+	for i := len(d.boxes) - 1; i >= 0; i-- {
+		for _, area := range d.boxes[i].areas {
+			if r := d.areaRect(area, scale, origin); r.ContainsPoint(where) {
+				return d.boxes[i], r
+			}
+		}
+	}
+	return nil, unison.Rect{}
+}
+
+func (d *BodyDiagram) updateTooltip(where unison.Point, _ unison.Rect) unison.Rect {
+	box, r := d.boxAt(where)
+	if box == nil {
+		d.Tooltip = nil
+		return unison.Rect{}
+	}
+	var buffer strings.Builder
+	var drTooltip xio.ByteBuffer
+	fmt.Fprintf(&buffer, i18n.Text("%s\nDR: %s%s"), box.location.TableName, box.location.DisplayDR(d.entity, &drTooltip),
+		drTooltip.String())
+	if injury := d.entity.LocationInjury(box.location); injury != nil {
+		fmt.Fprintf(&buffer, i18n.Text("\nInjury: %s"), injury.Injury.String())
+		if threshold, ok := box.location.CripplingThreshold(d.entity); ok {
+			fmt.Fprintf(&buffer, i18n.Text(" (crippled above %s)"), threshold.String())
+		}
+		if d.entity.IsLocationCrippled(box.location) {
+			buffer.WriteString(i18n.Text("\nCrippled"))
+		}
+		if notes := strings.TrimSpace(injury.Notes); notes != "" {
+			buffer.WriteString("\n")
+			buffer.WriteString(notes)
+		}
+	}
+	buffer.WriteString(i18n.Text("\n\nClick to record injury"))
+	d.Tooltip = unison.NewTooltipWithText(buffer.String())
+	return d.RectToRoot(r)
+}
+
+func (d *BodyDiagram) mouseDown(where unison.Point, button, _ int, _ unison.Modifiers) bool {
+	if button != unison.ButtonLeft {
+		return false
+	}
+	if box, _ := d.boxAt(where); box != nil {
+		editLocationInjury(d, d.entity, box.location)
+	}
+	return true
+}
+
+type locationInjuriesUndoEdit = *unison.UndoEdit[[]*model.LocationInjury]
+
+func editLocationInjury(owner unison.Paneler, entity *model.Entity, location *model.HitLocation) {
+	injury := model.LocationInjury{Location: location.TableName}
+	if existing := entity.LocationInjury(location); existing != nil {
+		injury = *existing
+	}
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	title := NewFieldLeadingLabel(location.TableName)
+	title.Font = unison.SystemFont
+	title.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+	panel.AddChild(title)
+	text := i18n.Text("Injury")
+	panel.AddChild(NewFieldLeadingLabel(text))
+	field := NewDecimalField(nil, "", text,
+		func() fxp.Int { return injury.Injury },
+		func(v fxp.Int) { injury.Injury = v },
+		0, fxp.Max, false, false)
+	if threshold, ok := location.CripplingThreshold(entity); ok {
+		field.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text("Crippled when injury exceeds %s"),
+			threshold.String()))
+	}
+	panel.AddChild(field)
+	panel.AddChild(unison.NewPanel())
+	panel.AddChild(NewCheckBox(nil, "", i18n.Text("Crippled"),
+		func() unison.CheckState { return unison.CheckStateFromBool(injury.Crippled) },
+		func(state unison.CheckState) { injury.Crippled = state == unison.OnCheckState }))
+	text = i18n.Text("Notes")
+	panel.AddChild(NewFieldLeadingLabel(text))
+	notesField := NewMultiLineStringField(nil, "", text,
+		func() string { return injury.Notes },
+		func(s string) { injury.Notes = s })
+	notesField.SetMinimumTextWidthUsing(prototypeMinNameWidth)
+	panel.AddChild(notesField)
+	dialog, err := unison.NewDialog(nil, nil, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create injury dialog"), err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	before := append([]*model.LocationInjury(nil), entity.LocationInjuries...)
+	entity.SetLocationInjury(&injury)
+	after := append([]*model.LocationInjury(nil), entity.LocationInjuries...)
+	if mgr := unison.UndoManagerFor(owner); mgr != nil {
+		apply := func(list []*model.LocationInjury) {
+			entity.LocationInjuries = append([]*model.LocationInjury(nil), list...)
+			MarkModified(owner)
+		}
+		mgr.Add(&unison.UndoEdit[[]*model.LocationInjury]{
+			ID:         unison.NextUndoID(),
+			EditName:   fmt.Sprintf(i18n.Text("Record Injury to %s"), location.TableName),
+			UndoFunc:   func(edit locationInjuriesUndoEdit) { apply(edit.BeforeData) },
+			RedoFunc:   func(edit locationInjuriesUndoEdit) { apply(edit.AfterData) },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	MarkModified(owner)
+}
@@ -29,6 +29,7 @@ type BodyPanel struct {
 	row           []unison.Paneler
 	sepLayoutData []*unison.FlexLayoutData
 	crc           uint64
+	showDiagram   bool
 }
 
 // NewBodyPanel creates a new body panel.
@@ -44,8 +45,10 @@ func NewBodyPanel(entity *model.Entity) *BodyPanel {
 		VAlign: unison.FillAlignment,
 		VSpan:  3,
 	})
-	locations := model.SheetSettingsFor(entity).BodyType
+	sheetSettings := model.SheetSettingsFor(entity)
+	locations := sheetSettings.BodyType
 	p.crc = locations.CRC64()
+	p.showDiagram = sheetSettings.ShowBodyDiagram
 	p.titledBorder = &TitledBorder{Title: locations.Name}
 	p.SetBorder(unison.NewCompoundBorder(p.titledBorder, unison.NewEmptyBorder(unison.Insets{
 		Left:   2,
@@ -88,6 +91,9 @@ func (p *BodyPanel) addContent(locations *model.Body) {
 	for _, one := range p.sepLayoutData {
 		one.VSpan = len(p.row)
 	}
+	if p.showDiagram {
+		p.AddChild(NewBodyDiagram(p.entity))
+	}
 }
 
 func (p *BodyPanel) addTable(bodyType *model.Body, depth int) {
@@ -182,9 +188,11 @@ func (p *BodyPanel) addSeparator() {
 
 // Sync the panel to the current data.
 func (p *BodyPanel) Sync() {
-	locations := model.SheetSettingsFor(p.entity).BodyType
-	if crc := locations.CRC64(); crc != p.crc {
+	sheetSettings := model.SheetSettingsFor(p.entity)
+	locations := sheetSettings.BodyType
+	if crc := locations.CRC64(); crc != p.crc || sheetSettings.ShowBodyDiagram != p.showDiagram {
 		p.crc = crc
+		p.showDiagram = sheetSettings.ShowBodyDiagram
 		p.titledBorder.Title = locations.Name
 		p.addContent(locations)
 		MarkForLayoutWithinDockable(p)
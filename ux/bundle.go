@@ -0,0 +1,125 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// ExportCharacterBundle asks the user for a destination and then exports the sheet as a character bundle.
+func ExportCharacterBundle(sheet *Sheet) {
+	dialog := unison.NewSaveDialog()
+	settings := model.GlobalSettings()
+	dialog.SetInitialDirectory(settings.LastDir(model.DefaultLastDirKey))
+	dialog.SetAllowedExtensions(model.BundleExt)
+	if dialog.RunModal() {
+		if filePath, ok := unison.ValidateSaveFilePath(dialog.Path(), model.BundleExt, false); ok {
+			settings.SetLastDir(model.DefaultLastDirKey, filepath.Dir(filePath))
+			if err := model.ExportBundle(sheet.Entity(), filePath); err != nil {
+				unison.ErrorDialogWithError(i18n.Text("Unable to export character bundle"), err)
+			}
+		}
+	}
+}
+
+// ImportCharacterBundle asks the user for a character bundle and the library to place its contents into, then
+// extracts it and opens the sheet it contained.
+func ImportCharacterBundle() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(false)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(model.BundleExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	settings := model.GlobalSettings()
+	dialog.SetInitialDirectory(settings.LastDir(model.DefaultLastDirKey))
+	if !dialog.RunModal() {
+		return
+	}
+	bundlePath := dialog.Path()
+	settings.SetLastDir(model.DefaultLastDirKey, filepath.Dir(bundlePath))
+	lib := askForBundleLibrary()
+	if lib == nil {
+		return
+	}
+	sheetPath, err := model.ImportBundle(bundlePath, lib, askForBundleConflictAction)
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to import character bundle"), err)
+		return
+	}
+	OpenFile(nil, sheetPath)
+}
+
+func askForBundleLibrary() *model.Library {
+	popup := unison.NewPopupMenu[string]()
+	libs := model.GlobalSettings().Libraries()
+	candidates := make(map[string]*model.Library)
+	for _, lib := range libs.List() {
+		if !lib.IsMaster() {
+			candidates[lib.Title] = lib
+			popup.AddItem(lib.Title)
+		}
+	}
+	popup.Select(libs.User().Title)
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Import Into")))
+	panel.AddChild(popup)
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), unison.NewOKButtonInfo()})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create import dialog"), err)
+		return nil
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return nil
+	}
+	title, _ := popup.Selected()
+	return candidates[title]
+}
+
+func askForBundleConflictAction(relPath string) model.BundleConflictAction {
+	popup := unison.NewPopupMenu[model.BundleConflictAction]()
+	for _, one := range model.AllBundleConflictAction {
+		popup.AddItem(one)
+	}
+	popup.Select(model.KeepExistingBundleConflictAction)
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("A different version of %s already exists."), relPath)
+	panel.AddChild(label)
+	panel.AddChild(popup)
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon,
+		unison.DefaultDialogTheme.QuestionIconInk, panel, []*unison.DialogButtonInfo{unison.NewOKButtonInfo()})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create import dialog"), err)
+		return model.KeepExistingBundleConflictAction
+	}
+	dialog.RunModal()
+	action, _ := popup.Selected()
+	return action
+}
@@ -0,0 +1,537 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/rpgtools/calendar"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	_ unison.Dockable  = &campaignClockDockable{}
+	_ unison.TabCloser = &campaignClockDockable{}
+)
+
+type clockUnit struct {
+	title   string
+	minutes int
+}
+
+type campaignClockDockable struct {
+	unison.Panel
+	dateLabel     *unison.Label
+	calendarLabel *unison.Label
+	amount        int
+	unit          *clockUnit
+	dateField     *unison.Field
+	timeField     *unison.Field
+	characters    *unison.Panel
+	events        *unison.Panel
+	eventField    *unison.Field
+}
+
+// agingCharacter holds a character whose age is tracked by the campaign clock.
+type agingCharacter struct {
+	sheet *Sheet
+	cal   *calendar.Calendar
+	month int
+	day   int
+}
+
+func (u *clockUnit) String() string {
+	return u.title
+}
+
+// ShowCampaignClock shows the campaign clock.
+func ShowCampaignClock() {
+	ws, dc, found := Activate(func(d unison.Dockable) bool {
+		_, ok := d.(*campaignClockDockable)
+		return ok
+	})
+	if !found && ws != nil {
+		d := &campaignClockDockable{amount: 1}
+		d.Self = d
+		d.SetLayout(&unison.FlexLayout{Columns: 1})
+		model.GlobalSettings().EnsureCampaignClock()
+		content := unison.NewPanel()
+		content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(unison.StdHSpacing * 2)))
+		content.SetLayout(&unison.FlexLayout{
+			Columns:  1,
+			VSpacing: unison.StdVSpacing * 2,
+		})
+		content.AddChild(d.createDateBlock())
+		content.AddChild(d.createAdvanceBlock())
+		content.AddChild(d.createSetBlock())
+		d.characters = createClockSection(content, i18n.Text("Characters"))
+		d.events = createClockSection(content, i18n.Text("Event Log"))
+		content.AddChild(d.createEventEntryBlock())
+		scroll := unison.NewScrollPanel()
+		scroll.SetContent(content, unison.HintedFillBehavior, unison.FillBehavior)
+		scroll.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			VAlign: unison.FillAlignment,
+			HGrab:  true,
+			VGrab:  true,
+		})
+		d.AddChild(scroll)
+		d.sync()
+		PlaceInDock(ws, dc, d, EditorGroup)
+	}
+}
+
+// UpdateCampaignClock refreshes the campaign clock, if it is being shown, to reflect changes made to sheets.
+func UpdateCampaignClock() {
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, one := range dc.Dockables() {
+					if d, ok := one.(*campaignClockDockable); ok {
+						d.syncCharacters()
+						return true
+					}
+				}
+				return false
+			})
+		}
+	}
+}
+
+func createClockSection(content *unison.Panel, title string) *unison.Panel {
+	label := unison.NewLabel()
+	label.Text = title
+	label.Font = unison.SystemFont
+	label.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.Insets{Bottom: 1},
+		false), unison.NewEmptyBorder(unison.Insets{Top: unison.StdVSpacing})))
+	label.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	content.AddChild(label)
+	section := unison.NewPanel()
+	section.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	content.AddChild(section)
+	return section
+}
+
+func (d *campaignClockDockable) createDateBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{Columns: 1})
+	d.dateLabel = unison.NewLabel()
+	d.dateLabel.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			fd := unison.SystemFont.Descriptor()
+			fd.Size *= 1.5
+			return fd
+		},
+	}
+	panel.AddChild(d.dateLabel)
+	d.calendarLabel = unison.NewLabel()
+	d.calendarLabel.Font = model.FieldSecondaryFont
+	panel.AddChild(d.calendarLabel)
+	return panel
+}
+
+func (d *campaignClockDockable) createAdvanceBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.AddChild(NewIntegerField(nil, "", i18n.Text("Amount"),
+		func() int { return d.amount },
+		func(value int) { d.amount = value },
+		1, 9999, false, false))
+	units := []*clockUnit{
+		{title: i18n.Text("Minutes"), minutes: 1},
+		{title: i18n.Text("Hours"), minutes: model.MinutesPerHour},
+		{title: i18n.Text("Days"), minutes: model.MinutesPerDay},
+		{title: i18n.Text("Weeks"), minutes: model.MinutesPerWeek},
+	}
+	d.unit = units[1]
+	addPopup(panel, units, &d.unit)
+	advance := unison.NewButton()
+	advance.Text = i18n.Text("Advance")
+	advance.ClickCallback = func() { d.advance(d.amount * d.unit.minutes) }
+	panel.AddChild(advance)
+	rewind := unison.NewButton()
+	rewind.Text = i18n.Text("Rewind")
+	rewind.ClickCallback = func() { d.advance(-d.amount * d.unit.minutes) }
+	panel.AddChild(rewind)
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  len(panel.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	return panel
+}
+
+func (d *campaignClockDockable) createSetBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Date")))
+	d.dateField = unison.NewField()
+	d.dateField.ValidateCallback = func() bool {
+		_, err := d.calendar().ParseDate(strings.TrimSpace(d.dateField.Text()))
+		return err == nil
+	}
+	d.dateField.SetMinimumTextWidthUsing(d.calendar().NewDateByDays(0).String() + "0000")
+	panel.AddChild(d.dateField)
+	panel.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Time")))
+	d.timeField = unison.NewField()
+	d.timeField.ValidateCallback = func() bool {
+		_, _, ok := model.ParseTimeOfDay(d.timeField.Text())
+		return ok
+	}
+	d.timeField.SetMinimumTextWidthUsing("00:00")
+	panel.AddChild(d.timeField)
+	set := unison.NewButton()
+	set.Text = i18n.Text("Set")
+	set.ClickCallback = d.setFromFields
+	panel.AddChild(set)
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  len(panel.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	return panel
+}
+
+func (d *campaignClockDockable) createEventEntryBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	d.eventField = unison.NewField()
+	d.eventField.Watermark = i18n.Text("Describe an event to log at the current time")
+	d.eventField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	d.eventField.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if keyCode == unison.KeyReturn || keyCode == unison.KeyNumPadEnter {
+			d.logEvent()
+			return true
+		}
+		return d.eventField.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	panel.AddChild(d.eventField)
+	logButton := unison.NewButton()
+	logButton.Text = i18n.Text("Log Event")
+	logButton.ClickCallback = d.logEvent
+	panel.AddChild(logButton)
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	return panel
+}
+
+func (d *campaignClockDockable) clock() *model.CampaignClock {
+	return model.GlobalSettings().EnsureCampaignClock()
+}
+
+func (d *campaignClockDockable) calendarRef() *model.CalendarRef {
+	return model.GlobalSettings().CampaignCalendarRef()
+}
+
+func (d *campaignClockDockable) calendar() *calendar.Calendar {
+	return d.calendarRef().Calendar
+}
+
+func (d *campaignClockDockable) advance(minutes int) {
+	clock := d.clock()
+	d.changeTime(clock.Minutes + minutes)
+}
+
+func (d *campaignClockDockable) setFromFields() {
+	clock := d.clock()
+	date, err := d.calendar().ParseDate(strings.TrimSpace(d.dateField.Text()))
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Invalid date"), err)
+		return
+	}
+	hour, minute, ok := model.ParseTimeOfDay(d.timeField.Text())
+	if !ok {
+		unison.ErrorDialogWithMessage(i18n.Text("Invalid time"), i18n.Text("Times must be entered as hours and minutes, e.g. 14:05"))
+		return
+	}
+	revised := model.CampaignClock{Minutes: clock.Minutes}
+	revised.SetDate(date)
+	revised.SetTimeOfDay(hour, minute)
+	d.changeTime(revised.Minutes)
+}
+
+func (d *campaignClockDockable) changeTime(minutes int) {
+	clock := d.clock()
+	fromDays := clock.Date(d.calendar()).Days
+	clock.Minutes = minutes
+	d.checkBirthdays(fromDays, clock.Date(d.calendar()).Days)
+	d.sync()
+}
+
+// checkBirthdays looks for birthdays of the tracked characters that were passed when the date changed and offers to
+// update their ages.
+func (d *campaignClockDockable) checkBirthdays(fromDays, toDays int) {
+	if fromDays == toDays {
+		return
+	}
+	clock := d.clock()
+	type ageChange struct {
+		sheet  *Sheet
+		newAge int
+	}
+	var changes []*ageChange
+	var lines []string
+	for _, one := range agingCharacters() {
+		count := model.BirthdaysBetween(one.cal, one.month, one.day, fromDays, toDays)
+		if count == 0 {
+			continue
+		}
+		name := one.sheet.entity.Profile.Name
+		age, err := strconv.Atoi(strings.TrimSpace(one.sheet.entity.Profile.Age))
+		if err != nil {
+			lines = append(lines, fmt.Sprintf(i18n.Text("%s passed %d birthdays."), name, count))
+			continue
+		}
+		change := &ageChange{
+			sheet:  one.sheet,
+			newAge: age + count,
+		}
+		changes = append(changes, change)
+		lines = append(lines, fmt.Sprintf(i18n.Text("%s is now %d (was %d)."), name, change.newAge, age))
+		if interval := model.AgingRollInterval(change.newAge); interval != 0 {
+			lines = append(lines, fmt.Sprintf(i18n.Text("%s must make an aging roll every %d months (p. B444)."), name,
+				interval))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	buttons := []*unison.DialogButtonInfo{unison.NewOKButtonInfo()}
+	if len(changes) != 0 {
+		buttons = []*unison.DialogButtonInfo{
+			{
+				Title:        i18n.Text("Leave Ages"),
+				ResponseCode: unison.ModalResponseCancel,
+				KeyCodes:     []unison.KeyCode{unison.KeyEscape},
+			},
+			unison.NewOKButtonInfoWithTitle(i18n.Text("Update Ages")),
+		}
+	}
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		unison.NewMessagePanel(i18n.Text("Birthdays"), strings.Join(lines, "\n")), buttons)
+	if err != nil {
+		jot.Error(err)
+		return
+	}
+	if dialog.RunModal() == unison.ModalResponseOK {
+		for _, change := range changes {
+			updateAge(change.sheet, strconv.Itoa(change.newAge))
+		}
+		for _, line := range lines {
+			clock.Log(line)
+		}
+	}
+}
+
+// updateAge sets the age on the sheet as an undoable edit.
+func updateAge(sheet *Sheet, age string) {
+	entity := sheet.entity
+	apply := func(value string) {
+		entity.Profile.Age = value
+		sheet.Rebuild(true)
+		sheet.MarkModified(nil)
+	}
+	if mgr := unison.UndoManagerFor(sheet); mgr != nil {
+		mgr.Add(&unison.UndoEdit[string]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Update Age"),
+			UndoFunc:   func(edit *unison.UndoEdit[string]) { apply(edit.BeforeData) },
+			RedoFunc:   func(edit *unison.UndoEdit[string]) { apply(edit.AfterData) },
+			BeforeData: entity.Profile.Age,
+			AfterData:  age,
+		})
+	}
+	apply(age)
+}
+
+func (d *campaignClockDockable) logEvent() {
+	text := strings.TrimSpace(d.eventField.Text())
+	if text == "" {
+		return
+	}
+	d.clock().Log(text)
+	d.eventField.SetText("")
+	d.syncEvents()
+}
+
+func (d *campaignClockDockable) sync() {
+	clock := d.clock()
+	cal := d.calendar()
+	d.dateLabel.Text = clock.Format(cal)
+	d.calendarLabel.Text = fmt.Sprintf(i18n.Text("Calendar: %s"), d.calendarRef().Name)
+	d.dateField.SetText(clock.Date(cal).String())
+	hour, minute := clock.TimeOfDay()
+	d.timeField.SetText(fmt.Sprintf("%02d:%02d", hour, minute))
+	d.syncCharacters()
+	d.syncEvents()
+}
+
+// agingCharacters returns the open sheets that belong to the campaign and have a birthday.
+func agingCharacters() []*agingCharacter {
+	s := model.GlobalSettings()
+	var list []*agingCharacter
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, one := range dc.Dockables() {
+					sheet, ok := one.(*Sheet)
+					if !ok || (s.ActiveCampaign() != nil && s.CampaignFor(sheet.entity) == nil) {
+						continue
+					}
+					cal := s.CalendarRefFor(sheet.entity).Calendar
+					if month, day, valid := model.ParseBirthday(cal, sheet.entity.Profile.Birthday); valid {
+						list = append(list, &agingCharacter{
+							sheet: sheet,
+							cal:   cal,
+							month: month,
+							day:   day,
+						})
+					}
+				}
+				return false
+			})
+		}
+	}
+	return list
+}
+
+func (d *campaignClockDockable) syncCharacters() {
+	d.characters.RemoveAllChildren()
+	d.characters.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	clock := d.clock()
+	list := agingCharacters()
+	if len(list) == 0 {
+		label := NewFieldTrailingLabel(i18n.Text("Open the sheets of characters with a birthday to track their ages."))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 4})
+		d.characters.AddChild(label)
+	}
+	for _, one := range list {
+		profile := one.sheet.entity.Profile
+		d.characters.AddChild(NewFieldTrailingLabel(profile.Name))
+		age := profile.Age
+		if n, err := strconv.Atoi(strings.TrimSpace(age)); err == nil {
+			if interval := model.AgingRollInterval(n); interval != 0 {
+				age = fmt.Sprintf(i18n.Text("%d (aging rolls every %d months)"), n, interval)
+			}
+		}
+		d.characters.AddChild(NewFieldTrailingLabel(fmt.Sprintf(i18n.Text("Age %s"), age)))
+		d.characters.AddChild(NewFieldTrailingLabel(fmt.Sprintf(i18n.Text("Birthday %s"), profile.Birthday)))
+		today := clock.Date(one.cal).Days
+		next := model.NextBirthday(one.cal, one.month, one.day, today)
+		var when string
+		switch days := next.Days - today; days {
+		case 0:
+			when = i18n.Text("Birthday is today")
+		case 1:
+			when = i18n.Text("Birthday is tomorrow")
+		default:
+			when = fmt.Sprintf(i18n.Text("Next birthday in %d days"), days)
+		}
+		d.characters.AddChild(NewFieldTrailingLabel(when))
+	}
+	d.characters.MarkForLayoutAndRedraw()
+}
+
+func (d *campaignClockDockable) syncEvents() {
+	d.events.RemoveAllChildren()
+	d.events.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	clock := d.clock()
+	cal := d.calendar()
+	if len(clock.Events) == 0 {
+		label := NewFieldTrailingLabel(i18n.Text("No events have been logged."))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+		d.events.AddChild(label)
+	}
+	for i := len(clock.Events) - 1; i >= 0; i-- {
+		event := clock.Events[i]
+		deleteButton := unison.NewSVGButton(svg.Trash)
+		deleteButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove Event"))
+		deleteButton.ClickCallback = func() {
+			if j := slices.Index(clock.Events, event); j != -1 {
+				clock.Events = slices.Delete(clock.Events, j, j+1)
+				d.syncEvents()
+			}
+		}
+		d.events.AddChild(deleteButton)
+		when := NewFieldTrailingLabel(model.FormatGameTime(cal, event.When))
+		when.Font = model.FieldSecondaryFont
+		d.events.AddChild(when)
+		d.events.AddChild(NewFieldTrailingLabel(event.Text))
+	}
+	d.events.MarkForLayoutAndRedraw()
+	d.MarkForLayoutRecursively()
+}
+
+// TitleIcon implements unison.Dockable
+func (d *campaignClockDockable) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Bookmark,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable
+func (d *campaignClockDockable) Title() string {
+	return i18n.Text("Campaign Clock")
+}
+
+func (d *campaignClockDockable) String() string {
+	return d.Title()
+}
+
+// Tooltip implements unison.Dockable
+func (d *campaignClockDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable
+func (d *campaignClockDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *campaignClockDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *campaignClockDockable) AttemptClose() bool {
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
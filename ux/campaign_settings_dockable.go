@@ -0,0 +1,278 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type campaignSettingsDockable struct {
+	SettingsDockable
+	content *unison.Panel
+}
+
+// ShowCampaignSettings the Campaign Settings window.
+func ShowCampaignSettings() {
+	ws, dc, found := Activate(func(d unison.Dockable) bool {
+		_, ok := d.(*campaignSettingsDockable)
+		return ok
+	})
+	if !found && ws != nil {
+		d := &campaignSettingsDockable{}
+		d.Self = d
+		d.TabTitle = i18n.Text("Campaign Settings")
+		d.TabIcon = svg.Settings
+		d.Extensions = []string{model.CampaignExt}
+		d.Loader = d.load
+		d.Saver = d.save
+		d.Resetter = d.reset
+		d.Setup(ws, dc, nil, nil, d.initContent)
+	}
+}
+
+func (d *campaignSettingsDockable) campaign() *model.Campaign {
+	s := model.GlobalSettings()
+	if s.Campaign == nil {
+		s.Campaign = model.NewCampaign()
+	}
+	return s.Campaign
+}
+
+func (d *campaignSettingsDockable) initContent(content *unison.Panel) {
+	d.content = content
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	c := d.campaign()
+	title := i18n.Text("Campaign Name")
+	content.AddChild(NewFieldLeadingLabel(title))
+	nameField := NewStringField(nil, "", title,
+		func() string { return c.Name },
+		func(s string) {
+			c.Name = strings.TrimSpace(s)
+			notifyOfCampaignChange()
+		})
+	nameField.Tooltip = unison.NewTooltipWithText(i18n.Text("The campaign is only in effect while it has a name"))
+	nameField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	content.AddChild(nameField)
+	d.createInitialPointsField(content, c)
+	d.createTechLevelField(content, c)
+	d.createControlRatingField(content, c)
+	d.createCalendarPopup(content, c)
+	d.createSheetSettingsBlock(content, c)
+	d.createPageRefsBlock(content, c)
+	d.createLibrariesBlock(content, c)
+	addLabelAndMultiLineStringField(content, i18n.Text("House Rules"), "", &c.HouseRules)
+}
+
+func (d *campaignSettingsDockable) createInitialPointsField(content *unison.Panel, c *model.Campaign) {
+	title := i18n.Text("Initial Points")
+	content.AddChild(NewFieldLeadingLabel(title))
+	field := NewDecimalField(nil, "", title,
+		func() fxp.Int { return c.InitialPoints },
+		func(v fxp.Int) { c.InitialPoints = v }, model.InitialPointsMin, model.InitialPointsMax, false, false)
+	field.Tooltip = unison.NewTooltipWithText(i18n.Text("Use 0 to defer to the general settings"))
+	content.AddChild(field)
+}
+
+func (d *campaignSettingsDockable) createTechLevelField(content *unison.Panel, c *model.Campaign) {
+	title := i18n.Text("Default Tech Level")
+	content.AddChild(NewFieldLeadingLabel(title))
+	field := NewStringField(nil, "", title,
+		func() string { return c.DefaultTechLevel },
+		func(s string) { c.DefaultTechLevel = strings.TrimSpace(s) })
+	field.Tooltip = unison.NewTooltipWithText(techLevelInfo())
+	field.Watermark = model.GlobalSettings().General.DefaultTechLevel
+	field.SetMinimumTextWidthUsing("12^")
+	content.AddChild(field)
+}
+
+func (d *campaignSettingsDockable) createControlRatingField(content *unison.Panel, c *model.Campaign) {
+	title := i18n.Text("Control Rating")
+	content.AddChild(NewFieldLeadingLabel(title))
+	field := NewIntegerField(nil, "", title,
+		func() int { return c.ControlRating },
+		func(v int) {
+			c.ControlRating = v
+			for _, sheet := range OpenSheets(nil) {
+				sheet.Rebuild(true)
+			}
+		}, model.ControlRatingMin, model.ControlRatingMax, false, false)
+	field.Tooltip = unison.NewTooltipWithText(i18n.Text(`Equipment with a legality class below this control rating is
+flagged as illegal. Use 0 to disable legality checks.`))
+	content.AddChild(field)
+}
+
+func (d *campaignSettingsDockable) createCalendarPopup(content *unison.Panel, c *model.Campaign) {
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Calendar")))
+	useGeneral := i18n.Text("Use General Settings")
+	popup := unison.NewPopupMenu[string]()
+	popup.AddItem(useGeneral)
+	for _, lib := range model.AvailableCalendarRefs(model.GlobalSettings().Libraries()) {
+		popup.AddDisabledItem(lib.Name)
+		for _, one := range lib.List {
+			popup.AddItem(one.Name)
+		}
+	}
+	if c.CalendarName == "" {
+		popup.Select(useGeneral)
+	} else {
+		popup.Select(c.CalendarName)
+	}
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[string]) {
+		if item, ok := p.Selected(); ok {
+			if item == useGeneral {
+				item = ""
+			}
+			c.CalendarName = item
+		}
+	}
+	content.AddChild(popup)
+}
+
+func (d *campaignSettingsDockable) createSheetSettingsBlock(content *unison.Panel, c *model.Campaign) {
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Sheet Settings")))
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+	})
+	label := unison.NewLabel()
+	if c.SheetSettings != nil {
+		label.Text = i18n.Text("Included")
+	} else {
+		label.Text = i18n.Text("Not included; new sheets use the default sheet settings")
+	}
+	label.SetLayoutData(&unison.FlexLayoutData{VAlign: unison.MiddleAlignment})
+	wrapper.AddChild(label)
+	captureButton := unison.NewButton()
+	captureButton.Text = i18n.Text("Use Default Sheet Settings")
+	captureButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Copy the current default sheet settings into the campaign"))
+	captureButton.ClickCallback = func() {
+		c.SheetSettings = model.GlobalSettings().Sheet.Clone(nil)
+		d.sync()
+	}
+	wrapper.AddChild(captureButton)
+	clearButton := unison.NewButton()
+	clearButton.Text = i18n.Text("Clear")
+	clearButton.SetEnabled(c.SheetSettings != nil)
+	clearButton.ClickCallback = func() {
+		c.SheetSettings = nil
+		d.sync()
+	}
+	wrapper.AddChild(clearButton)
+	content.AddChild(wrapper)
+}
+
+func (d *campaignSettingsDockable) createPageRefsBlock(content *unison.Panel, c *model.Campaign) {
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Page Reference Mappings")))
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+	})
+	count := len(c.PageRefs.List())
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("%d mapping(s)"), count)
+	label.SetLayoutData(&unison.FlexLayoutData{VAlign: unison.MiddleAlignment})
+	wrapper.AddChild(label)
+	captureButton := unison.NewButton()
+	captureButton.Text = i18n.Text("Use Current Mappings")
+	captureButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Copy the current page reference mappings into the campaign"))
+	captureButton.ClickCallback = func() {
+		c.PageRefs = model.PageRefs{}
+		for _, one := range model.GlobalSettings().PageRefs.List() {
+			c.PageRefs.Set(one)
+		}
+		d.sync()
+	}
+	wrapper.AddChild(captureButton)
+	clearButton := unison.NewButton()
+	clearButton.Text = i18n.Text("Clear")
+	clearButton.SetEnabled(count != 0)
+	clearButton.ClickCallback = func() {
+		c.PageRefs = model.PageRefs{}
+		d.sync()
+	}
+	wrapper.AddChild(clearButton)
+	content.AddChild(wrapper)
+}
+
+func (d *campaignSettingsDockable) createLibrariesBlock(content *unison.Panel, c *model.Campaign) {
+	label := NewFieldLeadingLabel(i18n.Text("Libraries"))
+	label.Tooltip = unison.NewTooltipWithText(i18n.Text("When no libraries are checked, all libraries are used"))
+	content.AddChild(label)
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, lib := range model.GlobalSettings().LibrarySet.List() {
+		lib := lib
+		wrapper.AddChild(NewCheckBox(nil, "", lib.Title,
+			func() unison.CheckState {
+				return unison.CheckStateFromBool(len(c.Libraries) != 0 && c.UsesLibrary(lib))
+			},
+			func(state unison.CheckState) {
+				c.SetUsesLibrary(lib, state == unison.OnCheckState)
+				notifyOfCampaignChange()
+			}))
+	}
+	content.AddChild(wrapper)
+}
+
+func (d *campaignSettingsDockable) reset() {
+	model.GlobalSettings().Campaign = model.NewCampaign()
+	d.sync()
+	notifyOfCampaignChange()
+}
+
+func (d *campaignSettingsDockable) sync() {
+	d.content.RemoveAllChildren()
+	d.initContent(d.content)
+	d.MarkForLayoutAndRedraw()
+}
+
+func (d *campaignSettingsDockable) load(fileSystem fs.FS, filePath string) error {
+	c, err := model.NewCampaignFromFile(fileSystem, filePath)
+	if err != nil {
+		return err
+	}
+	model.GlobalSettings().Campaign = c
+	d.sync()
+	notifyOfCampaignChange()
+	return nil
+}
+
+func (d *campaignSettingsDockable) save(filePath string) error {
+	return d.campaign().Save(filePath)
+}
+
+func notifyOfCampaignChange() {
+	if model.NotifyOfLibraryChangeFunc != nil {
+		toolbox.Call(model.NotifyOfLibraryChangeFunc)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+)
+
+var lastCardSize = model.PokerCardSize
+
+func canExportCards[T model.NodeTypes](table *unison.Table[*Node[T]]) bool {
+	for _, row := range table.SelectedRows(false) {
+		if model.NewCard(row.Data()) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func exportCards[T model.NodeTypes](entity *model.Entity, table *unison.Table[*Node[T]]) {
+	rows := table.SelectedRows(true)
+	data := make([]T, 0, len(rows))
+	for _, row := range rows {
+		data = append(data, row.Data())
+	}
+	cards := model.NewCards(data...)
+	if len(cards) == 0 {
+		return
+	}
+	size, ok := askForCardSize(entity, len(cards))
+	if !ok {
+		return
+	}
+	title := i18n.Text("Cards")
+	dir := model.GlobalSettings().LastDir(model.DefaultLastDirKey)
+	if d := unison.AncestorOrSelf[FileBackedDockable](table); d != nil {
+		if p := d.BackingFilePath(); p != "" {
+			dir = filepath.Dir(p)
+			title = fs.BaseName(p)
+		}
+	}
+	if entity != nil && entity.Profile.Name != "" {
+		title = entity.Profile.Name
+	}
+	table.Window().ShowCursor()
+	dialog := unison.NewSaveDialog()
+	dialog.SetInitialDirectory(dir)
+	dialog.SetAllowedExtensions("pdf")
+	if dialog.RunModal() {
+		if filePath, ok2 := unison.ValidateSaveFilePath(dialog.Path(), "pdf", false); ok2 {
+			model.GlobalSettings().SetLastDir(model.DefaultLastDirKey, filepath.Dir(filePath))
+			if err := newCardExporter(entity, title, cards, size).exportAsPDFFile(filePath); err != nil {
+				unison.ErrorDialogWithError(i18n.Text("Unable to export cards as PDF!"), err)
+			}
+		}
+	}
+}
+
+func askForCardSize(entity *model.Entity, count int) (model.CardSize, bool) {
+	settings := model.SheetSettingsFor(entity)
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Card Size")))
+	popup := unison.NewPopupMenu[model.CardSize]()
+	for _, one := range model.AllCardSize {
+		popup.AddItem(one)
+	}
+	popup.Select(lastCardSize)
+	panel.AddChild(popup)
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Paper")))
+	panel.AddChild(NewFieldTrailingLabel(fmt.Sprintf(i18n.Text("%s, %s"), settings.Page.Size, settings.Page.Orientation)))
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Layout")))
+	layoutLabel := NewFieldTrailingLabel("")
+	panel.AddChild(layoutLabel)
+	updateLayout := func() {
+		size, _ := popup.Selected()
+		columns, rows := size.Grid(settings.Page)
+		perPage := columns * rows
+		layoutLabel.Text = fmt.Sprintf(i18n.Text("%d cards, %d per page (%d x %d), %d pages"), count, perPage, columns,
+			rows, (count+perPage-1)/perPage)
+		layoutLabel.MarkForLayoutAndRedraw()
+		if parent := layoutLabel.Parent(); parent != nil {
+			parent.MarkForLayoutAndRedraw()
+		}
+	}
+	updateLayout()
+	popup.SelectionChangedCallback = func(_ *unison.PopupMenu[model.CardSize]) { updateLayout() }
+	dialog, err := unison.NewDialog(nil, nil, panel,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), {
+			Title:        i18n.Text("Export"),
+			ResponseCode: unison.ModalResponseOK,
+			KeyCodes:     []unison.KeyCode{unison.KeyReturn, unison.KeyNumPadEnter},
+		}})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create card export dialog"), err)
+		return 0, false
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return 0, false
+	}
+	lastCardSize, _ = popup.Selected()
+	return lastCardSize, true
+}
+
+func newCardExporter(entity *model.Entity, title string, cards []*model.Card, size model.CardSize) *pageExporter {
+	p := &pageExporter{
+		entity:   entity,
+		title:    title,
+		keywords: "GCS Cards",
+	}
+	pageSettings := model.SheetSettingsFor(entity).Page
+	columns, rows := size.Grid(pageSettings)
+	w, h := size.Dimensions()
+	cardSize := unison.NewSize(w.Pixels(), h.Pixels())
+	r := unison.Rect{Size: p.PageSize()}
+	var page *unison.Panel
+	for i, card := range cards {
+		if i%(columns*rows) == 0 {
+			page = newCardPage(pageSettings, columns)
+			p.AddChild(page)
+			p.pages = append(p.pages, page)
+		}
+		page.AddChild(newCardPanel(card, cardSize))
+	}
+	for _, one := range p.pages {
+		page = one.AsPanel()
+		page.SetFrameRect(r)
+		page.MarkForLayoutRecursively()
+		page.ValidateLayout()
+	}
+	return p
+}
+
+func newCardPage(pageSettings *model.PageSettings, columns int) *unison.Panel {
+	page := unison.NewPanel()
+	page.SetBorder(unison.NewEmptyBorder(unison.Insets{
+		Top:    pageSettings.TopMargin.Pixels(),
+		Left:   pageSettings.LeftMargin.Pixels(),
+		Bottom: pageSettings.BottomMargin.Pixels(),
+		Right:  pageSettings.RightMargin.Pixels(),
+	}))
+	page.SetLayout(&unison.FlexLayout{Columns: columns})
+	page.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+		gc.DrawRect(rect, model.PageColor.Paint(gc, rect, unison.Fill))
+	}
+	return page
+}
+
+func newCardPanel(card *model.Card, size unison.Size) *unison.Panel {
+	const hInset = 4
+	p := unison.NewPanel()
+	p.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(model.HeaderColor, 0, unison.NewUniformInsets(1), false),
+		unison.NewEmptyBorder(unison.Insets{Bottom: 2, Left: hInset, Right: hInset})))
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: 4,
+	})
+	p.SetSizer(func(_ unison.Size) (min, pref, max unison.Size) { return size, size, size })
+	p.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+		gc.DrawRect(rect, model.PageColor.Paint(gc, rect, unison.Fill))
+	}
+	header := NewPageHeader(card.Title, 2)
+	header.SetBorder(unison.NewEmptyBorder(unison.NewVerticalInsets(2)))
+	p.AddChild(header)
+	p.AddChild(NewPageInternalHeader(card.Kind, 2))
+	for _, field := range card.Fields {
+		label := NewPageLabel(field.Label)
+		label.Font = model.PageLabelSecondaryFont
+		p.AddChild(label)
+		value := NewPageLabel(field.Value)
+		value.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			VAlign: unison.MiddleAlignment,
+			HGrab:  true,
+		})
+		p.AddChild(value)
+	}
+	if notes := strings.TrimSpace(card.Notes); notes != "" {
+		p.AddChild(NewPageInternalHeader("", 2))
+		width := size.Width - (2 + 2*hInset)
+		decoration := &unison.TextDecoration{
+			Font:       model.PageFieldSecondaryFont,
+			Foreground: unison.OnContentColor,
+		}
+		for _, line := range unison.NewTextWrappedLines(notes, decoration, width) {
+			label := NewPageLabel(line.String())
+			label.Font = decoration.Font
+			label.SetLayoutData(&unison.FlexLayoutData{
+				HSpan:  2,
+				HAlign: unison.FillAlignment,
+			})
+			p.AddChild(label)
+		}
+	}
+	if card.PageRef != "" {
+		filler := unison.NewPanel()
+		filler.SetLayoutData(&unison.FlexLayoutData{
+			HSpan: 2,
+			VGrab: true,
+		})
+		p.AddChild(filler)
+		ref := NewPageLabelEnd(card.PageRef)
+		ref.Font = model.PageLabelSecondaryFont
+		ref.SetLayoutData(&unison.FlexLayoutData{
+			HSpan:  2,
+			HAlign: unison.FillAlignment,
+		})
+		p.AddChild(ref)
+	}
+	return p
+}
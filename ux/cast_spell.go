@@ -0,0 +1,207 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type castMode string
+
+var (
+	castModeCast     = castMode(i18n.Text("Cast"))
+	castModeMaintain = castMode(i18n.Text("Maintain"))
+)
+
+type spendEnergyUndoEdit = *unison.UndoEdit[*spendEnergyState]
+
+type spendEnergyState struct {
+	Owner  Rebuildable
+	Entity *model.Entity
+	AttrID string
+	Damage fxp.Int
+}
+
+func newSpendEnergyState(owner Rebuildable, entity *model.Entity, attrID string) *spendEnergyState {
+	s := &spendEnergyState{
+		Owner:  owner,
+		Entity: entity,
+		AttrID: attrID,
+	}
+	if attr, ok := entity.Attributes.Set[attrID]; ok {
+		s.Damage = attr.Damage
+	}
+	return s
+}
+
+func (s *spendEnergyState) Apply() {
+	if attr, ok := s.Entity.Attributes.Set[s.AttrID]; ok {
+		attr.Damage = s.Damage
+	}
+	s.Finish()
+}
+
+func (s *spendEnergyState) Finish() {
+	s.Entity.Recalculate()
+	MarkModified(s.Owner)
+}
+
+type castSpellPanel struct {
+	unison.Panel
+	spell       *model.Spell
+	data        model.SpellCastingData
+	mode        castMode
+	units       fxp.Int
+	pool        string
+	energy      fxp.Int
+	energyField *DecimalField
+}
+
+func canCastSpell[T model.NodeTypes](table *unison.Table[*Node[T]]) bool {
+	rows := table.SelectedRows(false)
+	if len(rows) != 1 {
+		return false
+	}
+	spell, ok := any(rows[0].Data()).(*model.Spell)
+	return ok && !spell.Container() && spell.Entity != nil
+}
+
+func castSpell[T model.NodeTypes](owner Rebuildable, table *unison.Table[*Node[T]]) {
+	rows := table.SelectedRows(false)
+	if len(rows) != 1 {
+		return
+	}
+	spell, ok := any(rows[0].Data()).(*model.Spell)
+	if !ok || spell.Container() || spell.Entity == nil {
+		return
+	}
+	p := newCastSpellPanel(spell)
+	dialog, err := unison.NewDialog(nil, nil, p,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), {
+			Title:        i18n.Text("Spend Energy"),
+			ResponseCode: unison.ModalResponseOK,
+			KeyCodes:     []unison.KeyCode{unison.KeyReturn, unison.KeyNumPadEnter},
+		}})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create cast spell dialog"), err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK || p.energy <= 0 {
+		return
+	}
+	entity := spell.Entity
+	before := newSpendEnergyState(owner, entity, p.pool)
+	if err = entity.SpendEnergy(p.pool, p.energy); err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to spend energy"), err)
+		return
+	}
+	after := newSpendEnergyState(owner, entity, p.pool)
+	if mgr := unison.UndoManagerFor(table); mgr != nil {
+		mgr.Add(&unison.UndoEdit[*spendEnergyState]{
+			ID:         unison.NextUndoID(),
+			EditName:   fmt.Sprintf(i18n.Text("%s %s"), p.mode, spell.String()),
+			UndoFunc:   func(edit spendEnergyUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit spendEnergyUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Finish()
+}
+
+func newCastSpellPanel(spell *model.Spell) *castSpellPanel {
+	p := &castSpellPanel{
+		spell: spell,
+		data:  spell.CastingData(),
+		mode:  castModeCast,
+		units: fxp.One,
+		pool:  spell.EnergySourceAttribute(),
+	}
+	p.Self = p
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Spell")))
+	p.AddChild(NewFieldTrailingLabel(spell.String()))
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Action")))
+	modePopup := unison.NewPopupMenu[castMode]()
+	modePopup.AddItem(castModeCast, castModeMaintain)
+	modePopup.Select(p.mode)
+	modePopup.SelectionChangedCallback = func(popup *unison.PopupMenu[castMode]) {
+		if item, ok := popup.Selected(); ok {
+			p.mode = item
+			p.updateEnergy()
+		}
+	}
+	p.AddChild(modePopup)
+
+	if p.data.Cost.PerUnit != "" || p.data.Maintenance.PerUnit != "" {
+		unit := p.data.Cost.PerUnit
+		if unit == "" {
+			unit = p.data.Maintenance.PerUnit
+		}
+		p.AddChild(NewFieldLeadingLabel(fmt.Sprintf(i18n.Text("Number (per %s)"), unit)))
+		p.AddChild(NewDecimalField(nil, "", i18n.Text("Number"),
+			func() fxp.Int { return p.units },
+			func(v fxp.Int) {
+				p.units = v
+				p.updateEnergy()
+			}, fxp.One, fxp.Thousand, false, false))
+	}
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Energy Source")))
+	choices, current := model.AttributeChoices(spell.Entity, "", model.PoolsOnlyFlag, p.pool)
+	poolPopup := addPopup(p.AsPanel(), choices, &current)
+	poolPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[*model.AttributeChoice]) {
+		if item, ok := popup.Selected(); ok {
+			p.pool = item.Key
+		}
+	}
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Energy")))
+	p.energyField = NewDecimalField(nil, "", i18n.Text("Energy"),
+		func() fxp.Int { return p.energy },
+		func(v fxp.Int) { p.energy = v }, 0, fxp.Thousand, false, false)
+	p.AddChild(p.energyField)
+
+	p.updateEnergy()
+	return p
+}
+
+func (p *castSpellPanel) updateEnergy() {
+	cost := p.data.Cost
+	if p.mode == castModeMaintain {
+		cost = p.data.Maintenance
+	}
+	p.energy = cost.Effective(p.units, p.data.CostReduction)
+	if cost.Parsed {
+		if p.data.CostReduction > 0 {
+			p.energyField.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text("%s, reduced by %v for skill level"),
+				cost.String(), p.data.CostReduction))
+		} else {
+			p.energyField.Tooltip = unison.NewTooltipWithText(cost.String())
+		}
+	} else {
+		p.energyField.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text(`Unable to interpret "%s"; enter the energy to spend`),
+			cost.Text))
+	}
+	p.energyField.Sync()
+}
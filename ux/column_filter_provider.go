@@ -0,0 +1,119 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
+)
+
+var _ TableProvider[*model.Trait] = &columnFilterProvider[*model.Trait]{}
+
+// columnFilterProvider wraps a TableProvider, removing the columns that have been hidden in the sheet settings.
+type columnFilterProvider[T model.NodeTypes] struct {
+	TableProvider[T]
+	hidden []int
+}
+
+// blockLayoutKeyer is implemented by providers whose RefKey() differs from the block layout key of the sheet block
+// they populate.
+type blockLayoutKeyer interface {
+	BlockLayoutKey() string
+}
+
+type columnChoice struct {
+	ID    int
+	Title string
+}
+
+func newColumnFilterProvider[T model.NodeTypes](provider TableProvider[T]) TableProvider[T] {
+	if entity := provider.Entity(); entity != nil {
+		key := provider.RefKey()
+		if keyer, ok := provider.(blockLayoutKeyer); ok {
+			key = keyer.BlockLayoutKey()
+		}
+		if hidden := entity.SheetSettings.HiddenColumnIDs(key); len(hidden) != 0 {
+			return &columnFilterProvider[T]{
+				TableProvider: provider,
+				hidden:        hidden,
+			}
+		}
+	}
+	return provider
+}
+
+func (p *columnFilterProvider[T]) isHidden(id int) bool {
+	return id != p.HierarchyColumnID() && id != p.ExcessWidthColumnID() && slices.Contains(p.hidden, id)
+}
+
+func (p *columnFilterProvider[T]) Headers() []unison.TableColumnHeader[*Node[T]] {
+	ids := p.TableProvider.ColumnIDs()
+	all := p.TableProvider.Headers()
+	headers := make([]unison.TableColumnHeader[*Node[T]], 0, len(all))
+	for i, header := range all {
+		if !p.isHidden(ids[i]) {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
+
+func (p *columnFilterProvider[T]) ColumnIDs() []int {
+	all := p.TableProvider.ColumnIDs()
+	ids := make([]int, 0, len(all))
+	for _, id := range all {
+		if !p.isHidden(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// columnChoices returns the columns of the provider that may be hidden.
+func columnChoices[T model.NodeTypes](provider TableProvider[T]) []columnChoice {
+	ids := provider.ColumnIDs()
+	headers := provider.Headers()
+	choices := make([]columnChoice, 0, len(ids))
+	for i, id := range ids {
+		if id == provider.HierarchyColumnID() || id == provider.ExcessWidthColumnID() {
+			continue
+		}
+		title := ""
+		if h, ok := headers[i].(*PageTableColumnHeader[T]); ok {
+			title = h.columnTitle()
+		}
+		if title == "" {
+			title = i18n.Text("Unnamed Column")
+		}
+		choices = append(choices, columnChoice{ID: id, Title: title})
+	}
+	return choices
+}
+
+// columnTitle returns a short title for the column, preferring a short tooltip over the abbreviated header text.
+func (h *PageTableColumnHeader[T]) columnTitle() string {
+	tip := h.tooltipText
+	for _, sep := range []string{" e.g.", ". ", ", ", "."} {
+		if i := strings.Index(tip, sep); i != -1 {
+			tip = tip[:i]
+		}
+	}
+	tip = strings.TrimSpace(tip)
+	if h.Text == "" || (tip != "" && len(tip) <= 30) {
+		return tip
+	}
+	return h.Text
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+var _ unison.CellFactory = &commandPaletteCellFactory{}
+
+type commandPaletteCellFactory struct{}
+
+// ShowCommandPalette presents a searchable list of the commands that are currently enabled and executes the one chosen.
+func ShowCommandPalette() {
+	wnd := unison.ActiveWindow()
+	enabled := make([]*model.Binding, 0, 128)
+	for _, b := range model.CurrentBindings() {
+		if b.Action != commandPaletteAction && b.Action.Enabled(nil) {
+			enabled = append(enabled, b)
+		}
+	}
+	if len(enabled) == 0 {
+		return
+	}
+	settings := model.GlobalSettings()
+	list := unison.NewList[*model.Binding]()
+	list.Factory = &commandPaletteCellFactory{}
+	list.DoubleClickCallback = func() {
+		if dialog, ok := list.Window().ClientData()[unison.DialogClientDataKey].(*unison.Dialog); ok {
+			dialog.Button(unison.ModalResponseOK).Click()
+		}
+	}
+	filter := func(query string) {
+		list.RemoveRange(0, list.Count()-1)
+		list.Selection.Reset()
+		list.Append(model.RankCommands(enabled, query, settings.RecentCommands)...)
+		if list.Count() != 0 {
+			list.Select(false, 0)
+		}
+		list.MarkForLayoutAndRedraw()
+		if parent := list.Parent(); parent != nil {
+			parent.MarkForLayoutAndRedraw()
+		}
+	}
+	field := NewSearchField()
+	field.Watermark = i18n.Text("Search commands")
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	clearButtonUpdater := field.ModifiedCallback
+	field.ModifiedCallback = func(before, after *unison.FieldState) {
+		clearButtonUpdater(before, after)
+		filter(after.Text)
+	}
+	field.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		switch keyCode {
+		case unison.KeyUp, unison.KeyDown:
+			return list.DefaultKeyDown(keyCode, mod, repeat)
+		case unison.KeyReturn, unison.KeyNumPadEnter:
+			list.DoubleClickCallback()
+			return true
+		default:
+			return field.DefaultKeyDown(keyCode, mod, repeat)
+		}
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.NewUniformInsets(1), false))
+	scroll.SetContent(list, unison.FillBehavior, unison.FillBehavior)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		MinSize: unison.NewSize(400, 300),
+		HAlign:  unison.FillAlignment,
+		VAlign:  unison.FillAlignment,
+		HGrab:   true,
+		VGrab:   true,
+	})
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+	})
+	panel.AddChild(field)
+	panel.AddChild(scroll)
+	filter("")
+	dialog, err := unison.NewDialog(nil, nil, panel, []*unison.DialogButtonInfo{
+		unison.NewCancelButtonInfo(),
+		unison.NewOKButtonInfoWithTitle(i18n.Text("Run")),
+	})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create command palette"), err)
+		return
+	}
+	field.RequestFocus()
+	if dialog.RunModal() != unison.ModalResponseOK || list.Selection.Count() == 0 {
+		return
+	}
+	b := list.DataAtIndex(list.Selection.FirstSet())
+	settings.AddRecentCommand(b.ID)
+	unison.InvokeTask(func() {
+		if wnd != nil && wnd.IsValid() {
+			wnd.ToFront()
+		}
+		b.Action.Execute(nil)
+	})
+}
+
+// CellHeight implements unison.CellFactory.
+func (f *commandPaletteCellFactory) CellHeight() float32 {
+	return 0
+}
+
+// CreateCell implements unison.CellFactory.
+func (f *commandPaletteCellFactory) CreateCell(_ unison.Paneler, element any, _ int, foreground, _ unison.Ink, _, _ bool) unison.Paneler {
+	p := unison.NewPanel()
+	p.SetBorder(unison.NewEmptyBorder(unison.Insets{
+		Top:    unison.StdVSpacing,
+		Left:   unison.StdHSpacing,
+		Bottom: unison.StdVSpacing,
+		Right:  unison.StdHSpacing,
+	}))
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	b, ok := element.(*model.Binding)
+	if !ok {
+		return p
+	}
+	title := unison.NewLabel()
+	title.Text = b.Action.Title
+	title.Font = unison.FieldFont
+	title.OnBackgroundInk = foreground
+	title.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	p.AddChild(title)
+	key := unison.NewLabel()
+	key.Text = b.Action.KeyBinding.String()
+	key.Font = unison.KeyboardFont
+	key.OnBackgroundInk = foreground
+	key.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.EndAlignment,
+		VSpan:  2,
+	})
+	p.AddChild(key)
+	id := unison.NewLabel()
+	id.Text = b.ID
+	id.Font = model.FieldSecondaryFont
+	id.OnBackgroundInk = foreground
+	p.AddChild(id)
+	return p
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+)
+
+// ShowComplianceReport shows the ways in which the sheet's equipment, skills and spells don't comply with the
+// character's tech level or the campaign's control rating.
+func ShowComplianceReport(sheet *Sheet) {
+	title := fmt.Sprintf(i18n.Text("Tech Level & Legality Report for %s"), sheet.Title())
+	content := complianceReportMarkdown(title, sheet.entity)
+	if ws := WorkspaceFromWindowOrAny(nil); ws != nil {
+		if d, ok := ws.LocateFileBackedDockable(markdownContentOnlyPrefix + title).(*MarkdownDockable); ok {
+			d.content = content
+			d.original = content
+			d.renderMarkdown()
+		}
+	}
+	ShowReadOnlyMarkdown(title, content)
+}
+
+func complianceReportMarkdown(title string, entity *model.Entity) string {
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, "# %s\n\n", title)
+	tl := strings.TrimSpace(entity.Profile.TechLevel)
+	if tl == "" {
+		tl = i18n.Text("not set; tech level checks are disabled")
+	}
+	fmt.Fprintf(&buffer, i18n.Text("**Tech Level:** %s  \n"), tl)
+	if cr := model.GlobalSettings().ControlRatingFor(entity); cr > model.ControlRatingMin {
+		fmt.Fprintf(&buffer, i18n.Text("**Control Rating:** %d\n\n"), cr)
+	} else {
+		buffer.WriteString(i18n.Text("**Control Rating:** none; set one in the campaign settings to enable legality checks\n\n"))
+	}
+	issues := entity.ComplianceIssues()
+	if len(issues) == 0 {
+		buffer.WriteString(i18n.Text("No problems were found."))
+		buffer.WriteString("\n")
+		return buffer.String()
+	}
+	for _, kind := range model.AllComplianceIssueKind {
+		first := true
+		for _, issue := range issues {
+			if issue.Kind != kind {
+				continue
+			}
+			if first {
+				first = false
+				fmt.Fprintf(&buffer, "## %s\n\n| %s | %s |\n|---|---|\n", kind, i18n.Text("Item"), i18n.Text("Problem"))
+			}
+			fmt.Fprintf(&buffer, "| %s | %s |\n", escapeMarkdownTableText(issue.Name),
+				escapeMarkdownTableText(issue.Detail))
+		}
+		if !first {
+			buffer.WriteString("\n")
+		}
+	}
+	return buffer.String()
+}
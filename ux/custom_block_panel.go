@@ -0,0 +1,209 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath"
+	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	_ Syncer     = &CustomBlockPanel{}
+	_ pageHelper = &CustomBlockPanel{}
+)
+
+// CustomBlockPanel holds the contents of a user-defined block on the sheet.
+type CustomBlockPanel struct {
+	unison.Panel
+	entity    *model.Entity
+	key       string
+	border    *TitledBorder
+	rows      []customBlockRow
+	drawStart int
+	drawEnd   int
+	limited   bool
+}
+
+type customBlockRow struct {
+	Value   string
+	Label   string
+	Tooltip string
+}
+
+// NewCustomBlockPanel creates a new panel for the custom block with the given block layout key.
+func NewCustomBlockPanel(entity *model.Entity, key string) *CustomBlockPanel {
+	p := &CustomBlockPanel{
+		entity: entity,
+		key:    key,
+		border: &TitledBorder{},
+	}
+	p.Self = p
+	p.ClientData()[pageKey] = key
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: 4,
+	})
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewCompoundBorder(p.border, unison.NewEmptyBorder(unison.Insets{
+		Top:    1,
+		Left:   2,
+		Bottom: 1,
+		Right:  2,
+	})))
+	p.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) { drawBandedBackground(p, gc, rect, 0, 2) }
+	p.Sync()
+	return p
+}
+
+// Sync the panel to the current data.
+func (p *CustomBlockPanel) Sync() {
+	block := p.entity.SheetSettings.CustomBlockFor(p.key)
+	if block == nil {
+		return
+	}
+	rows := p.collectRows(block)
+	title := block.String()
+	if title == p.border.Title && slices.Equal(rows, p.rows) {
+		return
+	}
+	p.border.Title = title
+	p.rows = rows
+	p.rebuild()
+}
+
+func (p *CustomBlockPanel) rebuild() {
+	p.RemoveAllChildren()
+	start, endBefore := p.CurrentDrawRowRange()
+	if len(p.rows) == 0 {
+		if start == 0 {
+			label := NewPageLabelCenter(i18n.Text("Nothing matches this block's settings"))
+			label.SetLayoutData(&unison.FlexLayoutData{
+				HSpan:  2,
+				HAlign: unison.FillAlignment,
+				HGrab:  true,
+			})
+			p.AddChild(label)
+		}
+	} else {
+		for _, row := range p.rows[start:endBefore] {
+			value, label := row.labels()
+			p.AddChild(value)
+			p.AddChild(label)
+		}
+	}
+	MarkForLayoutWithinDockable(p)
+}
+
+func (r customBlockRow) labels() (value, label *unison.Label) {
+	value = NewPageLabelEnd(r.Value)
+	label = NewPageLabel(r.Label)
+	if r.Tooltip != "" {
+		value.Tooltip = unison.NewTooltipWithText(r.Tooltip)
+		label.Tooltip = unison.NewTooltipWithText(r.Tooltip)
+	}
+	return value, label
+}
+
+func (p *CustomBlockPanel) collectRows(block *model.CustomBlock) []customBlockRow {
+	var rows []customBlockRow
+	switch block.Type {
+	case model.AttributesCustomBlockType:
+		for _, id := range block.Attributes {
+			attr, ok := p.entity.Attributes.Set[id]
+			if !ok {
+				continue
+			}
+			def := attr.AttributeDef()
+			if def == nil {
+				continue
+			}
+			row := customBlockRow{Label: def.CombinedName()}
+			if def.Type == model.PoolAttributeType {
+				row.Value = fmt.Sprintf("%v/%v", attr.Current(), attr.Maximum())
+			} else {
+				row.Value = attr.Current().String()
+			}
+			rows = append(rows, row)
+		}
+	case model.SkillsCustomBlockType:
+		for _, skill := range block.Skills(p.entity) {
+			rows = append(rows, customBlockRow{
+				Value:   skill.CalculateLevel().LevelAsString(false),
+				Label:   skill.String(),
+				Tooltip: model.FormatRelativeSkill(p.entity, skill.Type, skill.Difficulty, skill.AdjustedRelativeLevel()),
+			})
+		}
+	case model.NotesCustomBlockType:
+		for _, note := range block.Notes(p.entity) {
+			for _, line := range strings.Split(strings.TrimSpace(note.Text), "\n") {
+				rows = append(rows, customBlockRow{Label: line})
+			}
+		}
+	}
+	return rows
+}
+
+// RowCount returns the number of rows.
+func (p *CustomBlockPanel) RowCount() int {
+	return xmath.Max(len(p.rows), 1)
+}
+
+// OverheadHeight implements pageHelper.
+func (p *CustomBlockPanel) OverheadHeight() float32 {
+	insets := p.Border().Insets()
+	return insets.Height()
+}
+
+// RowHeights implements pageHelper.
+func (p *CustomBlockPanel) RowHeights() []float32 {
+	if len(p.rows) == 0 {
+		label := NewPageLabelCenter(i18n.Text("Nothing matches this block's settings"))
+		_, pref, _ := label.Sizes(unison.Size{})
+		return []float32{pref.Height}
+	}
+	heights := make([]float32, len(p.rows))
+	for i, row := range p.rows {
+		value, label := row.labels()
+		_, valuePref, _ := value.Sizes(unison.Size{})
+		_, labelPref, _ := label.Sizes(unison.Size{})
+		heights[i] = xmath.Max(valuePref.Height, labelPref.Height)
+	}
+	return heights
+}
+
+// CurrentDrawRowRange implements pageHelper.
+func (p *CustomBlockPanel) CurrentDrawRowRange() (start, endBefore int) {
+	count := p.RowCount()
+	if !p.limited {
+		return 0, count
+	}
+	start = xmath.Min(xmath.Max(p.drawStart, 0), count)
+	return start, xmath.Min(xmath.Max(p.drawEnd, start), count)
+}
+
+// SetDrawRowRange implements pageHelper.
+func (p *CustomBlockPanel) SetDrawRowRange(start, endBefore int) {
+	p.drawStart = start
+	p.drawEnd = endBefore
+	p.limited = true
+	p.rebuild()
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 	"github.com/richardwilkes/unison"
 )
 
@@ -83,7 +84,7 @@ func (d *DescriptionPanel) createColumn1() *unison.Panel {
 		func(s string) { d.entity.Profile.Gender = s })
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the gender using the current ancestry"), func() {
-			d.entity.Profile.Gender = d.entity.Ancestry().RandomGender(d.entity.Profile.Gender)
+			d.entity.Profile.Gender = d.entity.Ancestry().RandomGender(rand.NewCryptoRand(), d.entity.Profile.Gender)
 			SetTextAndMarkModified(genderField.Field, d.entity.Profile.Gender)
 		}))
 	genderField.ClientData()[SkipDeepSync] = true
@@ -96,7 +97,7 @@ func (d *DescriptionPanel) createColumn1() *unison.Panel {
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the age using the current ancestry"), func() {
 			age, _ := strconv.Atoi(d.entity.Profile.Age) //nolint:errcheck // A default of 0 is ok here on error
-			d.entity.Profile.Age = strconv.Itoa(d.entity.Ancestry().RandomAge(d.entity, d.entity.Profile.Gender, age))
+			d.entity.Profile.Age = strconv.Itoa(d.entity.Ancestry().RandomAge(rand.NewCryptoRand(), d.entity, d.entity.Profile.Gender, age))
 			SetTextAndMarkModified(ageField.Field, d.entity.Profile.Age)
 		}))
 	ageField.ClientData()[SkipDeepSync] = true
@@ -108,8 +109,7 @@ func (d *DescriptionPanel) createColumn1() *unison.Panel {
 		func(s string) { d.entity.Profile.Birthday = s })
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the birthday using the current calendar"), func() {
-			global := model.GlobalSettings()
-			d.entity.Profile.Birthday = global.General.CalendarRef(global.LibrarySet).RandomBirthday(d.entity.Profile.Birthday)
+			d.entity.Profile.Birthday = model.GlobalSettings().CalendarRefFor(d.entity).RandomBirthday(rand.NewCryptoRand(), d.entity.Profile.Birthday)
 			SetTextAndMarkModified(birthdayField.Field, d.entity.Profile.Birthday)
 		}))
 	birthdayField.ClientData()[SkipDeepSync] = true
@@ -135,7 +135,7 @@ func (d *DescriptionPanel) createColumn2() *unison.Panel {
 		func(v model.Length) { d.entity.Profile.Height = v }, 0, model.Length(fxp.Max), true)
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the height using the current ancestry"), func() {
-			d.entity.Profile.Height = d.entity.Ancestry().RandomHeight(d.entity, d.entity.Profile.Gender, d.entity.Profile.Height)
+			d.entity.Profile.Height = d.entity.Ancestry().RandomHeight(rand.NewCryptoRand(), d.entity, d.entity.Profile.Gender, d.entity.Profile.Height)
 			SetTextAndMarkModified(heightField.Field, d.entity.Profile.Height.String())
 		}))
 	heightField.ClientData()[SkipDeepSync] = true
@@ -147,7 +147,7 @@ func (d *DescriptionPanel) createColumn2() *unison.Panel {
 		func(v model.Weight) { d.entity.Profile.Weight = v }, 0, model.Weight(fxp.Max), true)
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the weight using the current ancestry"), func() {
-			d.entity.Profile.Weight = d.entity.Ancestry().RandomWeight(d.entity, d.entity.Profile.Gender, d.entity.Profile.Weight)
+			d.entity.Profile.Weight = d.entity.Ancestry().RandomWeight(rand.NewCryptoRand(), d.entity, d.entity.Profile.Gender, d.entity.Profile.Weight)
 			SetTextAndMarkModified(weightField.Field, d.entity.Profile.Weight.String())
 		}))
 	weightField.ClientData()[SkipDeepSync] = true
@@ -181,7 +181,7 @@ func (d *DescriptionPanel) createColumn3() *unison.Panel {
 		func(s string) { d.entity.Profile.Hair = s })
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the hair using the current ancestry"), func() {
-			d.entity.Profile.Hair = d.entity.Ancestry().RandomHair(d.entity.Profile.Gender, d.entity.Profile.Hair)
+			d.entity.Profile.Hair = d.entity.Ancestry().RandomHair(rand.NewCryptoRand(), d.entity.Profile.Gender, d.entity.Profile.Hair)
 			SetTextAndMarkModified(hairField.Field, d.entity.Profile.Hair)
 		}))
 	hairField.ClientData()[SkipDeepSync] = true
@@ -193,7 +193,7 @@ func (d *DescriptionPanel) createColumn3() *unison.Panel {
 		func(s string) { d.entity.Profile.Eyes = s })
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the eyes using the current ancestry"), func() {
-			d.entity.Profile.Eyes = d.entity.Ancestry().RandomEyes(d.entity.Profile.Gender, d.entity.Profile.Eyes)
+			d.entity.Profile.Eyes = d.entity.Ancestry().RandomEyes(rand.NewCryptoRand(), d.entity.Profile.Gender, d.entity.Profile.Eyes)
 			SetTextAndMarkModified(eyesField.Field, d.entity.Profile.Eyes)
 		}))
 	eyesField.ClientData()[SkipDeepSync] = true
@@ -205,7 +205,7 @@ func (d *DescriptionPanel) createColumn3() *unison.Panel {
 		func(s string) { d.entity.Profile.Skin = s })
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the skin using the current ancestry"), func() {
-			d.entity.Profile.Skin = d.entity.Ancestry().RandomSkin(d.entity.Profile.Gender, d.entity.Profile.Skin)
+			d.entity.Profile.Skin = d.entity.Ancestry().RandomSkin(rand.NewCryptoRand(), d.entity.Profile.Gender, d.entity.Profile.Skin)
 			SetTextAndMarkModified(skinField.Field, d.entity.Profile.Skin)
 		}))
 	skinField.ClientData()[SkipDeepSync] = true
@@ -217,7 +217,7 @@ func (d *DescriptionPanel) createColumn3() *unison.Panel {
 		func(s string) { d.entity.Profile.Handedness = s })
 	column.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the handedness using the current ancestry"), func() {
-			d.entity.Profile.Handedness = d.entity.Ancestry().RandomHandedness(d.entity.Profile.Gender, d.entity.Profile.Handedness)
+			d.entity.Profile.Handedness = d.entity.Ancestry().RandomHandedness(rand.NewCryptoRand(), d.entity.Profile.Gender, d.entity.Profile.Handedness)
 			SetTextAndMarkModified(handField.Field, d.entity.Profile.Handedness)
 		}))
 	handField.ClientData()[SkipDeepSync] = true
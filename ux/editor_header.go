@@ -91,6 +91,13 @@ func NewEnabledHeader[T model.NodeTypes](forPage bool) unison.TableColumnHeader[
 		forPage)
 }
 
+// NewTraitActiveHeader creates a new trait active header.
+func NewTraitActiveHeader[T model.NodeTypes](forPage bool) unison.TableColumnHeader[*Node[T]] {
+	return NewEditorListSVGHeader[T](unison.CheckmarkSVG,
+		i18n.Text(`Whether this switchable trait is currently active. Traits that are not active do not apply any features or weapons they may normally contribute to the character.`),
+		forPage)
+}
+
 // NewMoneyHeader creates a new money header.
 func NewMoneyHeader[T model.NodeTypes](forPage bool) unison.TableColumnHeader[*Node[T]] {
 	return NewEditorListSVGHeader[T](svg.Coins,
@@ -133,7 +140,8 @@ var _ unison.TableColumnHeader[*Node[*model.Trait]] = &PageTableColumnHeader[*mo
 // PageTableColumnHeader provides a default page table column header panel.
 type PageTableColumnHeader[T model.NodeTypes] struct {
 	unison.Label
-	sortState unison.SortState
+	sortState   unison.SortState
+	tooltipText string
 }
 
 // NewPageTableColumnHeader creates a new page table column header panel with the given title.
@@ -148,6 +156,7 @@ func NewPageTableColumnHeader[T model.NodeTypes](title, tooltip string) *PageTab
 			Ascending: true,
 			Sortable:  true,
 		},
+		tooltipText: tooltip,
 	}
 
 	h.Self = h
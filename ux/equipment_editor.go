@@ -41,14 +41,24 @@ func EditEquipment(owner Rebuildable, equipment *model.Equipment, carried bool)
 			} else {
 				addLabelAndDecimalField(content, nil, "", qtyLabel, "", &e.editorData.Quantity, 0, fxp.Max-1)
 			}
+			settings := model.SheetSettingsFor(e.target.Entity)
+			hasCurrencies := len(settings.Currencies) != 0
 			valueLabel := i18n.Text("Value")
-			wrapper := addFlowWrapper(content, valueLabel, 3)
+			count := 3
+			if hasCurrencies {
+				count++
+			}
+			wrapper := addFlowWrapper(content, valueLabel, count)
 			addDecimalField(wrapper, nil, "", valueLabel, "", &e.editorData.Value, 0, fxp.Max-1)
+			if hasCurrencies {
+				addCurrencyPopup(wrapper, e.target.Entity, &e.editorData.Currency)
+			}
 			wrapper.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Extended")))
 			wrapper.AddChild(NewNonEditableField(func(field *NonEditableField) {
 				var value fxp.Int
 				if e.editorData.Quantity > 0 {
-					value = model.ValueAdjustedForModifiers(e.editorData.Value, e.editorData.Modifiers)
+					value = model.ValueAdjustedForModifiers(settings.ConvertCurrency(e.editorData.Value,
+						e.editorData.Currency), e.editorData.Modifiers) + settings.CoinValue(e.editorData.Coins)
 					if e.target.Container() {
 						for _, one := range e.target.Children {
 							value += one.ExtendedValue()
@@ -56,25 +66,52 @@ func EditEquipment(owner Rebuildable, equipment *model.Equipment, carried bool)
 					}
 					value = value.Mul(e.editorData.Quantity)
 				}
-				field.Text = value.Comma()
+				field.Text = settings.FormatCurrency(value)
 				field.MarkForLayoutAndRedraw()
 			}))
+			if hasCurrencies {
+				coinsLabel := i18n.Text("Coins")
+				wrapper = addFlowWrapper(content, coinsLabel, len(settings.Currencies)*2)
+				for _, one := range settings.Currencies {
+					addCoinField(wrapper, one, &e.editorData.Coins)
+					wrapper.AddChild(NewFieldTrailingLabel(one.String()))
+				}
+			}
 			weightLabel := i18n.Text("Weight")
 			wrapper = addFlowWrapper(content, weightLabel, 3)
 			addWeightField(wrapper, nil, "", weightLabel, "", e.target.Entity, &e.editorData.Weight, false)
 			wrapper.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("Extended")))
 			wrapper.AddChild(NewNonEditableField(func(field *NonEditableField) {
 				var weight model.Weight
-				defUnits := model.SheetSettingsFor(e.target.Entity).DefaultWeightUnits
+				defUnits := settings.DefaultWeightUnits
 				if e.editorData.Quantity > 0 {
 					weight = model.ExtendedWeightAdjustedForModifiers(defUnits, e.editorData.Quantity, e.editorData.Weight,
-						e.editorData.Modifiers, e.editorData.Features, e.target.Children, false, false)
+						settings.CoinWeight(e.editorData.Coins), e.editorData.Modifiers, e.editorData.Features,
+						e.target.Children, false, false)
 				}
 				field.Text = defUnits.Format(weight)
 				field.MarkForLayoutAndRedraw()
 			}))
 			content.AddChild(unison.NewPanel())
 			addCheckBox(content, i18n.Text("Ignore weight for skills"), &e.editorData.WeightIgnoredForSkills)
+			addLabelAndDecimalField(content, nil, "", i18n.Text("Volume"),
+				i18n.Text("The volume (or number of slots) this item occupies within a container"),
+				&e.editorData.Volume, 0, fxp.Max-1)
+			if e.target.Container() {
+				capacityTooltip := i18n.Text("A value of zero means there is no limit")
+				maxWeightLabel := i18n.Text("Maximum Weight")
+				wrapper = addFlowWrapper(content, maxWeightLabel, 5)
+				addWeightField(wrapper, nil, "", maxWeightLabel, capacityTooltip, e.target.Entity,
+					&e.editorData.MaxContainedWeight, false)
+				maxVolumeLabel := i18n.Text("Volume")
+				wrapper.AddChild(NewFieldInteriorLeadingLabel(maxVolumeLabel))
+				addDecimalField(wrapper, nil, "", maxVolumeLabel, capacityTooltip, &e.editorData.MaxContainedVolume, 0,
+					fxp.Max-1)
+				maxQtyLabel := i18n.Text("Items")
+				wrapper.AddChild(NewFieldInteriorLeadingLabel(maxQtyLabel))
+				addDecimalField(wrapper, nil, "", maxQtyLabel, capacityTooltip, &e.editorData.MaxContainedQuantity, 0,
+					fxp.Max-1)
+			}
 			usesLabel := i18n.Text("Uses")
 			wrapper = addFlowWrapper(content, usesLabel, 3)
 			usesField := addIntegerField(wrapper, nil, "", usesLabel, "", &e.editorData.Uses, 0, 9999999)
@@ -103,3 +140,20 @@ func EditEquipment(owner Rebuildable, equipment *model.Equipment, carried bool)
 			}
 		})
 }
+
+func addCoinField(parent *unison.Panel, currency *model.Currency, coins *map[string]fxp.Int) {
+	field := NewDecimalField(nil, "", currency.String(),
+		func() fxp.Int { return (*coins)[currency.ID] },
+		func(value fxp.Int) {
+			if value == 0 {
+				delete(*coins, currency.ID)
+			} else {
+				if *coins == nil {
+					*coins = make(map[string]fxp.Int)
+				}
+				(*coins)[currency.ID] = value
+			}
+			MarkModified(parent)
+		}, 0, fxp.Max-1, false, false)
+	parent.AddChild(field)
+}
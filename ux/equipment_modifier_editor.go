@@ -49,7 +49,12 @@ func initEquipmentModifierEditor(e *editor[*model.EquipmentModifier, *model.Equi
 
 func addEquipmentCostFields(parent *unison.Panel, e *editor[*model.EquipmentModifier, *model.EquipmentModifierEditData]) {
 	label := i18n.Text("Cost Modifier")
-	wrapper := addFlowWrapper(parent, label, 2)
+	hasCurrencies := len(model.SheetSettingsFor(e.target.Entity).Currencies) != 0
+	count := 2
+	if hasCurrencies {
+		count++
+	}
+	wrapper := addFlowWrapper(parent, label, count)
 	field := NewStringField(nil, "", label,
 		func() string { return e.editorData.CostType.Format(e.editorData.CostAmount) },
 		func(value string) {
@@ -69,6 +74,10 @@ func addEquipmentCostFields(parent *unison.Panel, e *editor[*model.EquipmentModi
 		field.SetText(e.editorData.CostType.Format(field.Text()))
 		MarkModified(wrapper)
 	}
+	if hasCurrencies {
+		currencyPopup := addCurrencyPopup(wrapper, e.target.Entity, &e.editorData.CostCurrency)
+		currencyPopup.Tooltip = unison.NewTooltipWithText(i18n.Text("The currency used for added amounts"))
+	}
 }
 
 func addEquipmentWeightFields(parent *unison.Panel, e *editor[*model.EquipmentModifier, *model.EquipmentModifierEditData]) {
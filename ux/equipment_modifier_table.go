@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -53,5 +54,6 @@ func NewEquipmentModifierTableDockable(filePath string, modifiers []*model.Equip
 	return NewTableDockable(filePath, model.EquipmentModifiersExt,
 		NewEquipmentModifiersProvider(provider, false),
 		func(path string) error { return model.SaveEquipmentModifiers(provider.EquipmentModifierList(), path) },
+		func(w io.Writer) error { return model.SaveEquipmentModifiersTo(provider.EquipmentModifierList(), w) },
 		NewEquipmentModifierItemID, NewEquipmentContainerModifierItemID)
 }
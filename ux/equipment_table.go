@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -60,6 +61,7 @@ func NewEquipmentTableDockable(filePath string, equipment []*model.Equipment) *T
 	provider := &equipmentListProvider{other: equipment}
 	d := NewTableDockable(filePath, model.EquipmentExt, NewEquipmentProvider(provider, false, false),
 		func(path string) error { return model.SaveEquipment(provider.OtherEquipmentList(), path) },
+		func(w io.Writer) error { return model.SaveEquipmentTo(provider.OtherEquipmentList(), w) },
 		NewOtherEquipmentItemID, NewOtherEquipmentContainerItemID)
 	InstallContainerConversionHandlers(d, d, d.table)
 	return d
@@ -0,0 +1,322 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+var (
+	_ unison.Dockable = &expressionTesterDockable{}
+	_ GroupedCloser   = &expressionTesterDockable{}
+)
+
+type expressionTesterDockable struct {
+	unison.Panel
+	sheet           *Sheet
+	expressionField *unison.Field
+	resultLabel     *unison.Label
+	explanation     *unison.Panel
+	filterField     *unison.Field
+	variables       *unison.Panel
+}
+
+// ShowExpressionTester shows the expression tester for the given Sheet.
+func ShowExpressionTester(sheet *Sheet) {
+	ws, dc, found := Activate(func(d unison.Dockable) bool {
+		if t, ok := d.(*expressionTesterDockable); ok {
+			return t.sheet == sheet
+		}
+		return false
+	})
+	if !found && ws != nil {
+		d := &expressionTesterDockable{sheet: sheet}
+		d.Self = d
+		d.SetLayout(&unison.FlexLayout{Columns: 1})
+		content := unison.NewPanel()
+		content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(unison.StdHSpacing * 2)))
+		content.SetLayout(&unison.FlexLayout{
+			Columns:  1,
+			VSpacing: unison.StdVSpacing * 2,
+		})
+		content.AddChild(d.createExpressionBlock())
+		content.AddChild(d.createResultBlock())
+		d.variables = createClockSection(content, i18n.Text("Variables"))
+		content.AddChild(d.createFilterField())
+		content.AddChild(d.variables)
+		scroll := unison.NewScrollPanel()
+		scroll.SetContent(content, unison.HintedFillBehavior, unison.FillBehavior)
+		scroll.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			VAlign: unison.FillAlignment,
+			HGrab:  true,
+			VGrab:  true,
+		})
+		d.AddChild(scroll)
+		d.ClientData()[AssociatedUUIDKey] = sheet.Entity().ID
+		d.syncResult()
+		d.syncVariables()
+		group := EditorGroup
+		p := sheet.AsPanel()
+		for p != nil {
+			if _, exists := p.ClientData()[AssociatedUUIDKey]; exists {
+				group = subEditorGroup
+				break
+			}
+			p = p.Parent()
+		}
+		PlaceInDock(ws, dc, d, group)
+		d.expressionField.RequestFocus()
+	}
+}
+
+// UpdateExpressionTester refreshes the expression tester for the given Sheet, if it is being shown.
+func UpdateExpressionTester(sheet *Sheet) {
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, other := range dc.Dockables() {
+					if d, ok := other.(*expressionTesterDockable); ok && d.sheet == sheet {
+						d.syncResult()
+						d.syncVariables()
+						return true
+					}
+				}
+				return false
+			})
+		}
+	}
+}
+
+func (d *expressionTesterDockable) createExpressionBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	panel.AddChild(NewFieldLeadingLabel(i18n.Text("Expression")))
+	d.expressionField = unison.NewMultiLineField()
+	d.expressionField.Watermark = i18n.Text("e.g. $dx + 5")
+	d.expressionField.Tooltip = unison.NewTooltipWithText(i18n.Text("The expression to evaluate against this sheet"))
+	d.expressionField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	d.expressionField.ModifiedCallback = func(_, _ *unison.FieldState) { d.syncResult() }
+	panel.AddChild(d.expressionField)
+	return panel
+}
+
+func (d *expressionTesterDockable) createResultBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	d.resultLabel = unison.NewLabel()
+	d.resultLabel.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			fd := unison.SystemFont.Descriptor()
+			fd.Size *= 1.5
+			return fd
+		},
+	}
+	panel.AddChild(d.resultLabel)
+	d.explanation = unison.NewPanel()
+	d.explanation.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(d.explanation)
+	return panel
+}
+
+func (d *expressionTesterDockable) createFilterField() *unison.Field {
+	d.filterField = NewSearchField()
+	d.filterField.Watermark = i18n.Text("Filter")
+	d.filterField.Tooltip = unison.NewTooltipWithText(i18n.Text("Only show variables whose ID or name contains this text"))
+	d.filterField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	callback := d.filterField.ModifiedCallback
+	d.filterField.ModifiedCallback = func(before, after *unison.FieldState) {
+		callback(before, after)
+		d.syncVariables()
+	}
+	return d.filterField
+}
+
+func (d *expressionTesterDockable) syncResult() {
+	d.explanation.RemoveAllChildren()
+	expression := d.expressionField.Text()
+	if strings.TrimSpace(expression) == "" {
+		d.resultLabel.Text = i18n.Text("Enter an expression to evaluate.")
+		d.resultLabel.OnBackgroundInk = unison.DefaultLabelTheme.OnBackgroundInk
+	} else {
+		result := model.EvaluateExpression(expression, d.sheet.entity)
+		if result.Err == nil {
+			d.resultLabel.Text = fmt.Sprintf(i18n.Text("Result: %s"), result.ValueText())
+			d.resultLabel.OnBackgroundInk = unison.DefaultLabelTheme.OnBackgroundInk
+		} else {
+			d.resultLabel.Text = i18n.Text("Unable to evaluate the expression")
+			d.resultLabel.OnBackgroundInk = unison.ErrorColor
+			d.addExplanation(fmt.Sprintf(i18n.Text("Error: %s"), result.ErrorText()))
+			if result.Failing != "" && result.Failing != strings.TrimSpace(expression) {
+				d.addExplanation(fmt.Sprintf(i18n.Text("Failing part: %s"), result.Failing))
+			}
+		}
+		if len(result.Unresolved) != 0 {
+			names := make([]string, len(result.Unresolved))
+			for i, one := range result.Unresolved {
+				names[i] = "$" + one
+			}
+			d.addExplanation(fmt.Sprintf(i18n.Text("Unresolved variables: %s"), strings.Join(names, ", ")))
+		}
+	}
+	d.MarkForLayoutRecursively()
+	d.MarkForRedraw()
+}
+
+func (d *expressionTesterDockable) addExplanation(text string) {
+	label := NewFieldTrailingLabel(text)
+	label.OnBackgroundInk = unison.ErrorColor
+	d.explanation.AddChild(label)
+}
+
+func (d *expressionTesterDockable) syncVariables() {
+	d.variables.RemoveAllChildren()
+	d.variables.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	filter := strings.ToLower(strings.TrimSpace(d.filterField.Text()))
+	count := 0
+	for _, one := range d.sheet.entity.ExpressionVariables() {
+		if filter != "" && !strings.Contains(strings.ToLower(one.Name), filter) &&
+			!strings.Contains(strings.ToLower(one.Description), filter) {
+			continue
+		}
+		count++
+		d.variables.AddChild(d.newVariableLabel(one.Name))
+		desc := NewFieldTrailingLabel(one.Description)
+		desc.Font = model.FieldSecondaryFont
+		d.variables.AddChild(desc)
+		value := NewFieldTrailingLabel(one.Value)
+		if strings.TrimSpace(one.Value) == "" {
+			value.Text = i18n.Text("(unresolved)")
+			value.OnBackgroundInk = unison.ErrorColor
+		}
+		d.variables.AddChild(value)
+	}
+	if count == 0 {
+		label := NewFieldTrailingLabel(i18n.Text("No variables match the filter."))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+		d.variables.AddChild(label)
+	}
+	d.MarkForLayoutRecursively()
+	d.MarkForRedraw()
+}
+
+func (d *expressionTesterDockable) newVariableLabel(name string) *unison.Label {
+	label := NewFieldTrailingLabel("$" + name)
+	label.Tooltip = unison.NewTooltipWithText(i18n.Text("Click to insert this variable into the expression"))
+	label.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool {
+		return true
+	}
+	label.MouseUpCallback = func(where unison.Point, _ int, _ unison.Modifiers) bool {
+		if label.ContentRect(false).ContainsPoint(where) {
+			d.insertVariable(name)
+		}
+		return true
+	}
+	label.UpdateCursorCallback = func(_ unison.Point) *unison.Cursor {
+		return unison.PointingCursor()
+	}
+	return label
+}
+
+func (d *expressionTesterDockable) insertVariable(name string) {
+	text := []rune(d.expressionField.Text())
+	start, end := d.expressionField.Selection()
+	insert := []rune("$" + name)
+	replaced := make([]rune, 0, len(text)-(end-start)+len(insert))
+	replaced = append(replaced, text[:start]...)
+	replaced = append(replaced, insert...)
+	replaced = append(replaced, text[end:]...)
+	d.expressionField.SetText(string(replaced))
+	d.expressionField.SetSelectionTo(start + len(insert))
+	d.expressionField.RequestFocus()
+}
+
+// TitleIcon implements unison.Dockable
+func (d *expressionTesterDockable) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Gears,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable
+func (d *expressionTesterDockable) Title() string {
+	return fmt.Sprintf(i18n.Text("Expression Tester for %s"), d.sheet.String())
+}
+
+func (d *expressionTesterDockable) String() string {
+	return d.Title()
+}
+
+// Tooltip implements unison.Dockable
+func (d *expressionTesterDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable
+func (d *expressionTesterDockable) Modified() bool {
+	return false
+}
+
+// CloseWithGroup implements GroupedCloser
+func (d *expressionTesterDockable) CloseWithGroup(other unison.Paneler) bool {
+	return d.sheet != nil && d.sheet == other
+}
+
+// MayAttemptClose implements GroupedCloser
+func (d *expressionTesterDockable) MayAttemptClose() bool {
+	return MayAttemptCloseOfGroup(d)
+}
+
+// AttemptClose implements GroupedCloser
+func (d *expressionTesterDockable) AttemptClose() bool {
+	if !CloseGroup(d) {
+		return false
+	}
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
@@ -0,0 +1,244 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	_ externalChangeMerger = &Sheet{}
+	_ externalChangeMerger = &TableDockable[*model.Trait]{}
+)
+
+var (
+	fileWatches            = make(map[FileBackedDockable]*model.FileWatchToken)
+	pendingExternalChanges = make(map[FileBackedDockable]bool)
+)
+
+// externalChangeMerger defines the method a FileBackedDockable must implement to support merging changes made to its
+// backing file by something other than this application.
+type externalChangeMerger interface {
+	mergeFromDisk()
+}
+
+type rowMergeChoice struct {
+	kind      string
+	title     string
+	status    string
+	useTheirs *bool
+}
+
+// watchBackingFile starts watching the dockable's backing file for changes made outside of this application. If it is
+// already being watched, the current state of the file is acknowledged, such as after a save.
+func watchBackingFile(d FileBackedDockable) {
+	filePath := d.BackingFilePath()
+	if token, exists := fileWatches[d]; exists {
+		if token.Path() == filePath {
+			token.Acknowledge()
+			return
+		}
+		token.Stop()
+		delete(fileWatches, d)
+	}
+	if !filepath.IsAbs(filePath) || !fs.FileExists(filePath) {
+		return
+	}
+	if token := model.WatchFile(filePath, func(_ string, exists bool) { backingFileChanged(d, exists) }); token != nil {
+		fileWatches[d] = token
+	}
+}
+
+func stopWatchingBackingFile(d FileBackedDockable) {
+	if token, exists := fileWatches[d]; exists {
+		token.Stop()
+		delete(fileWatches, d)
+	}
+}
+
+// pruneBackingFileWatches stops the watches of dockables that have been closed.
+func pruneBackingFileWatches() {
+	for d := range fileWatches {
+		if unison.Ancestor[*unison.DockContainer](d) == nil {
+			stopWatchingBackingFile(d)
+		}
+	}
+}
+
+func backingFileChanged(d FileBackedDockable, exists bool) {
+	if unison.Ancestor[*unison.DockContainer](d) == nil {
+		stopWatchingBackingFile(d)
+		return
+	}
+	if pendingExternalChanges[d] {
+		return
+	}
+	pendingExternalChanges[d] = true
+	defer delete(pendingExternalChanges, d)
+	if !exists {
+		unison.WarningDialogWithMessage(fmt.Sprintf(i18n.Text("%s was removed or renamed on disk."), d.Title()),
+			i18n.Text("Saving will create the file again."))
+		return
+	}
+	modified := false
+	if m, ok := d.(interface{ Modified() bool }); ok {
+		modified = m.Modified()
+	}
+	merger, canMerge := d.(externalChangeMerger)
+	buttons := []*unison.DialogButtonInfo{
+		{
+			Title:        i18n.Text("Keep Mine"),
+			ResponseCode: unison.ModalResponseCancel,
+			KeyCodes:     []unison.KeyCode{unison.KeyEscape},
+		},
+	}
+	if canMerge {
+		buttons = append(buttons, &unison.DialogButtonInfo{
+			Title:        i18n.Text("Merge…"),
+			ResponseCode: unison.ModalResponseUserBase,
+		})
+	}
+	var detail string
+	if modified {
+		detail = i18n.Text("You have unsaved changes, so the file cannot simply be reloaded. Saving will replace the changes made on disk.")
+	} else {
+		detail = i18n.Text("Reload to see the changes made on disk.")
+		buttons = append(buttons, unison.NewOKButtonInfoWithTitle(i18n.Text("Reload")))
+	}
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.WarningIcon, unison.DefaultDialogTheme.WarningIconInk,
+		unison.NewMessagePanel(fmt.Sprintf(i18n.Text("%s has been changed on disk."), d.Title()), detail), buttons)
+	if err != nil {
+		jot.Error(err)
+		return
+	}
+	switch dialog.RunModal() {
+	case unison.ModalResponseOK:
+		reloadFromDisk(d)
+	case unison.ModalResponseUserBase:
+		merger.mergeFromDisk()
+	default:
+	}
+}
+
+func reloadFromDisk(d FileBackedDockable) {
+	dc := unison.Ancestor[*unison.DockContainer](d)
+	if dc == nil {
+		return
+	}
+	filePath := d.BackingFilePath()
+	replacement, err := model.FileInfoFor(filePath).Load(filePath)
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to reload ")+fs.BaseName(filePath), err)
+		return
+	}
+	dc.Stack(replacement, slices.Index(dc.Dockables(), unison.Dockable(d)))
+	if !d.AttemptClose() {
+		dc.Close(replacement)
+		return
+	}
+	stopWatchingBackingFile(d)
+	if fbd, ok := replacement.(FileBackedDockable); ok {
+		watchBackingFile(fbd)
+	}
+}
+
+func rowMergeChoices[T model.NodeTypes](kind string, diffs []*model.RowDifference[T]) []*rowMergeChoice {
+	var zero T
+	choices := make([]*rowMergeChoice, 0, len(diffs))
+	for _, diff := range diffs {
+		choice := &rowMergeChoice{
+			kind:      kind,
+			useTheirs: &diff.UseTheirs,
+		}
+		switch {
+		case diff.Mine == zero:
+			choice.title = model.AsNode(diff.Theirs).String()
+			choice.status = i18n.Text("Added on disk")
+		case diff.Theirs == zero:
+			choice.title = model.AsNode(diff.Mine).String()
+			choice.status = i18n.Text("Not on disk")
+		default:
+			choice.title = model.AsNode(diff.Mine).String()
+			choice.status = i18n.Text("Changed on disk")
+		}
+		choices = append(choices, choice)
+	}
+	return choices
+}
+
+// resolveRowMerge asks the user which version of each differing row to use. Returns false if canceled.
+func resolveRowMerge(title string, choices []*rowMergeChoice) bool {
+	if len(choices) == 0 {
+		unison.WarningDialogWithMessage(fmt.Sprintf(i18n.Text("No rows in %s differ from the file on disk."), title),
+			i18n.Text("Only list rows are merged; everything else keeps your version."))
+		return false
+	}
+	grid := unison.NewPanel()
+	grid.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	grid.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(unison.StdHSpacing)))
+	for _, header := range []string{i18n.Text("Use Disk Version"), i18n.Text("Type"), i18n.Text("Status")} {
+		label := unison.NewLabel()
+		label.Text = header
+		label.Font = unison.SystemFont
+		grid.AddChild(label)
+	}
+	for _, choice := range choices {
+		choice := choice
+		checkbox := unison.NewCheckBox()
+		checkbox.Text = choice.title
+		checkbox.State = unison.CheckStateFromBool(*choice.useTheirs)
+		checkbox.ClickCallback = func() { *choice.useTheirs = checkbox.State == unison.OnCheckState }
+		grid.AddChild(checkbox)
+		grid.AddChild(NewFieldTrailingLabel(choice.kind))
+		grid.AddChild(NewFieldTrailingLabel(choice.status))
+	}
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.NewUniformInsets(1), false))
+	scroll.SetContent(grid, unison.HintedFillBehavior, unison.FillBehavior)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: unison.NewSize(600, 400),
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = fmt.Sprintf(i18n.Text("Choose the rows of %s to take from the file on disk."), title)
+	panel.AddChild(label)
+	panel.AddChild(scroll)
+	dialog, err := unison.NewDialog(nil, nil, panel, []*unison.DialogButtonInfo{
+		unison.NewCancelButtonInfo(),
+		unison.NewOKButtonInfoWithTitle(i18n.Text("Merge")),
+	})
+	if err != nil {
+		jot.Error(err)
+		return false
+	}
+	return dialog.RunModal() == unison.ModalResponseOK
+}
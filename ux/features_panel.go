@@ -110,6 +110,9 @@ func (p *featuresPanel) insertFeaturePanel(index int, f model.Feature) {
 		return
 	}
 	if panel != nil {
+		if cf, ok := f.(model.ConditionalFeature); ok {
+			p.addConditionLine(panel, cf.ActivationCondition())
+		}
 		panel.SetLayoutData(&unison.FlexLayoutData{
 			HAlign: unison.FillAlignment,
 			HGrab:  true,
@@ -118,6 +121,72 @@ func (p *featuresPanel) insertFeaturePanel(index int, f model.Feature) {
 	}
 }
 
+func (p *featuresPanel) addConditionLine(parent *unison.Panel, c *model.FeatureCondition) {
+	parent.AddChild(unison.NewPanel())
+	wrapper := unison.NewPanel()
+	wrapper.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	details := unison.NewPanel()
+	details.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	popup := addPopup(wrapper, model.AllFeatureConditionType, &c.Type)
+	callback := popup.SelectionChangedCallback
+	popup.SelectionChangedCallback = func(pop *unison.PopupMenu[model.FeatureConditionType]) {
+		callback(pop)
+		details.RemoveAllChildren()
+		p.addConditionDetails(details, c)
+		unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
+	}
+	p.addConditionDetails(details, c)
+	wrapper.AddChild(details)
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	parent.AddChild(wrapper)
+}
+
+func (p *featuresPanel) addConditionDetails(parent *unison.Panel, c *model.FeatureCondition) {
+	switch c.Type {
+	case model.ExpressionFeatureConditionType:
+		field := addStringField(parent, i18n.Text("Condition Expression"), "", &c.Expression)
+		field.Tooltip = unison.NewTooltipWithText(i18n.Text(`For example: !has_trait(Berserk) or $st >= 12`))
+		field.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			HGrab:  true,
+		})
+	case model.EncumbranceFeatureConditionType:
+		addPopup(parent, model.AllEncumbrance, &c.MinEncumbrance)
+		parent.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("and")))
+		addPopup(parent, model.AllEncumbrance, &c.MaxEncumbrance)
+	case model.EquippedNameFeatureConditionType, model.EquippedTagFeatureConditionType:
+		field := addStringField(parent, i18n.Text("Condition Qualifier"), "", &c.Qualifier)
+		field.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			HGrab:  true,
+		})
+	case model.ToggleFeatureConditionType:
+		parent.AddChild(NewCheckBox(nil, "", i18n.Text("Currently on"),
+			func() unison.CheckState { return unison.CheckStateFromBool(c.Active) },
+			func(state unison.CheckState) {
+				c.Active = state == unison.OnCheckState
+				MarkModified(parent)
+			}))
+	default:
+		parent.AddChild(unison.NewPanel())
+	}
+	parent.SetLayout(&unison.FlexLayout{
+		Columns:  len(parent.Children()),
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+}
+
 func (p *featuresPanel) createBasePanel(f model.Feature) *unison.Panel {
 	panel := unison.NewPanel()
 	panel.SetLayout(&unison.FlexLayout{
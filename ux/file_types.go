@@ -124,6 +124,7 @@ func RegisterGCSFileTypes() {
 	registerGCSFileInfo("GCS Skills", model.SkillsExt, groupWith, svg.GCSSkills, NewSkillTableDockableFromFile)
 	registerGCSFileInfo("GCS Spells", model.SpellsExt, groupWith, svg.GCSSpells, NewSpellTableDockableFromFile)
 	registerGCSFileInfo("GCS Notes", model.NotesExt, groupWith, svg.GCSNotes, NewNoteTableDockableFromFile)
+	registerGCSFileInfo("GCS Random Table", model.RandomTableExt, []string{model.RandomTableExt}, svg.Randomize, NewRandomTableDockable)
 }
 
 func registerGCSFileInfo(name, ext string, groupWith []string, svg *unison.SVG, loader func(filePath string) (unison.Dockable, error)) {
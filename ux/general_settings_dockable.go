@@ -49,6 +49,7 @@ type generalSettingsDockable struct {
 	maxAutoColWidthField          *IntegerField
 	monitorResolutionField        *IntegerField
 	exportResolutionField         *IntegerField
+	autosaveIntervalField         *IntegerField
 	tooltipDelayField             *DecimalField
 	tooltipDismissalField         *DecimalField
 	scrollWheelMultiplierField    *DecimalField
@@ -119,6 +120,7 @@ func (d *generalSettingsDockable) initContent(content *unison.Panel) {
 	d.createCellAutoMaxWidthField(content)
 	d.createMonitorResolutionField(content)
 	d.createImageResolutionField(content)
+	d.createAutosaveIntervalField(content)
 	d.createTooltipDelayField(content)
 	d.createTooltipDismissalField(content)
 	d.createScrollWheelMultiplierField(content)
@@ -254,6 +256,17 @@ func (d *generalSettingsDockable) createImageResolutionField(content *unison.Pan
 	content.AddChild(WrapWithSpan(2, d.exportResolutionField, NewFieldTrailingLabel(i18n.Text("ppi"))))
 }
 
+func (d *generalSettingsDockable) createAutosaveIntervalField(content *unison.Panel) {
+	title := i18n.Text("Autosave Interval")
+	content.AddChild(NewFieldLeadingLabel(title))
+	d.autosaveIntervalField = NewIntegerField(nil, "", title,
+		func() int { return model.GlobalSettings().General.AutosaveInterval },
+		func(v int) { model.GlobalSettings().General.AutosaveInterval = v },
+		model.AutosaveIntervalMin, model.AutosaveIntervalMax, false, false)
+	d.autosaveIntervalField.Tooltip = unison.NewTooltipWithText(i18n.Text("How often modified documents are snapshotted so that they can be recovered after a crash"))
+	content.AddChild(WrapWithSpan(2, d.autosaveIntervalField, NewFieldTrailingLabel(i18n.Text("minutes"))))
+}
+
 func (d *generalSettingsDockable) createTooltipDelayField(content *unison.Panel) {
 	title := i18n.Text("Tooltip Delay")
 	content.AddChild(NewFieldLeadingLabel(title))
@@ -364,6 +377,7 @@ func (d *generalSettingsDockable) sync() {
 	d.maxAutoColWidthField.SetText(strconv.Itoa(s.MaximumAutoColWidth))
 	d.monitorResolutionField.SetText(strconv.Itoa(s.MonitorResolution))
 	d.exportResolutionField.SetText(strconv.Itoa(s.ImageResolution))
+	d.autosaveIntervalField.SetText(strconv.Itoa(s.AutosaveInterval))
 	d.tooltipDelayField.SetText(s.TooltipDelay.String())
 	d.tooltipDismissalField.SetText(s.TooltipDismissal.String())
 	d.scrollWheelMultiplierField.SetText(s.ScrollWheelMultiplier.String())
@@ -14,6 +14,7 @@ package ux
 import (
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xmath/rand"
 	"github.com/richardwilkes/unison"
 )
 
@@ -58,7 +59,7 @@ func NewIdentityPanel(entity *model.Entity, targetMgr *TargetMgr) *IdentityPanel
 		func(s string) { p.entity.Profile.Name = s })
 	p.AddChild(NewPageLabelWithRandomizer(title,
 		i18n.Text("Randomize the name using the current ancestry"), func() {
-			p.entity.Profile.Name = p.entity.Ancestry().RandomName(
+			p.entity.Profile.Name = p.entity.Ancestry().RandomName(rand.NewCryptoRand(),
 				model.AvailableNameGenerators(model.GlobalSettings().Libraries()), p.entity.Profile.Gender)
 			SetTextAndMarkModified(nameField.Field, p.entity.Profile.Name)
 		}))
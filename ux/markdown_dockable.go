@@ -17,6 +17,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/svg"
@@ -28,7 +29,10 @@ import (
 	"github.com/richardwilkes/unison"
 )
 
-const markdownContentOnlyPrefix = "//////////"
+const (
+	markdownContentOnlyPrefix = "//////////"
+	markdownRenderDelay       = 250 * time.Millisecond
+)
 
 var (
 	_ FileBackedDockable = &MarkdownDockable{}
@@ -42,6 +46,7 @@ type MarkdownDockable struct {
 	path              string
 	original          string
 	content           string
+	rendered          string
 	undoMgr           *unison.UndoManager
 	scroller          *unison.ScrollPanel
 	markdown          *unison.Markdown
@@ -54,6 +59,7 @@ type MarkdownDockable struct {
 	inDrag            bool
 	allowEditing      bool
 	needsSaveAsPrompt bool
+	renderPending     bool
 }
 
 // ShowReadOnlyMarkdown attempts to show the given markdown content in a dockable.
@@ -119,13 +125,13 @@ func newMarkdownDockable(filePath, content string, allowEditing, startInEditMode
 		d.original = string(data)
 	}
 	d.content = d.original
-	d.markdown.SetContent(d.content, 0)
+	d.renderMarkdown()
 
 	d.editor = NewMultiLineStringField(nil, "", "",
 		func() string { return d.content },
 		func(value string) {
 			d.content = value
-			d.markdown.SetContent(value, 0)
+			d.eventuallyRenderMarkdown()
 			d.editor.MarkForLayoutAndRedraw()
 			MarkModified(d.editor)
 		})
@@ -213,6 +219,28 @@ func newMarkdownDockable(filePath, content string, allowEditing, startInEditMode
 	return d, nil
 }
 
+// renderMarkdown updates the displayed markdown from the content, expanding any wiki links with their current values.
+func (d *MarkdownDockable) renderMarkdown() {
+	rendered := model.ExpandWikiLinks(d.content, func(link *model.WikiLink) string {
+		return wikiLinkText(d.markdown.WorkingDir, link)
+	})
+	if rendered != d.rendered {
+		d.rendered = rendered
+		d.markdown.SetContent(rendered, 0)
+	}
+}
+
+// eventuallyRenderMarkdown calls renderMarkdown() after a small delay, collapsing intervening requests to do the same.
+func (d *MarkdownDockable) eventuallyRenderMarkdown() {
+	if !d.renderPending {
+		d.renderPending = true
+		unison.InvokeTaskAfter(func() {
+			d.renderPending = false
+			d.renderMarkdown()
+		}, markdownRenderDelay)
+	}
+}
+
 func (d *MarkdownDockable) updateCursor(_ unison.Point) *unison.Cursor {
 	if d.inDrag {
 		return unison.MoveCursor()
@@ -340,6 +368,27 @@ func (d *MarkdownDockable) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (d *MarkdownDockable) untitled() bool {
+	return d.needsSaveAsPrompt
+}
+
+func (d *MarkdownDockable) snapshot(w io.Writer) error {
+	_, err := w.Write([]byte(d.content))
+	return errs.Wrap(err)
+}
+
+func (d *MarkdownDockable) adoptRecoveredSnapshot(info *model.RecoveryInfo) {
+	d.path = info.OriginalPath
+	d.needsSaveAsPrompt = info.Untitled
+	d.original = ""
+	if !info.Untitled {
+		d.markdown.WorkingDir = filepath.Dir(d.path)
+		if data, err := os.ReadFile(d.path); err == nil {
+			d.original = string(data)
+		}
+	}
+}
+
 func (d *MarkdownDockable) saveData(filePath string) error {
 	dirPath := filepath.Dir(filePath)
 	if err := os.MkdirAll(dirPath, 0o750); err != nil {
@@ -32,6 +32,7 @@ import (
 const (
 	NewSheetItemID = unison.UserBaseID + iota
 	NewTemplateItemID
+	GenerateNPCsItemID
 	NewTraitsLibraryItemID
 	NewTraitModifiersLibraryItemID
 	NewEquipmentLibraryItemID
@@ -40,6 +41,7 @@ const (
 	NewSkillsLibraryItemID
 	NewSpellsLibraryItemID
 	NewMarkdownFileItemID
+	NewRandomTableItemID
 	OpenItemID
 	CloseTabID
 	RecentFilesMenuID
@@ -50,6 +52,8 @@ const (
 	ExportAsWEBPItemID
 	ExportAsPNGItemID
 	ExportAsJPEGItemID
+	ExportAsBundleItemID
+	ImportBundleItemID
 	PrintItemID
 	UndoItemID
 	RedoItemID
@@ -58,6 +62,15 @@ const (
 	ConvertToContainerItemID
 	ConvertToNonContainerItemID
 	ToggleStateItemID
+	ToggleFeaturesItemID
+	StudyPlannerItemID
+	CastSpellItemID
+	ExportCardsItemID
+	SearchRulebooksItemID
+	BuiltInRandomTablesMenuID
+	CommandPaletteItemID
+	CampaignClockItemID
+	ReactionRollItemID
 	IncrementItemID
 	DecrementItemID
 	IncrementUsesItemID
@@ -79,10 +92,12 @@ const (
 	PerSheetSettingsItemID
 	PerSheetAttributeSettingsItemID
 	PerSheetBodyTypeSettingsItemID
+	LinkToCampaignItemID
 	DefaultSheetSettingsItemID
 	DefaultAttributeSettingsItemID
 	DefaultBodyTypeSettingsItemID
 	GeneralSettingsItemID
+	CampaignSettingsItemID
 	PageRefMappingsItemID
 	ColorSettingsItemID
 	FontSettingsItemID
@@ -143,6 +158,7 @@ const (
 	LibraryBaseItemID
 	RecentFieldBaseItemID  = LibraryBaseItemID + 1000
 	ExportToTextBaseItemID = RecentFieldBaseItemID + 1000
+	RandomTableBaseItemID  = ExportToTextBaseItemID + 1000
 )
 
 var registerKeyBindingsOnce sync.Once
@@ -181,6 +197,7 @@ func (s menuBarScope) setupFileMenu(bar unison.Menu) {
 	m := bar.Menu(unison.FileMenuID)
 	i := s.insertMenuItem(m, 0, newCharacterSheetAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newCharacterTemplateAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, generateNPCsAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newMarkdownFileAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
@@ -191,9 +208,11 @@ func (s menuBarScope) setupFileMenu(bar unison.Menu) {
 	i = s.insertMenuItem(m, i, newEquipmentLibraryAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newEquipmentModifiersLibraryAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, newNotesLibraryAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, newRandomTableAction.NewMenuItem(f))
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, openAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, importBundleAction.NewMenuItem(f))
 	s.insertMenu(m, i, f.NewMenu(RecentFilesMenuID, i18n.Text("Recent Files"), s.recentFilesUpdater))
 
 	i = m.Item(unison.CloseItemID).Index()
@@ -241,6 +260,9 @@ func (s menuBarScope) setupEditMenu(bar unison.Menu) {
 
 	i = s.insertMenuSeparator(m, i)
 	i = s.insertMenuItem(m, i, toggleStateAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, toggleFeaturesAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, studyPlannerAction.NewMenuItem(f))
+	i = s.insertMenuItem(m, i, castSpellAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, swapDefaultsAction.NewMenuItem(f))
 	i = s.insertMenuItem(m, i, convertToContainerAction.NewMenuItem(f))
 	s.insertMenuItem(m, i, convertToNonContainerAction.NewMenuItem(f))
@@ -284,6 +306,11 @@ func (s menuBarScope) createItemMenu(f unison.MenuFactory) unison.Menu {
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, openOnePageReferenceAction.NewMenuItem(f))
 	m.InsertItem(-1, openEachPageReferenceAction.NewMenuItem(f))
+	m.InsertItem(-1, searchRulebooksAction.NewMenuItem(f))
+
+	m.InsertSeparator(-1, false)
+	m.InsertItem(-1, reactionRollAction.NewMenuItem(f))
+	m.InsertMenu(-1, f.NewMenu(BuiltInRandomTablesMenuID, i18n.Text("Built-in Random Tables"), s.builtInRandomTablesUpdater))
 	return m
 }
 
@@ -292,12 +319,15 @@ func (s menuBarScope) createSettingsMenu(f unison.MenuFactory) unison.Menu {
 	m.InsertItem(-1, perSheetSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, perSheetAttributeSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, perSheetBodyTypeSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, linkToCampaignAction.NewMenuItem(f))
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, defaultSheetSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, defaultAttributeSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, defaultBodyTypeSettingsAction.NewMenuItem(f))
 	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, generalSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, campaignSettingsAction.NewMenuItem(f))
+	m.InsertItem(-1, campaignClockAction.NewMenuItem(f))
 	m.InsertItem(-1, pageRefMappingsAction.NewMenuItem(f))
 	m.InsertItem(-1, colorSettingsAction.NewMenuItem(f))
 	m.InsertItem(-1, fontSettingsAction.NewMenuItem(f))
@@ -308,6 +338,8 @@ func (s menuBarScope) createSettingsMenu(f unison.MenuFactory) unison.Menu {
 func (s menuBarScope) createViewMenu(f unison.MenuFactory) unison.Menu {
 	m := f.NewMenu(ViewMenuID, i18n.Text("View"), nil)
 
+	m.InsertItem(-1, commandPaletteAction.NewMenuItem(f))
+	m.InsertSeparator(-1, false)
 	m.InsertItem(-1, scaleDefaultAction.NewMenuItem(f))
 	m.InsertItem(-1, scaleUpAction.NewMenuItem(f))
 	m.InsertItem(-1, scaleDownAction.NewMenuItem(f))
@@ -370,6 +402,21 @@ func (s menuBarScope) createOpenRecentFileAction(index int, path, title string)
 	}
 }
 
+func (s menuBarScope) builtInRandomTablesUpdater(menu unison.Menu) {
+	menu.RemoveAll()
+	for i, ref := range model.BuiltInRandomTables() {
+		ref := ref
+		menu.InsertItem(-1, (&unison.Action{
+			ID:              RandomTableBaseItemID + i,
+			Title:           ref.Name,
+			ExecuteCallback: func(_ *unison.Action, _ any) { ShowBuiltInRandomTable(ref) },
+		}).NewMenuItem(menu.Factory()))
+	}
+	if menu.Count() == 0 {
+		s.appendDisabledMenuItem(menu, i18n.Text("No built-in random tables available"))
+	}
+}
+
 func (s menuBarScope) exportToUpdater(menu unison.Menu) {
 	const outputTemplatesDirName = "Output Templates"
 	menu.RemoveAll()
@@ -378,6 +425,8 @@ func (s menuBarScope) exportToUpdater(menu unison.Menu) {
 	menu.InsertItem(-1, exportAsWEBPAction.NewMenuItem(factory))
 	menu.InsertItem(-1, exportAsPNGAction.NewMenuItem(factory))
 	menu.InsertItem(-1, exportAsJPEGAction.NewMenuItem(factory))
+	menu.InsertItem(-1, exportCardsAction.NewMenuItem(factory))
+	menu.InsertItem(-1, exportAsBundleAction.NewMenuItem(factory))
 	menu.InsertSeparator(-1, false)
 	index := 0
 	for _, lib := range model.GlobalSettings().Libraries().List() {
@@ -479,6 +528,10 @@ func AppendDefaultContextMenuItems(list []ContextMenuItem) []ContextMenuItem {
 		ContextMenuItem{i18n.Text("Decrease Tech Level"), DecrementTechLevelItemID},
 		ContextMenuItem{"", -1},
 		ContextMenuItem{i18n.Text("Toggle State"), ToggleStateItemID},
+		ContextMenuItem{i18n.Text("Toggle Situational Features"), ToggleFeaturesItemID},
+		ContextMenuItem{i18n.Text("Study Planner…"), StudyPlannerItemID},
+		ContextMenuItem{i18n.Text("Cast Spell…"), CastSpellItemID},
+		ContextMenuItem{i18n.Text("Export Cards to PDF…"), ExportCardsItemID},
 		ContextMenuItem{i18n.Text("Swap Defaults"), SwapDefaultsItemID},
 		ContextMenuItem{i18n.Text("Convert to Container"), ConvertToContainerItemID},
 		ContextMenuItem{i18n.Text("Convert to Non-Container"), ConvertToNonContainerItemID},
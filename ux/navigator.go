@@ -22,6 +22,7 @@ import (
 	"github.com/google/uuid"
 	gsettings "github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox"
 	"github.com/richardwilkes/toolbox/desktop"
 	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/toolbox/txt"
@@ -58,6 +59,7 @@ type Navigator struct {
 	downloadLibraryButton     *unison.Button
 	libraryReleaseNotesButton *unison.Button
 	configLibraryButton       *unison.Button
+	campaignOnlyButton        *unison.Button
 	scroll                    *unison.ScrollPanel
 	table                     *unison.Table[*NavigatorNode]
 	tokens                    []*gsettings.MonitorToken
@@ -78,7 +80,7 @@ func newNavigator() *Navigator {
 
 	n.table.Columns = make([]unison.ColumnInfo, 1)
 	globalSettings := gsettings.GlobalSettings()
-	libs := globalSettings.LibrarySet.List()
+	libs := n.libraries()
 	rows := make([]*NavigatorNode, 0, len(libs))
 	n.needReload = true
 	for _, lib := range libs {
@@ -154,6 +156,12 @@ func (n *Navigator) setupToolBar() {
 	n.configLibraryButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Configure"))
 	n.configLibraryButton.ClickCallback = n.configureSelection
 
+	n.campaignOnlyButton = unison.NewSVGButton(svg.Bookmark)
+	n.campaignOnlyButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Show only the libraries used by the active campaign"))
+	n.campaignOnlyButton.Sticky = gsettings.GlobalSettings().LibraryExplorer.CampaignOnly
+	n.campaignOnlyButton.SetEnabled(gsettings.GlobalSettings().ActiveCampaign() != nil)
+	n.campaignOnlyButton.ClickCallback = n.toggleCampaignOnly
+
 	first := unison.NewPanel()
 	first.AddChild(NewDefaultInfoPop())
 	first.AddChild(helpButton)
@@ -170,6 +178,7 @@ func (n *Navigator) setupToolBar() {
 		),
 	)
 	first.AddChild(n.hierarchyButton)
+	first.AddChild(n.campaignOnlyButton)
 	first.AddChild(NewToolbarSeparator())
 	first.AddChild(addLibraryButton)
 	first.AddChild(n.downloadLibraryButton)
@@ -621,7 +630,9 @@ func (n *Navigator) Reload() {
 	n.tokens = nil
 	disclosed := n.DisclosedPaths()
 	selection := n.SelectedPaths()
-	libs := gsettings.GlobalSettings().LibrarySet.List()
+	n.campaignOnlyButton.SetEnabled(gsettings.GlobalSettings().ActiveCampaign() != nil)
+	gsettings.RefreshRandomTableLookups(gsettings.GlobalSettings().Libraries())
+	libs := n.libraries()
 	rows := make([]*NavigatorNode, 0, len(libs))
 	for _, lib := range libs {
 		n.tokens = append(n.tokens, lib.Watch(n.watchCallback, true))
@@ -634,6 +645,22 @@ func (n *Navigator) Reload() {
 	n.table.SizeColumnsToFit(true)
 }
 
+func (n *Navigator) libraries() []*gsettings.Library {
+	globalSettings := gsettings.GlobalSettings()
+	if globalSettings.LibraryExplorer.CampaignOnly {
+		return globalSettings.CampaignLibraries()
+	}
+	return globalSettings.LibrarySet.List()
+}
+
+func (n *Navigator) toggleCampaignOnly() {
+	globalSettings := gsettings.GlobalSettings()
+	globalSettings.LibraryExplorer.CampaignOnly = !globalSettings.LibraryExplorer.CampaignOnly
+	n.campaignOnlyButton.Sticky = globalSettings.LibraryExplorer.CampaignOnly
+	n.campaignOnlyButton.MarkForRedraw()
+	n.Reload()
+}
+
 func (n *Navigator) adjustTableSizeEventually() {
 	if !n.adjustTableSizePending {
 		n.adjustTableSizePending = true
@@ -874,8 +901,20 @@ func (n *Navigator) ApplySelectedPaths(paths []string) {
 	n.table.SetSelectionMap(selMap)
 }
 
-// HandleLink will try to open http, https, and md links, as well as resolve page references.
+// HandleLink will try to open http, https, md, and wiki links, as well as resolve page references.
 func HandleLink(src unison.Paneler, target string) {
+	if link := gsettings.ParseWikiLinkURL(target); link != nil {
+		var wnd *unison.Window
+		var workingDir string
+		if !toolbox.IsNil(src) {
+			wnd = src.AsPanel().Window()
+			if md, ok := src.AsPanel().Self.(*unison.Markdown); ok {
+				workingDir = md.WorkingDir
+			}
+		}
+		handleWikiLink(wnd, workingDir, link)
+		return
+	}
 	if strings.HasPrefix(strings.ToLower(target), "md:") {
 		if revised, err := url.PathUnescape(target); err == nil {
 			target = revised
@@ -933,6 +972,7 @@ func DisplayNewDockable(wnd *unison.Window, dockable unison.Dockable) {
 		}
 	}()
 	if fbd, ok := dockable.(FileBackedDockable); ok {
+		watchBackingFile(fbd)
 		fi := gsettings.FileInfoFor(fbd.BackingFilePath())
 		if dc := ws.CurrentlyFocusedDockContainer(); dc != nil && DockContainerHoldsExtension(dc, fi.GroupWith...) {
 			dc.Stack(dockable, -1)
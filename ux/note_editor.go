@@ -85,6 +85,21 @@ func initNoteEditor(e *editor[*model.Note, *model.NoteEditData], content *unison
 	}
 	content.AddChild(field)
 
+	if model.GlobalSettings().CampaignClock() != nil {
+		content.AddChild(unison.NewPanel())
+		dateButton := unison.NewButton()
+		dateButton.Text = i18n.Text("Insert In-Game Date")
+		dateButton.ClickCallback = func() {
+			date := model.GlobalSettings().CurrentGameDate(e.target.OwningEntity())
+			text := []rune(field.Text())
+			start, end := field.Selection()
+			field.SetText(string(text[:start]) + date + string(text[end:]))
+			field.RequestFocus()
+			field.SetSelectionTo(start + len([]rune(date)))
+		}
+		content.AddChild(dateButton)
+	}
+
 	addPageRefLabelAndField(content, &e.editorData.PageRef)
 
 	label = unison.NewLabel()
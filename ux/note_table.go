@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -51,6 +52,7 @@ func NewNoteTableDockable(filePath string, notes []*model.Note) *TableDockable[*
 	provider := &noteListProvider{notes: notes}
 	d := NewTableDockable(filePath, model.NotesExt, NewNotesProvider(provider, false),
 		func(path string) error { return model.SaveNotes(provider.NoteList(), path) },
+		func(w io.Writer) error { return model.SaveNotesTo(provider.NoteList(), w) },
 		NewNoteItemID, NewNoteContainerItemID)
 	InstallContainerConversionHandlers(d, d, d.table)
 	return d
@@ -0,0 +1,223 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/i18n"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/toolbox/xmath/rand"
+	"github.com/richardwilkes/unison"
+)
+
+const maxGeneratedNPCs = 100
+
+type npcGeneratorPanel struct {
+	unison.Panel
+	generator     model.NPCGenerator
+	templatePaths []string
+	templateList  *unison.Label
+}
+
+// ShowNPCGenerator asks the user for the parameters for generating random NPCs and then opens a new sheet for each one
+// that was generated.
+func ShowNPCGenerator() {
+	p := newNPCGeneratorPanel()
+	dialog, err := unison.NewDialog(nil, nil, p,
+		[]*unison.DialogButtonInfo{unison.NewCancelButtonInfo(), {
+			Title:        i18n.Text("Generate"),
+			ResponseCode: unison.ModalResponseOK,
+			KeyCodes:     []unison.KeyCode{unison.KeyReturn, unison.KeyNumPadEnter},
+		}})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create NPC generator dialog"), err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	g := p.generator
+	for _, one := range p.templatePaths {
+		var t *model.Template
+		if t, err = model.NewTemplateFromFile(os.DirFS(filepath.Dir(one)), filepath.Base(one)); err != nil {
+			unison.ErrorDialogWithError(i18n.Text("Unable to load template"), err)
+			return
+		}
+		g.Templates = append(g.Templates, t)
+	}
+	var list []*model.Entity
+	if list, err = g.Generate(); err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to generate NPCs"), err)
+		return
+	}
+	for _, entity := range list {
+		DisplayNewDockable(nil, NewSheet(entity.Profile.Name+model.SheetExt, entity))
+	}
+}
+
+func newNPCGeneratorPanel() *npcGeneratorPanel {
+	p := &npcGeneratorPanel{
+		generator: model.NPCGenerator{
+			Ancestry: model.DefaultAncestry,
+			Points:   model.GlobalSettings().InitialPoints(),
+			Count:    1,
+			Seed:     newNPCSeed(),
+		},
+	}
+	p.Self = p
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	p.addAncestryPopup()
+	p.addTemplatesBlock()
+	title := i18n.Text("Point Budget")
+	p.AddChild(NewFieldLeadingLabel(title))
+	p.AddChild(NewDecimalField(nil, "", title,
+		func() fxp.Int { return p.generator.Points },
+		func(v fxp.Int) { p.generator.Points = v }, model.InitialPointsMin, model.InitialPointsMax, false, false))
+	title = i18n.Text("Count")
+	p.AddChild(NewFieldLeadingLabel(title))
+	p.AddChild(NewIntegerField(nil, "", title,
+		func() int { return p.generator.Count },
+		func(v int) { p.generator.Count = v }, 1, maxGeneratedNPCs, false, false))
+	p.addSeedField()
+	return p
+}
+
+func (p *npcGeneratorPanel) addAncestryPopup() {
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Ancestry")))
+	popup := unison.NewPopupMenu[string]()
+	for _, lib := range model.AvailableAncestries(model.GlobalSettings().Libraries()) {
+		for _, one := range lib.List {
+			popup.AddItem(one.Name)
+		}
+	}
+	popup.Select(p.generator.Ancestry)
+	popup.SelectionChangedCallback = func(popup *unison.PopupMenu[string]) {
+		if item, ok := popup.Selected(); ok {
+			p.generator.Ancestry = item
+		}
+	}
+	p.AddChild(popup)
+}
+
+func (p *npcGeneratorPanel) addTemplatesBlock() {
+	label := NewFieldLeadingLabel(i18n.Text("Templates"))
+	label.SetLayoutData(&unison.FlexLayoutData{VAlign: unison.StartAlignment})
+	p.AddChild(label)
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	p.templateList = unison.NewLabel()
+	p.updateTemplateList()
+	wrapper.AddChild(p.templateList)
+	buttons := unison.NewPanel()
+	buttons.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	addButton := unison.NewButton()
+	addButton.Text = i18n.Text("Add Templates…")
+	addButton.ClickCallback = p.addTemplates
+	buttons.AddChild(addButton)
+	clearButton := unison.NewButton()
+	clearButton.Text = i18n.Text("Clear")
+	clearButton.ClickCallback = func() {
+		p.templatePaths = nil
+		p.updateTemplateList()
+	}
+	buttons.AddChild(clearButton)
+	wrapper.AddChild(buttons)
+	p.AddChild(wrapper)
+}
+
+func (p *npcGeneratorPanel) addTemplates() {
+	dialog := unison.NewOpenDialog()
+	dialog.SetAllowsMultipleSelection(true)
+	dialog.SetResolvesAliases(true)
+	dialog.SetAllowedExtensions(model.TemplatesExt)
+	dialog.SetCanChooseDirectories(false)
+	dialog.SetCanChooseFiles(true)
+	settings := model.GlobalSettings()
+	dialog.SetInitialDirectory(settings.LastDir(model.DefaultLastDirKey))
+	if dialog.RunModal() {
+		paths := dialog.Paths()
+		settings.SetLastDir(model.DefaultLastDirKey, filepath.Dir(paths[0]))
+		p.templatePaths = append(p.templatePaths, paths...)
+		p.updateTemplateList()
+	}
+}
+
+func (p *npcGeneratorPanel) updateTemplateList() {
+	if len(p.templatePaths) == 0 {
+		p.templateList.Text = i18n.Text("None")
+	} else {
+		names := make([]string, 0, len(p.templatePaths))
+		for _, one := range p.templatePaths {
+			names = append(names, xfs.BaseName(one))
+		}
+		p.templateList.Text = strings.Join(names, ", ")
+	}
+	p.MarkForLayoutAndRedraw()
+	if w := p.Window(); w != nil {
+		w.Pack()
+	}
+}
+
+func (p *npcGeneratorPanel) addSeedField() {
+	title := i18n.Text("Seed")
+	label := NewFieldLeadingLabel(title)
+	label.Tooltip = unison.NewTooltipWithText(i18n.Text("The same seed and settings will always generate the same NPCs"))
+	p.AddChild(label)
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	field := NewStringField(nil, "", title,
+		func() string { return strconv.FormatInt(p.generator.Seed, 10) },
+		func(s string) {
+			if seed, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+				p.generator.Seed = seed
+			}
+		})
+	field.ValidateCallback = func() bool {
+		_, err := strconv.ParseInt(strings.TrimSpace(field.Text()), 10, 64)
+		return err == nil
+	}
+	field.SetMinimumTextWidthUsing(strconv.FormatInt(math.MaxInt64, 10))
+	wrapper.AddChild(field)
+	randomizeButton := unison.NewSVGButton(svg.Randomize)
+	randomizeButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Choose a new random seed"))
+	randomizeButton.ClickCallback = func() {
+		p.generator.Seed = newNPCSeed()
+		field.SetText(strconv.FormatInt(p.generator.Seed, 10))
+	}
+	wrapper.AddChild(randomizeButton)
+	p.AddChild(wrapper)
+}
+
+func newNPCSeed() int64 {
+	return int64(rand.NewCryptoRand().Intn(math.MaxInt32))
+}
@@ -33,18 +33,24 @@ type pageExporter struct {
 	unison.Panel
 	entity      *model.Entity
 	targetMgr   *TargetMgr
-	pages       []*Page
+	pages       []unison.Paneler
+	title       string
+	keywords    string
 	currentPage int
 }
 
 func newPageExporter(entity *model.Entity) *pageExporter {
-	p := &pageExporter{entity: entity}
+	p := &pageExporter{
+		entity:   entity,
+		title:    entity.Profile.Name,
+		keywords: "GCS Character Sheet",
+	}
 	p.targetMgr = NewTargetMgr(p)
 	pageSize := p.PageSize()
 	r := unison.Rect{Size: pageSize}
 	page, _ := createPageTopBlock(entity, p.targetMgr)
 	p.AddChild(page)
-	p.pages = append(p.pages, page)
+	pages := []*Page{page}
 	for _, col := range entity.SheetSettings.BlockLayout.ByRow() {
 		startAt := make(map[string]int)
 		for {
@@ -75,6 +81,10 @@ func newPageExporter(entity *model.Entity) *pageExporter {
 					addRowPanel(rowPanel, NewOtherEquipmentPageList(p, entity), model.BlockLayoutOtherEquipmentKey, startAt)
 				case model.BlockLayoutNotesKey:
 					addRowPanel(rowPanel, NewNotesPageList(p, entity), model.BlockLayoutNotesKey, startAt)
+				default:
+					if entity.SheetSettings.CustomBlockFor(c) != nil {
+						addRowPanel(rowPanel, NewCustomBlockPanel(entity, c), c, startAt)
+					}
 				}
 			}
 			children := rowPanel.Children()
@@ -110,7 +120,7 @@ func newPageExporter(entity *model.Entity) *pageExporter {
 				page.RemoveChild(rowPanel)
 				page = NewPage(entity)
 				p.AddChild(page)
-				p.pages = append(p.pages, page)
+				pages = append(pages, page)
 				page.AddChild(rowPanel)
 				page.SetFrameRect(r)
 				page.MarkForLayoutRecursively()
@@ -149,15 +159,16 @@ func newPageExporter(entity *model.Entity) *pageExporter {
 				// We've filled the page, so add another
 				page = NewPage(entity)
 				p.AddChild(page)
-				p.pages = append(p.pages, page)
+				pages = append(pages, page)
 			}
 		}
 	}
-	for _, page = range p.pages {
+	for _, page = range pages {
 		page.Force = true
 		page.SetFrameRect(r)
 		page.MarkForLayoutRecursively()
 		page.ValidateLayout()
+		p.pages = append(p.pages, page)
 	}
 	return p
 }
@@ -200,8 +211,15 @@ func (s *pageState) key() string {
 	return s.child.ClientData()[pageKey].(string)
 }
 
-func addRowPanel[T model.NodeTypes](rowPanel *unison.Panel, list *PageList[T], key string, startAtMap map[string]int) {
-	list.ClientData()[pageKey] = key
+// pageRowPanel is a panel whose rows may be split across pages.
+type pageRowPanel interface {
+	unison.Paneler
+	pageHelper
+	RowCount() int
+}
+
+func addRowPanel(rowPanel *unison.Panel, list pageRowPanel, key string, startAtMap map[string]int) {
+	list.AsPanel().ClientData()[pageKey] = key
 	count := list.RowCount()
 	startAt := startAtMap[key]
 	if count > startAt {
@@ -235,10 +253,10 @@ func (p *pageExporter) exportAsPDF(stream unison.Stream) error {
 	savedColorMode := p.saveTheme()
 	defer p.restoreTheme(savedColorMode)
 	if err := unison.CreatePDF(stream, &unison.PDFMetaData{
-		Title:           p.entity.Profile.Name,
+		Title:           p.title,
 		Author:          toolbox.CurrentUserName(),
-		Subject:         p.entity.Profile.Name,
-		Keywords:        "GCS Character Sheet",
+		Subject:         p.title,
+		Keywords:        p.keywords,
 		Creator:         "GCS",
 		RasterDPI:       300,
 		EncodingQuality: 101,
@@ -318,7 +336,8 @@ func (p *pageExporter) HasPage(pageNumber int) bool {
 
 // PageSize implements unison.PageProvider.
 func (p *pageExporter) PageSize() unison.Size {
-	w, h := p.entity.SheetSettings.Page.Orientation.Dimensions(p.entity.SheetSettings.Page.Size.Dimensions())
+	settings := model.SheetSettingsFor(p.entity)
+	w, h := settings.Page.Orientation.Dimensions(settings.Page.Size.Dimensions())
 	return unison.NewSize(w.Pixels(), h.Pixels())
 }
 
@@ -326,7 +345,7 @@ func (p *pageExporter) PageSize() unison.Size {
 func (p *pageExporter) DrawPage(canvas *unison.Canvas, pageNumber int) error {
 	p.currentPage = pageNumber
 	if pageNumber > 0 && pageNumber <= len(p.pages) {
-		page := p.pages[pageNumber-1]
+		page := p.pages[pageNumber-1].AsPanel()
 		page.Draw(canvas, page.ContentRect(true))
 		return nil
 	}
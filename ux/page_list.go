@@ -37,6 +37,8 @@ func NewTraitsPageList(owner Rebuildable, provider model.ListProvider) *PageList
 	p.installToggleDisabledHandler(owner)
 	p.installIncrementLevelHandler(owner)
 	p.installDecrementLevelHandler(owner)
+	p.installToggleFeaturesHandler(owner)
+	p.installStudyPlannerHandler(owner)
 	return p
 }
 
@@ -51,6 +53,7 @@ func NewCarriedEquipmentPageList(owner Rebuildable, provider model.ListProvider)
 	p.installIncrementTechLevelHandler(owner)
 	p.installDecrementTechLevelHandler(owner)
 	p.installContainerConversionHandlers(owner)
+	p.installToggleFeaturesHandler(owner)
 	return p
 }
 
@@ -76,6 +79,8 @@ func NewSkillsPageList(owner Rebuildable, provider model.ListProvider) *PageList
 	p.installDecrementSkillHandler(owner)
 	p.installIncrementTechLevelHandler(owner)
 	p.installDecrementTechLevelHandler(owner)
+	p.installToggleFeaturesHandler(owner)
+	p.installStudyPlannerHandler(owner)
 	return p
 }
 
@@ -86,6 +91,8 @@ func NewSpellsPageList(owner Rebuildable, provider model.SpellListProvider) *Pag
 	p.installDecrementPointsHandler(owner)
 	p.installIncrementSkillHandler(owner)
 	p.installDecrementSkillHandler(owner)
+	p.installStudyPlannerHandler(owner)
+	p.installCastSpellHandler(owner)
 	return p
 }
 
@@ -117,6 +124,7 @@ func NewRangedWeaponsPageList(entity *model.Entity) *PageList[*model.Weapon] {
 }
 
 func newPageList[T model.NodeTypes](owner Rebuildable, provider TableProvider[T]) *PageList[T] {
+	provider = newColumnFilterProvider(provider)
 	header, table := NewNodeTable[T](provider, model.PageFieldPrimaryFont)
 	table.RefKey = provider.RefKey()
 	p := &PageList[T]{
@@ -167,6 +175,9 @@ func newPageList[T model.NodeTypes](owner Rebuildable, provider TableProvider[T]
 			func(_ any) { DuplicateSelection(p.Table) })
 	}
 	p.installOpenPageReferenceHandlers()
+	p.InstallCmdHandlers(ExportCardsItemID,
+		func(_ any) bool { return canExportCards(p.Table) },
+		func(_ any) { exportCards(p.provider.Entity(), p.Table) })
 	p.SetLayoutData(&unison.FlexLayoutData{
 		HAlign: unison.FillAlignment,
 		VAlign: unison.FillAlignment,
@@ -200,6 +211,24 @@ func (p *PageList[T]) installToggleEquippedHandler(owner Rebuildable) {
 	}
 }
 
+func (p *PageList[T]) installToggleFeaturesHandler(owner Rebuildable) {
+	p.InstallCmdHandlers(ToggleFeaturesItemID,
+		func(_ any) bool { return canToggleFeatures(p.Table) },
+		func(_ any) { toggleFeatures(owner, p.Table) })
+}
+
+func (p *PageList[T]) installStudyPlannerHandler(owner Rebuildable) {
+	p.InstallCmdHandlers(StudyPlannerItemID,
+		func(_ any) bool { return canPlanStudy(p.Table) },
+		func(_ any) { planStudy(owner, p.Table) })
+}
+
+func (p *PageList[T]) installCastSpellHandler(owner Rebuildable) {
+	p.InstallCmdHandlers(CastSpellItemID,
+		func(_ any) bool { return canCastSpell(p.Table) },
+		func(_ any) { castSpell(owner, p.Table) })
+}
+
 func (p *PageList[T]) installIncrementPointsHandler(owner Rebuildable) {
 	p.InstallCmdHandlers(IncrementItemID,
 		func(_ any) bool { return canAdjustRawPoints(p.Table, true) },
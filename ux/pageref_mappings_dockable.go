@@ -101,7 +101,7 @@ func openPDFPageReference(wnd *unison.Window, ref, highlight string, promptConte
 		}
 		key := ref[:i]
 		s := model.GlobalSettings()
-		pageRef := s.PageRefs.Lookup(key)
+		pageRef := s.LookupPageRef(key)
 		if pageRef == nil && !promptContext[key] {
 			pdfName := PageRefKeyToName(key)
 			if pdfName != "" {
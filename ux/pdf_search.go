@@ -0,0 +1,539 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/pdf"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	pdfSearchDetectDPI          = 18
+	pdfSearchSnippetDPI         = 144
+	pdfSearchMaxHitsPerPage     = 20
+	pdfSearchMaxSnippetsPerPage = 3
+	pdfSearchSnippetWidth       = 480
+	pdfSearchMaxOpenDocs        = 4
+	pdfSearchMaxCachedQueries   = 16
+)
+
+var (
+	_ unison.Dockable  = &pdfSearchDockable{}
+	_ unison.TabCloser = &pdfSearchDockable{}
+
+	pdfSearchCache                            = make(map[string]*pdfSearchCacheEntry)
+	pdfSearchOpenDocs  []*pdfSearchCacheEntry // Most recently used first
+	pdfSearchCacheLock sync.Mutex
+	pdfSearchSequence  atomic.Int64
+)
+
+// pdfSearchHit holds the search hits for a single page of a PDF.
+type pdfSearchHit struct {
+	Path   string
+	Key    string
+	Page   int // 0-based page within the PDF
+	Offset int
+	Count  int
+}
+
+// pdfSearchCacheEntry holds the parsed document and the pages matching the most recent queries for a single PDF. The
+// entry is discarded if the file changes. The pdf package has no way to extract a page's text, so the parsed document
+// is kept instead, although only for the few most recently used files.
+type pdfSearchCacheEntry struct {
+	modTime time.Time
+	size    int64
+	doc     *pdf.Document
+	queries []string // Most recently used first
+	results map[string][]*pdfSearchHit
+}
+
+type pdfSearchDockable struct {
+	unison.Panel
+	searchField *unison.Field
+	statusLabel *unison.Label
+	content     *unison.Panel
+	scroll      *unison.ScrollPanel
+	query       string
+	sequence    int64
+}
+
+// RefPage returns the page number as used within page references.
+func (h *pdfSearchHit) RefPage() int {
+	return h.Page + 1 - h.Offset
+}
+
+// ShowPDFSearch shows the rulebook search.
+func ShowPDFSearch() {
+	ws, dc, found := Activate(func(d unison.Dockable) bool {
+		_, ok := d.(*pdfSearchDockable)
+		return ok
+	})
+	if !found && ws != nil {
+		d := &pdfSearchDockable{}
+		d.Self = d
+		d.SetLayout(&unison.FlexLayout{Columns: 1})
+		d.content = unison.NewPanel()
+		d.content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(unison.StdHSpacing)))
+		d.content.SetLayout(&unison.FlexLayout{
+			Columns:  1,
+			VSpacing: unison.StdVSpacing,
+		})
+		d.scroll = unison.NewScrollPanel()
+		d.scroll.SetContent(d.content, unison.HintedFillBehavior, unison.FillBehavior)
+		d.scroll.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			VAlign: unison.FillAlignment,
+			HGrab:  true,
+			VGrab:  true,
+		})
+		d.AddChild(d.createToolbar())
+		d.AddChild(d.scroll)
+		d.setStatus(i18n.Text("Enter text to search for in every PDF with a page reference mapping"))
+		PlaceInDock(ws, dc, d, EditorGroup)
+		d.searchField.RequestFocus()
+	}
+}
+
+func (d *pdfSearchDockable) createToolbar() *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.Insets{Bottom: 1},
+		false), unison.NewEmptyBorder(unison.StdInsets())))
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+
+	d.searchField = NewSearchField()
+	d.searchField.Watermark = i18n.Text("Search Rulebooks")
+	d.searchField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.MiddleAlignment,
+		HGrab:  true,
+	})
+	d.searchField.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if keyCode == unison.KeyReturn || keyCode == unison.KeyNumPadEnter {
+			d.search()
+			return true
+		}
+		return d.searchField.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	toolbar.AddChild(d.searchField)
+
+	searchButton := unison.NewButton()
+	searchButton.Text = i18n.Text("Search")
+	searchButton.ClickCallback = d.search
+	toolbar.AddChild(searchButton)
+
+	d.statusLabel = unison.NewLabel()
+	d.statusLabel.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	toolbar.AddChild(d.statusLabel)
+
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	return toolbar
+}
+
+func (d *pdfSearchDockable) setStatus(status string) {
+	d.statusLabel.Text = status
+	d.statusLabel.MarkForLayoutAndRedraw()
+	if parent := d.statusLabel.Parent(); parent != nil {
+		parent.MarkForLayoutAndRedraw()
+	}
+}
+
+func (d *pdfSearchDockable) search() {
+	query := strings.TrimSpace(d.searchField.Text())
+	if query == "" {
+		return
+	}
+	d.query = query
+	d.sequence = pdfSearchSequence.Add(1)
+	d.content.RemoveAllChildren()
+	d.content.MarkForLayoutAndRedraw()
+	d.scroll.MarkForLayoutAndRedraw()
+	refs := model.GlobalSettings().PageRefList()
+	if len(refs) == 0 {
+		d.setStatus(i18n.Text("There are no page reference mappings to search"))
+		return
+	}
+	d.setStatus(fmt.Sprintf(i18n.Text("Searching for \"%s\"…"), query))
+	go d.searchInBackground(d.sequence, query, refs)
+}
+
+func (d *pdfSearchDockable) searchInBackground(sequence int64, query string, refs []*model.PageRef) {
+	total := 0
+	for i, ref := range refs {
+		i, ref := i, ref
+		if pdfSearchSequence.Load() != sequence {
+			return
+		}
+		unison.InvokeTask(func() {
+			if d.sequence == sequence {
+				d.setStatus(fmt.Sprintf(i18n.Text("Searching %s (%d of %d)…"), ref.ID, i+1, len(refs)))
+			}
+		})
+		hits, err := searchPDF(sequence, ref, query)
+		if err != nil {
+			jot.Warn(err)
+			continue
+		}
+		if len(hits) != 0 {
+			total += len(hits)
+			unison.InvokeTask(func() {
+				if d.sequence == sequence {
+					d.addBook(ref, hits)
+				}
+			})
+		}
+	}
+	unison.InvokeTask(func() {
+		if d.sequence == sequence {
+			if total == 0 {
+				d.setStatus(fmt.Sprintf(i18n.Text("No matches found for \"%s\""), query))
+			} else {
+				d.setStatus(fmt.Sprintf(i18n.Text("Found \"%s\" on %d pages"), query, total))
+			}
+		}
+	})
+}
+
+func (d *pdfSearchDockable) addBook(ref *model.PageRef, hits []*pdfSearchHit) {
+	header := unison.NewLabel()
+	header.Text = fmt.Sprintf(i18n.Text("%s: %s"), ref.ID, fs.BaseName(ref.Path))
+	desc := header.Font.Descriptor()
+	desc.Weight = unison.BoldFontWeight
+	header.Font = desc.Font()
+	d.content.AddChild(header)
+	for _, hit := range hits {
+		d.content.AddChild(d.createHitPanel(hit))
+	}
+	d.content.MarkForLayoutAndRedraw()
+	d.scroll.MarkForLayoutAndRedraw()
+}
+
+func (d *pdfSearchDockable) createHitPanel(hit *pdfSearchHit) *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: 2,
+	})
+	panel.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.NewUniformInsets(1),
+		false), unison.NewEmptyBorder(unison.NewUniformInsets(4))))
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	ref := fmt.Sprintf("%s%d", hit.Key, hit.RefPage())
+	label := unison.NewLabel()
+	if hit.Count == 1 {
+		label.Text = fmt.Sprintf(i18n.Text("Page %d (%s): 1 match"), hit.Page+1, ref)
+	} else {
+		label.Text = fmt.Sprintf(i18n.Text("Page %d (%s): %d matches"), hit.Page+1, ref, hit.Count)
+	}
+	panel.AddChild(label)
+	snippets := unison.NewPanel()
+	snippets.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: 2,
+	})
+	panel.AddChild(snippets)
+	sequence := d.sequence
+	query := d.query
+	requested := false
+	panel.DrawCallback = func(_ *unison.Canvas, _ unison.Rect) {
+		// Snippets are only rendered once the hit has been scrolled into view
+		if !requested {
+			requested = true
+			go d.loadSnippets(sequence, hit, query, snippets)
+		}
+	}
+	panel.Tooltip = unison.NewTooltipWithText(i18n.Text("Click to open this page"))
+	panel.UpdateCursorCallback = func(_ unison.Point) *unison.Cursor { return unison.PointingCursor() }
+	panel.MouseDownCallback = func(_ unison.Point, _, _ int, _ unison.Modifiers) bool {
+		OpenPageReference(d.Window(), ref, d.query, nil)
+		return true
+	}
+	return panel
+}
+
+func (d *pdfSearchDockable) loadSnippets(sequence int64, hit *pdfSearchHit, query string, panel *unison.Panel) {
+	if pdfSearchSequence.Load() != sequence {
+		return
+	}
+	entry, err := cachedPDFSearchEntry(hit.Path)
+	if err != nil {
+		jot.Warn(err)
+		return
+	}
+	var doc *pdf.Document
+	if doc, err = entry.document(hit.Path); err != nil {
+		jot.Warn(err)
+		return
+	}
+	crops := createPDFSearchSnippets(doc, hit.Page, query)
+	unison.InvokeTask(func() {
+		if d.sequence != sequence {
+			return
+		}
+		for _, crop := range crops {
+			img, imgErr := unison.NewImageFromPixels(crop.Rect.Dx(), crop.Rect.Dy(), crop.Pix, 72.0/pdfSearchSnippetDPI)
+			if imgErr != nil {
+				continue
+			}
+			snippet := unison.NewLabel()
+			snippet.Drawable = img
+			panel.AddChild(snippet)
+		}
+		d.content.MarkForLayoutAndRedraw()
+		d.scroll.MarkForLayoutAndRedraw()
+	})
+}
+
+// searchPDF searches the PDF referenced by the page reference for the query, returning the pages with matches.
+func searchPDF(sequence int64, ref *model.PageRef, query string) ([]*pdfSearchHit, error) {
+	entry, err := cachedPDFSearchEntry(ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := strings.ToLower(query)
+	if hits, ok := entry.lookup(cacheKey); ok {
+		return adjustPDFSearchHits(hits, ref), nil
+	}
+	var doc *pdf.Document
+	if doc, err = entry.document(ref.Path); err != nil {
+		return nil, err
+	}
+	var hits []*pdfSearchHit
+	pageCount := doc.PageCount()
+	for i := 0; i < pageCount; i++ {
+		if pdfSearchSequence.Load() != sequence {
+			return nil, nil // Search was superseded, so don't cache partial results
+		}
+		var page *pdf.RenderedPage
+		if page, err = doc.RenderPage(i, pdfSearchDetectDPI, pdfSearchMaxHitsPerPage, query); err != nil {
+			continue
+		}
+		if len(page.SearchHits) != 0 {
+			hits = append(hits, &pdfSearchHit{
+				Path:  ref.Path,
+				Key:   ref.ID,
+				Page:  i,
+				Count: len(page.SearchHits),
+			})
+		}
+	}
+	entry.store(cacheKey, hits)
+	return adjustPDFSearchHits(hits, ref), nil
+}
+
+// cachedPDFSearchEntry returns the cache entry for the PDF, replacing it if the file has changed since it was cached.
+func cachedPDFSearchEntry(filePath string) (*pdfSearchCacheEntry, error) {
+	fi, err := os.Stat(filePath)
+	pdfSearchCacheLock.Lock()
+	defer pdfSearchCacheLock.Unlock()
+	if err != nil {
+		delete(pdfSearchCache, filePath)
+		return nil, err
+	}
+	entry, ok := pdfSearchCache[filePath]
+	if !ok || !entry.modTime.Equal(fi.ModTime()) || entry.size != fi.Size() {
+		entry = &pdfSearchCacheEntry{
+			modTime: fi.ModTime(),
+			size:    fi.Size(),
+			results: make(map[string][]*pdfSearchHit),
+		}
+		pdfSearchCache[filePath] = entry
+	}
+	return entry, nil
+}
+
+// document returns the parsed PDF, loading it if it isn't one of the documents currently being kept open.
+func (e *pdfSearchCacheEntry) document(filePath string) (*pdf.Document, error) {
+	pdfSearchCacheLock.Lock()
+	doc := e.doc
+	pdfSearchCacheLock.Unlock()
+	if doc == nil {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = pdf.New(data, 0); err != nil {
+			return nil, err
+		}
+	}
+	pdfSearchCacheLock.Lock()
+	defer pdfSearchCacheLock.Unlock()
+	if e.doc == nil {
+		e.doc = doc
+	} else {
+		doc = e.doc
+	}
+	if i := slices.Index(pdfSearchOpenDocs, e); i != -1 {
+		pdfSearchOpenDocs = slices.Delete(pdfSearchOpenDocs, i, i+1)
+	}
+	pdfSearchOpenDocs = slices.Insert(pdfSearchOpenDocs, 0, e)
+	for len(pdfSearchOpenDocs) > pdfSearchMaxOpenDocs {
+		last := len(pdfSearchOpenDocs) - 1
+		// The document is released by its finalizer once nothing is using it any longer
+		pdfSearchOpenDocs[last].doc = nil
+		pdfSearchOpenDocs[last] = nil
+		pdfSearchOpenDocs = pdfSearchOpenDocs[:last]
+	}
+	return doc, nil
+}
+
+func (e *pdfSearchCacheEntry) lookup(query string) ([]*pdfSearchHit, bool) {
+	pdfSearchCacheLock.Lock()
+	defer pdfSearchCacheLock.Unlock()
+	hits, ok := e.results[query]
+	if ok {
+		e.touch(query)
+	}
+	return hits, ok
+}
+
+func (e *pdfSearchCacheEntry) store(query string, hits []*pdfSearchHit) {
+	pdfSearchCacheLock.Lock()
+	defer pdfSearchCacheLock.Unlock()
+	e.results[query] = hits
+	e.touch(query)
+	for len(e.queries) > pdfSearchMaxCachedQueries {
+		last := len(e.queries) - 1
+		delete(e.results, e.queries[last])
+		e.queries = e.queries[:last]
+	}
+}
+
+// touch moves the query to the front of the list of recent queries. Must be called with the cache lock held.
+func (e *pdfSearchCacheEntry) touch(query string) {
+	if i := slices.Index(e.queries, query); i != -1 {
+		e.queries = slices.Delete(e.queries, i, i+1)
+	}
+	e.queries = slices.Insert(e.queries, 0, query)
+}
+
+// adjustPDFSearchHits returns copies of the hits with the current key and offset of the page reference applied.
+func adjustPDFSearchHits(hits []*pdfSearchHit, ref *model.PageRef) []*pdfSearchHit {
+	list := make([]*pdfSearchHit, len(hits))
+	for i, one := range hits {
+		hit := *one
+		hit.Key = ref.ID
+		hit.Offset = ref.Offset
+		list[i] = &hit
+	}
+	return list
+}
+
+// createPDFSearchSnippets renders the page and crops out the areas around the first few matches. Images are not created
+// here, as that must be done on the UI thread.
+func createPDFSearchSnippets(doc *pdf.Document, pageNumber int, query string) []*image.NRGBA {
+	page, err := doc.RenderPage(pageNumber, pdfSearchSnippetDPI, pdfSearchMaxSnippetsPerPage, query)
+	if err != nil {
+		return nil
+	}
+	bounds := page.Image.Rect
+	snippets := make([]*image.NRGBA, 0, len(page.SearchHits))
+	for _, hit := range page.SearchHits {
+		r := hit
+		lineHeight := r.Dy()
+		r.Min.Y -= lineHeight
+		r.Max.Y += lineHeight
+		if extra := pdfSearchSnippetWidth - r.Dx(); extra > 0 {
+			r.Min.X -= extra / 2
+			r.Max.X += extra - extra/2
+		}
+		if r.Min.X < bounds.Min.X {
+			r = r.Add(image.Pt(bounds.Min.X-r.Min.X, 0))
+		}
+		if r.Max.X > bounds.Max.X {
+			r = r.Add(image.Pt(bounds.Max.X-r.Max.X, 0))
+		}
+		if r = r.Intersect(bounds); r.Empty() {
+			continue
+		}
+		crop := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+		draw.Draw(crop, crop.Rect, page.Image, r.Min, draw.Src)
+		// Highlight the match itself
+		match := hit.Sub(r.Min).Intersect(crop.Rect)
+		for y := match.Min.Y; y < match.Max.Y; y++ {
+			for x := match.Min.X; x < match.Max.X; x++ {
+				i := crop.PixOffset(x, y)
+				crop.Pix[i+2] /= 2 // Reduce blue to tint the match yellow
+			}
+		}
+		snippets = append(snippets, crop)
+	}
+	return snippets
+}
+
+// TitleIcon implements unison.Dockable
+func (d *pdfSearchDockable) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.PDFFile,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable
+func (d *pdfSearchDockable) Title() string {
+	return i18n.Text("Rulebook Search")
+}
+
+func (d *pdfSearchDockable) String() string {
+	return d.Title()
+}
+
+// Tooltip implements unison.Dockable
+func (d *pdfSearchDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable
+func (d *pdfSearchDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *pdfSearchDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *pdfSearchDockable) AttemptClose() bool {
+	pdfSearchSequence.CompareAndSwap(d.sequence, d.sequence+1)
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
@@ -13,6 +13,7 @@ package ux
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -87,7 +88,13 @@ func (p *PointPoolsPanel) rebuild(attrs *model.AttributeDefs) {
 						}
 						return attr.Current()
 					},
-					func(v fxp.Int) { attr.Damage = (attr.Maximum() - v).Max(0) }, fxp.Min, attr.Maximum(), true)
+					func(v fxp.Int) {
+						before := attr.CurrentThreshold()
+						attr.Damage = (attr.Maximum() - v).Max(0)
+						if after := attr.CurrentThreshold(); after != nil && after != before && after.RequiredRoll != "" {
+							unison.InvokeTask(func() { promptForThresholdRoll(attr, after) })
+						}
+					}, fxp.Min, attr.Maximum(), true)
 				p.AddChild(currentField)
 
 				p.AddChild(NewPageLabel(i18n.Text("of")))
@@ -109,9 +116,7 @@ func (p *PointPoolsPanel) rebuild(attrs *model.AttributeDefs) {
 
 				if threshold := attr.CurrentThreshold(); threshold != nil {
 					state := NewPageLabel("[" + threshold.State + "]")
-					if threshold.Explanation != "" {
-						state.Tooltip = unison.NewTooltipWithText(threshold.Explanation)
-					}
+					state.Tooltip = thresholdTooltip(threshold)
 					p.AddChild(state)
 					p.stateLabels[def.ID()] = state
 				} else {
@@ -156,9 +161,7 @@ func (p *PointPoolsPanel) Sync() {
 					if attr, ok := p.entity.Attributes.Set[id]; ok {
 						if threshold := attr.CurrentThreshold(); threshold != nil {
 							label.Text = "[" + threshold.State + "]"
-							if threshold.Explanation != "" {
-								label.Tooltip = unison.NewTooltipWithText(threshold.Explanation)
-							}
+							label.Tooltip = thresholdTooltip(threshold)
 						} else {
 							label.Text = ""
 							label.Tooltip = nil
@@ -170,3 +173,29 @@ func (p *PointPoolsPanel) Sync() {
 		MarkForLayoutWithinDockable(p)
 	}
 }
+
+func thresholdTooltip(threshold *model.PoolThreshold) *unison.Panel {
+	var parts []string
+	if threshold.Explanation != "" {
+		parts = append(parts, threshold.Explanation)
+	}
+	if threshold.RequiredRoll != "" {
+		parts = append(parts, fmt.Sprintf(i18n.Text("Required roll: %s"), threshold.RequiredRoll))
+	}
+	if len(threshold.DisabledTags) != 0 {
+		parts = append(parts, fmt.Sprintf(i18n.Text("Disables traits tagged: %s"), model.CombineTags(threshold.DisabledTags)))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return unison.NewTooltipWithText(strings.Join(parts, "\n"))
+}
+
+func promptForThresholdRoll(attr *model.Attribute, threshold *model.PoolThreshold) {
+	name := attr.AttrID
+	if def := attr.AttributeDef(); def != nil {
+		name = def.Name
+	}
+	unison.WarningDialogWithMessage(fmt.Sprintf(i18n.Text("%s is now %s"), name, threshold.State),
+		fmt.Sprintf(i18n.Text("Required roll: %s"), threshold.RequiredRoll))
+}
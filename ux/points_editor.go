@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -86,7 +87,7 @@ func displayPointsEditor(owner Rebuildable, entity *model.Entity) {
 	e.content = unison.NewPanel()
 	e.content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(unison.StdHSpacing * 2)))
 	e.content.SetLayout(&unison.FlexLayout{
-		Columns:  4,
+		Columns:  5,
 		HSpacing: unison.StdHSpacing,
 		VSpacing: unison.StdVSpacing,
 	})
@@ -121,7 +122,7 @@ func displayPointsEditor(owner Rebuildable, entity *model.Entity) {
 	scroller.Content().AsPanel().ValidateScrollRoot()
 	PlaceInDock(ws, dc, e, EditorGroup)
 	if children := e.content.Children(); len(children) != 0 {
-		children[3].RequestFocus()
+		children[4].RequestFocus()
 	}
 }
 
@@ -213,6 +214,30 @@ func (e *pointsEditor) createRow(rec *model.PointsRecord, index int) {
 		index++
 	}
 
+	var gameDate *StringField
+	gameDateText := i18n.Text("In-Game Date")
+	cal := model.GlobalSettings().CalendarRefFor(e.entity).Calendar
+	gameDate = NewStringField(nil, "", gameDateText,
+		func() string { return rec.GameDate },
+		func(value string) {
+			rec.GameDate = strings.TrimSpace(value)
+			MarkModified(e.content)
+		})
+	gameDate.ValidateCallback = func() bool {
+		text := strings.TrimSpace(gameDate.Text())
+		if text == "" {
+			return true
+		}
+		_, err := cal.ParseDate(text)
+		return err == nil
+	}
+	gameDate.Watermark = gameDateText
+	gameDate.SetMinimumTextWidthUsing(cal.NewDateByDays(0).String())
+	e.content.AddChildAtIndex(gameDate, index)
+	if index != -1 {
+		index++
+	}
+
 	pts := NewDecimalField(nil, "", i18n.Text("Points"),
 		func() fxp.Int { return rec.Points },
 		func(value fxp.Int) {
@@ -236,21 +261,24 @@ func (e *pointsEditor) createRow(rec *model.PointsRecord, index int) {
 }
 
 func (e *pointsEditor) addEntry() {
-	rec := &model.PointsRecord{When: jio.Now()}
+	rec := &model.PointsRecord{
+		When:     jio.Now(),
+		GameDate: model.GlobalSettings().CurrentGameDate(e.entity),
+	}
 	e.current = slices.Insert(e.current, 0, rec)
 	e.createRow(rec, 0)
 	e.content.Pack()
 	e.content.MarkForRedraw()
 	MarkModified(e.content)
-	e.content.Children()[2].RequestFocus()
+	e.content.Children()[3].RequestFocus()
 }
 
 func (e *pointsEditor) removeEntry(rec *model.PointsRecord) {
 	for i, one := range e.current {
 		if one == rec {
 			e.current = slices.Delete(e.current, i, i+1)
-			i *= 4
-			for j := 3; j >= 0; j-- {
+			i *= 5
+			for j := 4; j >= 0; j-- {
 				e.content.RemoveChildAtIndex(i + j)
 			}
 			e.content.Pack()
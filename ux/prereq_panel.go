@@ -115,6 +115,8 @@ func (p *prereqPanel) addToList(parent *unison.Panel, depth, index int, child mo
 		panel = p.createSkillPrereqPanel(depth, one)
 	case *model.SpellPrereq:
 		panel = p.createSpellPrereqPanel(depth, one)
+	case *model.ExpressionPrereq:
+		panel = p.createExpressionPrereqPanel(depth, one)
 	default:
 		jot.Warn(errs.Newf("unknown prerequisite type: %s", reflect.TypeOf(child).String()))
 	}
@@ -276,6 +278,10 @@ func (p *prereqPanel) createPrereqForType(prereqType model.PrereqType, parentLis
 		one := model.NewSpellPrereq()
 		one.Parent = parentList
 		return one
+	case model.ExpressionPrereqType:
+		one := model.NewExpressionPrereq()
+		one.Parent = parentList
+		return one
 	default:
 		jot.Warn(errs.Newf("unknown prerequisite type: %s", prereqType.Key()))
 		return nil
@@ -481,3 +487,58 @@ func (p *prereqPanel) createSpellPrereqPanel(depth int, pr *model.SpellPrereq) *
 	panel.AddChild(second)
 	return panel
 }
+
+func (p *prereqPanel) createExpressionPrereqPanel(depth int, pr *model.ExpressionPrereq) *unison.Panel {
+	panel := unison.NewPanel()
+	p.createButtonsPanel(panel, depth, pr)
+	inFront := andOrText(pr) != noAndOr
+	if inFront {
+		p.addAndOr(panel, pr)
+	}
+	p.addPrereqTypeSwitcher(panel, depth, pr)
+	if !inFront {
+		p.addAndOr(panel, pr)
+	}
+	columns := len(panel.Children())
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  columns,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.AddChild(unison.NewPanel())
+	entity := p.entity
+	if entity == nil {
+		// Without a sheet, check the expression against a scratch character so that its syntax can still be verified
+		entity = model.NewEntity(model.PC)
+	}
+	field := NewStringField(nil, "", i18n.Text("Expression"),
+		func() string { return pr.Expression },
+		func(value string) {
+			pr.Expression = value
+			MarkModified(panel)
+		})
+	field.ValidateCallback = func() bool {
+		satisfied, err := model.EvaluateToBool(field.Text(), entity)
+		var tip string
+		switch {
+		case err != nil:
+			tip = err.Error()
+		case p.entity == nil:
+			tip = i18n.Text("The expression is valid")
+		case satisfied:
+			tip = i18n.Text("The expression is currently satisfied")
+		default:
+			tip = i18n.Text("The expression is not currently satisfied")
+		}
+		field.Tooltip = unison.NewTooltipWithSecondaryText(tip, i18n.Text(`For example: $st + $dx >= 25, trait_level(Magery) >= 3 && tech_level() >= 8, or !has_ancestry(Human)`))
+		return err == nil
+	}
+	field.Validate()
+	field.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  columns - 1,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	panel.AddChild(field)
+	return panel
+}
@@ -0,0 +1,425 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	xfs "github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+)
+
+const randomTableHistoryLimit = 100
+
+var (
+	_ FileBackedDockable = &RandomTableDockable{}
+	_ unison.TabCloser   = &RandomTableDockable{}
+	_ ModifiableRoot     = &RandomTableDockable{}
+)
+
+// RandomTableDockable holds the view for a random table file.
+type RandomTableDockable struct {
+	unison.Panel
+	path              string
+	table             *model.RandomTable
+	originalCRC       uint64
+	undoMgr           *unison.UndoManager
+	targetMgr         *TargetMgr
+	scroller          *unison.ScrollPanel
+	content           *unison.Panel
+	view              *unison.Markdown
+	historyView       *unison.Markdown
+	editToggle        *unison.Button
+	rangeFields       []*NonEditableField
+	history           []*randomTableRoll
+	modifier          int
+	needsSaveAsPrompt bool
+}
+
+type randomTableRoll struct {
+	when     time.Time
+	modifier int
+	result   *model.RandomTableResult
+}
+
+// NewRandomTableDockable creates a new unison.Dockable for random table files.
+func NewRandomTableDockable(filePath string) (unison.Dockable, error) {
+	table, err := model.NewRandomTableFromFile(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath))
+	if err != nil {
+		return nil, err
+	}
+	return newRandomTableDockable(filePath, table, false), nil
+}
+
+// NewUntitledRandomTableDockable creates a new unison.Dockable for a random table that hasn't been saved yet.
+func NewUntitledRandomTableDockable(title string, table *model.RandomTable, startInEditMode bool) unison.Dockable {
+	d := newRandomTableDockable(markdownContentOnlyPrefix+title+model.RandomTableExt, table, startInEditMode)
+	d.needsSaveAsPrompt = true
+	return d
+}
+
+// ShowBuiltInRandomTable opens a copy of one of the built-in random tables.
+func ShowBuiltInRandomTable(ref *model.NamedFileRef) {
+	ws := WorkspaceFromWindowOrAny(nil)
+	if d := ws.LocateFileBackedDockable(markdownContentOnlyPrefix + ref.Name + model.RandomTableExt); d != nil {
+		dc := unison.Ancestor[*unison.DockContainer](d)
+		dc.SetCurrentDockable(d)
+		dc.AcquireFocus()
+		return
+	}
+	table, err := model.NewRandomTableFromFile(ref.FileSystem, ref.FilePath)
+	if err != nil {
+		unison.ErrorDialogWithError(fmt.Sprintf(i18n.Text("Unable to open %s"), ref.Name), err)
+		return
+	}
+	DisplayNewDockable(nil, NewUntitledRandomTableDockable(ref.Name, table, false))
+}
+
+func newRandomTableDockable(filePath string, table *model.RandomTable, startInEditMode bool) *RandomTableDockable {
+	d := &RandomTableDockable{
+		path:    filePath,
+		table:   table,
+		undoMgr: unison.NewUndoManager(100, func(err error) { jot.Error(err) }),
+	}
+	d.Self = d
+	d.targetMgr = NewTargetMgr(d)
+	d.table.ResetTargetKeyPrefixes(d.targetMgr.NextPrefix)
+	d.originalCRC = d.table.CRC64()
+	d.SetLayout(&unison.FlexLayout{Columns: 1})
+
+	d.view = unison.NewMarkdown(true)
+	d.historyView = unison.NewMarkdown(true)
+	d.historyView.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+
+	d.content = unison.NewPanel()
+	d.content.SetLayout(&unison.FlexLayout{Columns: 1})
+	d.content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(20)))
+	d.scroller = unison.NewScrollPanel()
+	d.scroller.SetContent(d.content, unison.FillBehavior, unison.FillBehavior)
+	d.scroller.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		VAlign: unison.FillAlignment,
+		HGrab:  true,
+		VGrab:  true,
+	})
+
+	d.AddChild(d.createToolbar(startInEditMode))
+	d.AddChild(d.scroller)
+	d.rebuildContent()
+
+	d.InstallCmdHandlers(SaveItemID, func(_ any) bool { return d.Modified() }, func(_ any) { d.save(false) })
+	d.InstallCmdHandlers(SaveAsItemID, unison.AlwaysEnabled, func(_ any) { d.save(true) })
+	return d
+}
+
+func (d *RandomTableDockable) createToolbar(startInEditMode bool) *unison.Panel {
+	toolbar := unison.NewPanel()
+	toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.Insets{Bottom: 1},
+		false), unison.NewEmptyBorder(unison.StdInsets())))
+	toolbar.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+
+	d.editToggle = unison.NewSVGButton(svg.Edit)
+	d.editToggle.Sticky = startInEditMode
+	d.editToggle.Tooltip = unison.NewTooltipWithText(i18n.Text("Toggle Edit Mode"))
+	d.editToggle.ClickCallback = func() {
+		d.Window().FocusNext() // Intentionally move the focus to ensure any pending edits are flushed
+		d.editToggle.Sticky = !d.editToggle.Sticky
+		d.editToggle.MarkForRedraw()
+		d.rebuildContent()
+	}
+	toolbar.AddChild(d.editToggle)
+
+	label := i18n.Text("Modifier")
+	toolbar.AddChild(NewFieldLeadingLabel(label))
+	modifierField := NewIntegerField(nil, "", label,
+		func() int { return d.modifier },
+		func(v int) { d.modifier = v },
+		-999, 999, true, false)
+	modifierField.Tooltip = unison.NewTooltipWithText(i18n.Text("The amount to add to the roll, such as the margin of failure for a Fright Check"))
+	toolbar.AddChild(modifierField)
+
+	rollButton := unison.NewButton()
+	rollButton.Text = i18n.Text("Roll")
+	rollButton.ClickCallback = d.roll
+	toolbar.AddChild(rollButton)
+
+	clearButton := unison.NewSVGButton(svg.Trash)
+	clearButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Clear History"))
+	clearButton.ClickCallback = func() {
+		d.history = nil
+		d.updateHistory()
+	}
+	toolbar.AddChild(clearButton)
+
+	toolbar.SetLayout(&unison.FlexLayout{
+		Columns:  len(toolbar.Children()),
+		HSpacing: unison.StdHSpacing,
+	})
+	return toolbar
+}
+
+func (d *RandomTableDockable) roll() {
+	d.history = append([]*randomTableRoll{{
+		when:     time.Now(),
+		modifier: d.modifier,
+		result:   d.table.RollWithModifier(d.modifier),
+	}}, d.history...)
+	if len(d.history) > randomTableHistoryLimit {
+		d.history = d.history[:randomTableHistoryLimit]
+	}
+	d.updateHistory()
+}
+
+func (d *RandomTableDockable) rebuildContent() {
+	d.content.RemoveAllChildren()
+	d.rangeFields = nil
+	if d.editToggle.Sticky {
+		d.content.AddChild(newRandomTablePanel(d, d.table))
+	} else {
+		d.view.SetContent(randomTableMarkdown(d.table), 0)
+		d.content.AddChild(d.view)
+	}
+	d.content.AddChild(d.historyView)
+	d.updateHistory()
+	d.MarkForLayoutRecursively()
+	d.MarkForRedraw()
+}
+
+func (d *RandomTableDockable) updateHistory() {
+	var buffer strings.Builder
+	if len(d.history) != 0 {
+		fmt.Fprintf(&buffer, "### %s\n\n", i18n.Text("History"))
+		for _, one := range d.history {
+			fmt.Fprintf(&buffer, "- %s — **%s**", one.when.Format("15:04:05"), one.result.Totals())
+			if one.modifier != 0 {
+				fmt.Fprintf(&buffer, " (%s%+d)", d.table.Roll.String(), one.modifier)
+			}
+			fmt.Fprintf(&buffer, ": %s", escapeMarkdownTableText(one.result.Text()))
+			if value := one.result.Value(); value != 0 {
+				fmt.Fprintf(&buffer, " [%s]", value.String())
+			}
+			buffer.WriteByte('\n')
+		}
+	}
+	d.historyView.SetContent(buffer.String(), 0)
+	d.historyView.MarkForLayoutAndRedraw()
+	d.content.MarkForLayoutAndRedraw()
+}
+
+// randomTableMarkdown returns the markdown used to display the table.
+func randomTableMarkdown(table *model.RandomTable) string {
+	var buffer strings.Builder
+	if table.Name != "" {
+		fmt.Fprintf(&buffer, "# %s\n\n", escapeMarkdownTableText(table.Name))
+	}
+	writeRandomTableMarkdown(&buffer, table, 0)
+	return buffer.String()
+}
+
+func writeRandomTableMarkdown(buffer *strings.Builder, table *model.RandomTable, depth int) {
+	fmt.Fprintf(buffer, "**%s** %s\n\n", i18n.Text("Roll:"), table.Roll.String())
+	fmt.Fprintf(buffer, "| %s | %s |\n|:-:|---|\n", i18n.Text("Roll"), i18n.Text("Result"))
+	for _, one := range table.Entries {
+		if one.Slots > 0 {
+			fmt.Fprintf(buffer, "| %s | %s |\n", one.RollRange, escapeMarkdownTableText(randomTableEntryText(one)))
+		}
+	}
+	buffer.WriteByte('\n')
+	for _, one := range table.Entries {
+		if one.Slots > 0 && one.SubTable != nil {
+			heading := fmt.Sprintf(i18n.Text("Sub-table for %s"), one.RollRange)
+			if one.Modifier != 0 {
+				heading += fmt.Sprintf(i18n.Text(" (roll at %+d)"), one.Modifier)
+			}
+			level := depth + 3
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(buffer, "%s %s\n\n", strings.Repeat("#", level), heading)
+			writeRandomTableMarkdown(buffer, one.SubTable, depth+1)
+		}
+	}
+}
+
+func randomTableEntryText(entry *model.RandomTableEntry) string {
+	text := entry.Text
+	if entry.Value != 0 {
+		text += fmt.Sprintf(" [%s]", entry.Value.String())
+	}
+	return text
+}
+
+func escapeMarkdownTableText(text string) string {
+	return strings.NewReplacer("\r", "", "\n", " ", "|", `\|`, "*", `\*`, "_", `\_`, "#", `\#`).Replace(text)
+}
+
+// UndoManager implements undo.Provider
+func (d *RandomTableDockable) UndoManager() *unison.UndoManager {
+	return d.undoMgr
+}
+
+// TitleIcon implements workspace.FileBackedDockable
+func (d *RandomTableDockable) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Randomize,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements workspace.FileBackedDockable
+func (d *RandomTableDockable) Title() string {
+	return xfs.BaseName(d.path)
+}
+
+// Tooltip implements workspace.FileBackedDockable
+func (d *RandomTableDockable) Tooltip() string {
+	if d.needsSaveAsPrompt {
+		return ""
+	}
+	return d.BackingFilePath()
+}
+
+// BackingFilePath implements workspace.FileBackedDockable
+func (d *RandomTableDockable) BackingFilePath() string {
+	return d.path
+}
+
+// SetBackingFilePath implements workspace.FileBackedDockable
+func (d *RandomTableDockable) SetBackingFilePath(p string) {
+	d.path = p
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.UpdateTitle(d)
+	}
+}
+
+// Modified implements workspace.FileBackedDockable
+func (d *RandomTableDockable) Modified() bool {
+	return d.originalCRC != d.table.CRC64()
+}
+
+// MarkModified implements ModifiableRoot.
+func (d *RandomTableDockable) MarkModified(_ unison.Paneler) {
+	d.table.Update()
+	for _, one := range d.rangeFields {
+		one.Sync()
+	}
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.UpdateTitle(d)
+	}
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *RandomTableDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *RandomTableDockable) AttemptClose() bool {
+	if d.Modified() {
+		switch unison.YesNoCancelDialog(fmt.Sprintf(i18n.Text("Save changes made to\n%s?"), d.Title()), "") {
+		case unison.ModalResponseDiscard:
+		case unison.ModalResponseOK:
+			if !d.save(false) {
+				return false
+			}
+		case unison.ModalResponseCancel:
+			return false
+		}
+	}
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
+
+func (d *RandomTableDockable) save(forceSaveAs bool) bool {
+	d.Window().FocusNext() // Intentionally move the focus to ensure any pending edits are flushed
+	success := false
+	if forceSaveAs || d.needsSaveAsPrompt {
+		success = SaveDockableAs(d, model.RandomTableExt[1:], d.table.Save, func(path string) {
+			d.path = path
+			d.originalCRC = d.table.CRC64()
+		})
+	} else {
+		success = SaveDockable(d, d.table.Save, func() { d.originalCRC = d.table.CRC64() })
+	}
+	if success {
+		d.needsSaveAsPrompt = false
+		model.RefreshRandomTableLookups(model.GlobalSettings().Libraries())
+	}
+	return success
+}
+
+func (d *RandomTableDockable) untitled() bool {
+	return d.needsSaveAsPrompt
+}
+
+func (d *RandomTableDockable) snapshot(w io.Writer) error {
+	return d.table.SaveTo(w)
+}
+
+func (d *RandomTableDockable) adoptRecoveredSnapshot(info *model.RecoveryInfo) {
+	d.path = info.OriginalPath
+	d.needsSaveAsPrompt = info.Untitled
+	d.originalCRC = 0
+	if !info.Untitled {
+		if table, err := model.NewRandomTableFromFile(os.DirFS(filepath.Dir(d.path)), filepath.Base(d.path)); err == nil {
+			d.originalCRC = table.CRC64()
+		}
+	}
+}
+
+func (d *RandomTableDockable) prepareUndo(title string) *unison.UndoEdit[*model.RandomTable] {
+	return &unison.UndoEdit[*model.RandomTable]{
+		ID:         unison.NextUndoID(),
+		EditName:   title,
+		UndoFunc:   func(e *unison.UndoEdit[*model.RandomTable]) { d.applyTable(e.BeforeData) },
+		RedoFunc:   func(e *unison.UndoEdit[*model.RandomTable]) { d.applyTable(e.AfterData) },
+		AbsorbFunc: func(e *unison.UndoEdit[*model.RandomTable], other unison.Undoable) bool { return false },
+		BeforeData: d.table.Clone(nil),
+	}
+}
+
+func (d *RandomTableDockable) finishAndPostUndo(undo *unison.UndoEdit[*model.RandomTable]) {
+	undo.AfterData = d.table.Clone(nil)
+	d.UndoManager().Add(undo)
+}
+
+func (d *RandomTableDockable) applyTable(table *model.RandomTable) {
+	d.table = table.Clone(nil)
+	d.sync()
+}
+
+func (d *RandomTableDockable) sync() {
+	focusRefKey := d.targetMgr.CurrentFocusRef()
+	h, v := d.scroller.Position()
+	d.rebuildContent()
+	d.ValidateLayout()
+	d.MarkModified(nil)
+	d.targetMgr.ReacquireFocus(focusRefKey, nil, d.content)
+	d.scroller.SetPosition(h, v)
+}
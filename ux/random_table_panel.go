@@ -0,0 +1,242 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/rpgtools/dice"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+// newRandomTablePanel creates the editor for a table. Sub-tables are edited by nesting further instances.
+func newRandomTablePanel(d *RandomTableDockable, table *model.RandomTable) *unison.Panel {
+	p := unison.NewPanel()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+
+	if table.OwningEntry() == nil {
+		text := i18n.Text("Name")
+		p.AddChild(NewFieldLeadingLabel(text))
+		field := NewStringField(d.targetMgr, table.KeyPrefix+"name", text,
+			func() string { return table.Name },
+			func(s string) { table.Name = s })
+		field.SetMinimumTextWidthUsing(prototypeMinNameWidth)
+		field.Tooltip = unison.NewTooltipWithText(i18n.Text("The name of this table"))
+		p.AddChild(field)
+	}
+
+	text := i18n.Text("Roll")
+	p.AddChild(NewFieldLeadingLabel(text))
+	field := NewStringField(d.targetMgr, table.KeyPrefix+"roll", text,
+		func() string { return table.Roll.String() },
+		func(s string) { table.Roll = dice.New(s) })
+	field.SetMinimumTextWidthUsing("100d1000")
+	field.Tooltip = unison.NewTooltipWithText(i18n.Text("The dice to roll on the table"))
+	p.AddChild(field)
+
+	wrapper := unison.NewPanel()
+	wrapper.SetBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.NewUniformInsets(1), false))
+	wrapper.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  2,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	wrapper.SetLayout(&unison.FlexLayout{Columns: 1})
+	for _, entry := range table.Entries {
+		wrapper.AddChild(newRandomTableEntryPanel(d, entry))
+	}
+	p.AddChild(wrapper)
+
+	addButton := unison.NewSVGButton(svg.CircledAdd)
+	addButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Add entry"))
+	addButton.ClickCallback = func() {
+		undo := d.prepareUndo(i18n.Text("Add Entry"))
+		entry := &model.RandomTableEntry{
+			Slots:     1,
+			KeyPrefix: d.targetMgr.NextPrefix(),
+		}
+		table.AddEntry(entry)
+		d.finishAndPostUndo(undo)
+		d.sync()
+		if focus := d.targetMgr.Find(entry.KeyPrefix + "text"); focus != nil {
+			focus.RequestFocus()
+		}
+	}
+	p.AddChild(addButton)
+	return p
+}
+
+func newRandomTableEntryPanel(d *RandomTableDockable, entry *model.RandomTableEntry) *unison.Panel {
+	p := unison.NewPanel()
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	p.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	p.SetBorder(unison.NewEmptyBorder(unison.StdInsets()))
+	p.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+		color := unison.ContentColor
+		if p.Parent().IndexOfChild(p)%2 == 1 {
+			color = unison.BandingColor
+		}
+		gc.DrawRect(rect, color.Paint(gc, rect, unison.Fill))
+	}
+
+	buttons := unison.NewPanel()
+	buttons.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	buttons.SetLayoutData(&unison.FlexLayoutData{HAlign: unison.MiddleAlignment})
+	deleteButton := unison.NewSVGButton(svg.Trash)
+	deleteButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove entry"))
+	deleteButton.SetEnabled(len(entry.OwningTable().Entries) > 1)
+	deleteButton.ClickCallback = func() {
+		undo := d.prepareUndo(i18n.Text("Remove Entry"))
+		entry.OwningTable().RemoveEntry(entry)
+		d.finishAndPostUndo(undo)
+		d.sync()
+	}
+	buttons.AddChild(deleteButton)
+	var subTableButton *unison.Button
+	if entry.SubTable == nil {
+		subTableButton = unison.NewSVGButton(svg.CircledAdd)
+		subTableButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Add sub-table"))
+	} else {
+		subTableButton = unison.NewSVGButton(svg.Not)
+		subTableButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove sub-table"))
+	}
+	subTableButton.ClickCallback = func() { toggleRandomTableSubTable(d, entry) }
+	buttons.AddChild(subTableButton)
+	p.AddChild(buttons)
+
+	content := unison.NewPanel()
+	content.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	content.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	p.AddChild(content)
+
+	content.AddChild(NewFieldLeadingLabel(i18n.Text("Range")))
+	rangeField := NewNonEditableField(func(f *NonEditableField) { f.Text = entry.RollRange })
+	rangeField.Tooltip = unison.NewTooltipWithText(i18n.Text("The roll results that select this entry"))
+	d.rangeFields = append(d.rangeFields, rangeField)
+	content.AddChild(rangeField)
+
+	text := i18n.Text("Slots")
+	content.AddChild(NewFieldInteriorLeadingLabel(text))
+	slotsField := NewIntegerField(d.targetMgr, entry.KeyPrefix+"slots", text,
+		func() int { return entry.Slots },
+		func(v int) { entry.Slots = v },
+		0, 999999, false, false)
+	slotsField.Tooltip = unison.NewTooltipWithText(i18n.Text("The number of consecutive roll results this entry fills in the table"))
+	content.AddChild(slotsField)
+
+	text = i18n.Text("Text")
+	content.AddChild(NewFieldLeadingLabel(text))
+	textField := NewMultiLineStringField(d.targetMgr, entry.KeyPrefix+"text", text,
+		func() string { return entry.Text },
+		func(s string) { entry.Text = s })
+	textField.SetMinimumTextWidthUsing(prototypeMinNameWidth)
+	textField.Tooltip = unison.NewTooltipWithText(i18n.Text("The result to report when this entry is rolled"))
+	textField.SetLayoutData(&unison.FlexLayoutData{
+		HSpan:  3,
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	content.AddChild(textField)
+
+	text = i18n.Text("Value")
+	content.AddChild(NewFieldLeadingLabel(text))
+	valueField := NewDecimalField(d.targetMgr, entry.KeyPrefix+"value", text,
+		func() fxp.Int { return entry.Value },
+		func(v fxp.Int) { entry.Value = v },
+		fxp.Min, fxp.Max, false, false)
+	valueField.Tooltip = unison.NewTooltipWithText(i18n.Text("An optional numeric result, returned when the table is rolled by the random_table() function"))
+	content.AddChild(valueField)
+
+	if entry.SubTable != nil {
+		text = i18n.Text("Sub-Roll Modifier")
+		content.AddChild(NewFieldInteriorLeadingLabel(text))
+		modifierField := NewIntegerField(d.targetMgr, entry.KeyPrefix+"modifier", text,
+			func() int { return entry.Modifier },
+			func(v int) { entry.Modifier = v },
+			-999, 999, true, false)
+		modifierField.Tooltip = unison.NewTooltipWithText(i18n.Text("The amount to add to the roll on the sub-table"))
+		content.AddChild(modifierField)
+
+		sub := newRandomTablePanel(d, entry.SubTable)
+		sub.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0,
+			unison.Insets{Left: 1}, false), unison.NewEmptyBorder(unison.Insets{Left: unison.StdHSpacing})))
+		sub.SetLayoutData(&unison.FlexLayoutData{
+			HSpan:  4,
+			HAlign: unison.FillAlignment,
+			HGrab:  true,
+		})
+		content.AddChild(sub)
+	} else {
+		spacer := unison.NewPanel()
+		spacer.SetLayoutData(&unison.FlexLayoutData{HSpan: 2})
+		content.AddChild(spacer)
+	}
+	return p
+}
+
+func toggleRandomTableSubTable(d *RandomTableDockable, entry *model.RandomTableEntry) {
+	var focusKey string
+	if entry.SubTable == nil {
+		undo := d.prepareUndo(i18n.Text("Add Sub-Table"))
+		entry.SubTable = &model.RandomTable{
+			Roll:      dice.New("1d"),
+			KeyPrefix: d.targetMgr.NextPrefix(),
+		}
+		entry.SubTable.AddEntry(&model.RandomTableEntry{
+			Slots:     6,
+			KeyPrefix: d.targetMgr.NextPrefix(),
+		})
+		focusKey = entry.SubTable.Entries[0].KeyPrefix + "text"
+		d.finishAndPostUndo(undo)
+	} else {
+		undo := d.prepareUndo(i18n.Text("Remove Sub-Table"))
+		entry.SubTable = nil
+		entry.Modifier = 0
+		d.finishAndPostUndo(undo)
+	}
+	d.table.Update()
+	d.sync()
+	if focusKey != "" {
+		if focus := d.targetMgr.Find(focusKey); focus != nil {
+			focus.RequestFocus()
+		}
+	}
+}
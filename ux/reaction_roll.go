@@ -0,0 +1,495 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+	"golang.org/x/exp/slices"
+)
+
+const maxReactionHistory = 50
+
+var (
+	_ unison.Dockable  = &reactionRollDockable{}
+	_ unison.TabCloser = &reactionRollDockable{}
+)
+
+// reactionModifier holds a modifier that may be applied to a reaction roll.
+type reactionModifier struct {
+	From    string
+	Amount  int
+	Enabled bool
+}
+
+type reactionRollHistory struct {
+	subject string
+	roll    *model.ReactionRoll
+}
+
+type reactionRollDockable struct {
+	unison.Panel
+	characterPopup *unison.PopupMenu[*Sheet]
+	npcPopup       *unison.PopupMenu[*Sheet]
+	modifiers      *unison.Panel
+	adHocPanel     *unison.Panel
+	totalLabel     *unison.Label
+	resultLabel    *unison.Label
+	descLabel      *unison.Label
+	logButton      *unison.Button
+	history        *unison.Panel
+	character      *Sheet
+	npc            *Sheet
+	situation      model.ReactionSituation
+	sheetMods      []*reactionModifier
+	adHocMods      []*reactionModifier
+	last           *reactionRollHistory
+	past           []*reactionRollHistory
+}
+
+// ShowReactionRoll shows the reaction roll tool.
+func ShowReactionRoll() {
+	ws, dc, found := Activate(func(d unison.Dockable) bool {
+		_, ok := d.(*reactionRollDockable)
+		return ok
+	})
+	if !found && ws != nil {
+		d := &reactionRollDockable{character: ActiveSheet()}
+		d.Self = d
+		d.SetLayout(&unison.FlexLayout{Columns: 1})
+		content := unison.NewPanel()
+		content.SetBorder(unison.NewEmptyBorder(unison.NewUniformInsets(unison.StdHSpacing * 2)))
+		content.SetLayout(&unison.FlexLayout{
+			Columns:  1,
+			VSpacing: unison.StdVSpacing * 2,
+		})
+		content.AddChild(d.createSubjectBlock())
+		d.modifiers = createClockSection(content, i18n.Text("Reaction Modifiers From Sheet"))
+		d.adHocPanel = createClockSection(content, i18n.Text("Additional Modifiers"))
+		content.AddChild(d.createRollBlock())
+		content.AddChild(d.createResultBlock())
+		d.history = createClockSection(content, i18n.Text("History"))
+		scroll := unison.NewScrollPanel()
+		scroll.SetContent(content, unison.HintedFillBehavior, unison.FillBehavior)
+		scroll.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			VAlign: unison.FillAlignment,
+			HGrab:  true,
+			VGrab:  true,
+		})
+		d.AddChild(scroll)
+		d.syncSheets()
+		d.syncAdHoc()
+		d.syncResult()
+		d.syncHistory()
+		PlaceInDock(ws, dc, d, EditorGroup)
+	}
+}
+
+// UpdateReactionRoll refreshes the reaction roll tool, if it is being shown, to reflect changes made to sheets.
+func UpdateReactionRoll() {
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, one := range dc.Dockables() {
+					if d, ok := one.(*reactionRollDockable); ok {
+						d.syncSheets()
+						return true
+					}
+				}
+				return false
+			})
+		}
+	}
+}
+
+func (d *reactionRollDockable) createSubjectBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	text := i18n.Text("Character")
+	panel.AddChild(NewFieldLeadingLabel(text))
+	d.characterPopup = unison.NewPopupMenu[*Sheet]()
+	d.characterPopup.Tooltip = unison.NewTooltipWithText(i18n.Text("The character the reaction is to"))
+	d.characterPopup.SelectionChangedCallback = func(p *unison.PopupMenu[*Sheet]) {
+		if sheet, ok := p.Selected(); ok && sheet != d.character {
+			d.character = sheet
+			d.syncSheets()
+		}
+	}
+	panel.AddChild(d.characterPopup)
+	text = i18n.Text("Situation")
+	panel.AddChild(NewFieldLeadingLabel(text))
+	popup := unison.NewPopupMenu[model.ReactionSituation]()
+	for _, one := range model.AllReactionSituation {
+		popup.AddItem(one)
+	}
+	popup.Select(d.situation)
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[model.ReactionSituation]) {
+		if situation, ok := p.Selected(); ok {
+			d.situation = situation
+		}
+	}
+	panel.AddChild(popup)
+	return panel
+}
+
+func (d *reactionRollDockable) createRollBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	d.totalLabel = NewFieldTrailingLabel("")
+	panel.AddChild(d.totalLabel)
+	rollButton := unison.NewButton()
+	rollButton.Text = i18n.Text("Roll 3d")
+	rollButton.ClickCallback = d.roll
+	panel.AddChild(rollButton)
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  len(panel.Children()),
+		HSpacing: unison.StdHSpacing * 2,
+		VAlign:   unison.MiddleAlignment,
+	})
+	return panel
+}
+
+func (d *reactionRollDockable) createResultBlock() *unison.Panel {
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	panel.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	d.resultLabel = unison.NewLabel()
+	d.resultLabel.Font = &unison.DynamicFont{
+		Resolver: func() unison.FontDescriptor {
+			fd := unison.SystemFont.Descriptor()
+			fd.Size *= 1.5
+			return fd
+		},
+	}
+	panel.AddChild(d.resultLabel)
+	d.descLabel = NewFieldTrailingLabel("")
+	panel.AddChild(d.descLabel)
+
+	logPanel := unison.NewPanel()
+	logPanel.AddChild(NewFieldLeadingLabel(i18n.Text("NPC")))
+	d.npcPopup = unison.NewPopupMenu[*Sheet]()
+	d.npcPopup.Tooltip = unison.NewTooltipWithText(i18n.Text("The sheet whose notes the result should be logged to"))
+	d.npcPopup.SelectionChangedCallback = func(p *unison.PopupMenu[*Sheet]) {
+		if sheet, ok := p.Selected(); ok {
+			d.npc = sheet
+		}
+	}
+	logPanel.AddChild(d.npcPopup)
+	d.logButton = unison.NewButton()
+	d.logButton.Text = i18n.Text("Log to NPC Notes")
+	d.logButton.ClickCallback = d.logToNPC
+	logPanel.AddChild(d.logButton)
+	logPanel.SetLayout(&unison.FlexLayout{
+		Columns:  len(logPanel.Children()),
+		HSpacing: unison.StdHSpacing,
+		VAlign:   unison.MiddleAlignment,
+	})
+	panel.AddChild(logPanel)
+	return panel
+}
+
+func (d *reactionRollDockable) modifier() int {
+	total := 0
+	for _, list := range [][]*reactionModifier{d.sheetMods, d.adHocMods} {
+		for _, one := range list {
+			if one.Enabled {
+				total += one.Amount
+			}
+		}
+	}
+	return total
+}
+
+func (d *reactionRollDockable) subject() string {
+	if d.character != nil {
+		if name := strings.TrimSpace(d.character.entity.Profile.Name); name != "" {
+			return name
+		}
+		return d.character.Title()
+	}
+	return i18n.Text("Unknown")
+}
+
+func (d *reactionRollDockable) roll() {
+	if d.last != nil {
+		d.past = append(d.past, d.last)
+		if len(d.past) > maxReactionHistory {
+			d.past = d.past[len(d.past)-maxReactionHistory:]
+		}
+	}
+	d.last = &reactionRollHistory{
+		subject: d.subject(),
+		roll:    model.RollReaction(d.situation, d.modifier()),
+	}
+	d.syncResult()
+	d.syncHistory()
+}
+
+func (d *reactionRollDockable) logToNPC() {
+	if d.last == nil || d.npc == nil {
+		return
+	}
+	npc := d.npc
+	entity := npc.entity
+	note := model.NewNote(entity, nil, false)
+	note.Text = d.last.roll.NoteText(d.last.subject, model.GlobalSettings().CurrentGameDate(entity))
+	before := append([]*model.Note(nil), entity.Notes...)
+	after := append(append([]*model.Note(nil), entity.Notes...), note)
+	apply := func(list []*model.Note) {
+		entity.SetNoteList(append([]*model.Note(nil), list...))
+		npc.Rebuild(true)
+		npc.MarkModified(nil)
+	}
+	if mgr := unison.UndoManagerFor(npc); mgr != nil {
+		mgr.Add(&unison.UndoEdit[[]*model.Note]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Log Reaction Roll"),
+			UndoFunc:   func(edit *unison.UndoEdit[[]*model.Note]) { apply(edit.BeforeData) },
+			RedoFunc:   func(edit *unison.UndoEdit[[]*model.Note]) { apply(edit.AfterData) },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	apply(after)
+}
+
+// syncSheets rebuilds the sheet choices and the list of reaction modifiers provided by the selected character.
+func (d *reactionRollDockable) syncSheets() {
+	sheets := OpenSheets(nil)
+	if !slices.Contains(sheets, d.character) {
+		d.character = nil
+		if len(sheets) != 0 {
+			d.character = sheets[0]
+		}
+	}
+	if !slices.Contains(sheets, d.npc) {
+		d.npc = nil
+		for _, one := range sheets {
+			if one != d.character {
+				d.npc = one
+				break
+			}
+		}
+	}
+	fillSheetPopup(d.characterPopup, sheets, d.character)
+	fillSheetPopup(d.npcPopup, sheets, d.npc)
+
+	// Preserve the checked state of modifiers that are still present
+	enabled := make(map[string]bool, len(d.sheetMods))
+	for _, one := range d.sheetMods {
+		enabled[one.From] = one.Enabled
+	}
+	d.sheetMods = nil
+	if d.character != nil {
+		for _, one := range d.character.entity.Reactions() {
+			d.sheetMods = append(d.sheetMods, &reactionModifier{
+				From:    one.From,
+				Amount:  fxp.As[int](one.Total()),
+				Enabled: enabled[one.From],
+			})
+		}
+	}
+	d.modifiers.RemoveAllChildren()
+	d.modifiers.SetLayout(&unison.FlexLayout{
+		Columns:  1,
+		VSpacing: unison.StdVSpacing,
+	})
+	switch {
+	case d.character == nil:
+		d.modifiers.AddChild(NewFieldTrailingLabel(i18n.Text("Open a character sheet to use its reaction modifiers.")))
+	case len(d.sheetMods) == 0:
+		d.modifiers.AddChild(NewFieldTrailingLabel(i18n.Text("This character has no reaction modifiers.")))
+	default:
+		for _, one := range d.sheetMods {
+			d.modifiers.AddChild(d.newModifierCheckBox(one))
+		}
+	}
+	d.syncTotal()
+	d.MarkForLayoutRecursively()
+	d.MarkForRedraw()
+}
+
+func fillSheetPopup(popup *unison.PopupMenu[*Sheet], sheets []*Sheet, selected *Sheet) {
+	callback := popup.SelectionChangedCallback
+	popup.SelectionChangedCallback = nil
+	popup.RemoveAllItems()
+	for _, one := range sheets {
+		popup.AddItem(one)
+	}
+	if selected != nil {
+		popup.Select(selected)
+	}
+	popup.SetEnabled(len(sheets) != 0)
+	popup.SelectionChangedCallback = callback
+}
+
+func (d *reactionRollDockable) newModifierCheckBox(mod *reactionModifier) *CheckBox {
+	return NewCheckBox(nil, "", fmt.Sprintf("%+d %s", mod.Amount, mod.From),
+		func() unison.CheckState { return unison.CheckStateFromBool(mod.Enabled) },
+		func(state unison.CheckState) {
+			mod.Enabled = state == unison.OnCheckState
+			d.syncTotal()
+		})
+}
+
+func (d *reactionRollDockable) syncAdHoc() {
+	d.adHocPanel.RemoveAllChildren()
+	d.adHocPanel.SetLayout(&unison.FlexLayout{
+		Columns:  4,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	for _, one := range d.adHocMods {
+		mod := one
+		deleteButton := unison.NewSVGButton(svg.Trash)
+		deleteButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove modifier"))
+		deleteButton.ClickCallback = func() {
+			if i := slices.Index(d.adHocMods, mod); i != -1 {
+				d.adHocMods = slices.Delete(d.adHocMods, i, i+1)
+				d.syncAdHoc()
+			}
+		}
+		d.adHocPanel.AddChild(deleteButton)
+		d.adHocPanel.AddChild(NewCheckBox(nil, "", "",
+			func() unison.CheckState { return unison.CheckStateFromBool(mod.Enabled) },
+			func(state unison.CheckState) {
+				mod.Enabled = state == unison.OnCheckState
+				d.syncTotal()
+			}))
+		d.adHocPanel.AddChild(NewIntegerField(nil, "", i18n.Text("Modifier"),
+			func() int { return mod.Amount },
+			func(value int) {
+				mod.Amount = value
+				d.syncTotal()
+			},
+			-99, 99, true, false))
+		field := NewStringField(nil, "", i18n.Text("Description"),
+			func() string { return mod.From },
+			func(value string) { mod.From = value })
+		field.Watermark = i18n.Text("Description")
+		field.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			HGrab:  true,
+		})
+		d.adHocPanel.AddChild(field)
+	}
+	addButton := unison.NewSVGButton(svg.CircledAdd)
+	addButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Add modifier"))
+	addButton.ClickCallback = func() {
+		d.adHocMods = append(d.adHocMods, &reactionModifier{Enabled: true})
+		d.syncAdHoc()
+	}
+	d.adHocPanel.AddChild(addButton)
+	d.syncTotal()
+	d.MarkForLayoutRecursively()
+	d.MarkForRedraw()
+}
+
+func (d *reactionRollDockable) syncTotal() {
+	if d.totalLabel != nil {
+		d.totalLabel.Text = fmt.Sprintf(i18n.Text("Total Modifier: %+d"), d.modifier())
+		d.totalLabel.MarkForLayoutAndRedraw()
+	}
+}
+
+func (d *reactionRollDockable) syncResult() {
+	if d.last == nil {
+		d.resultLabel.Text = i18n.Text("No reaction has been rolled.")
+		d.descLabel.Text = ""
+	} else {
+		d.resultLabel.Text = d.last.roll.Summary()
+		d.descLabel.Text = d.last.roll.Level().Description(d.last.roll.Situation)
+	}
+	d.logButton.SetEnabled(d.last != nil)
+	d.resultLabel.MarkForLayoutAndRedraw()
+	d.descLabel.MarkForLayoutAndRedraw()
+}
+
+func (d *reactionRollDockable) syncHistory() {
+	d.history.RemoveAllChildren()
+	d.history.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing * 2,
+		VSpacing: unison.StdVSpacing,
+	})
+	if len(d.past) == 0 {
+		label := NewFieldTrailingLabel(i18n.Text("No earlier reactions have been rolled."))
+		label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+		d.history.AddChild(label)
+	}
+	for i := len(d.past) - 1; i >= 0; i-- {
+		one := d.past[i]
+		d.history.AddChild(NewFieldTrailingLabel(one.subject))
+		situation := NewFieldTrailingLabel(one.roll.Situation.String())
+		situation.Font = model.FieldSecondaryFont
+		d.history.AddChild(situation)
+		d.history.AddChild(NewFieldTrailingLabel(one.roll.Summary()))
+	}
+	d.MarkForLayoutRecursively()
+	d.MarkForRedraw()
+}
+
+// TitleIcon implements unison.Dockable
+func (d *reactionRollDockable) TitleIcon(suggestedSize unison.Size) unison.Drawable {
+	return &unison.DrawableSVG{
+		SVG:  svg.Randomize,
+		Size: suggestedSize,
+	}
+}
+
+// Title implements unison.Dockable
+func (d *reactionRollDockable) Title() string {
+	return i18n.Text("Reaction Roll")
+}
+
+func (d *reactionRollDockable) String() string {
+	return d.Title()
+}
+
+// Tooltip implements unison.Dockable
+func (d *reactionRollDockable) Tooltip() string {
+	return ""
+}
+
+// Modified implements unison.Dockable
+func (d *reactionRollDockable) Modified() bool {
+	return false
+}
+
+// MayAttemptClose implements unison.TabCloser
+func (d *reactionRollDockable) MayAttemptClose() bool {
+	return true
+}
+
+// AttemptClose implements unison.TabCloser
+func (d *reactionRollDockable) AttemptClose() bool {
+	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
+		dc.Close(d)
+	}
+	return true
+}
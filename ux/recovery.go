@@ -0,0 +1,235 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/log/jot"
+	"github.com/richardwilkes/unison"
+)
+
+// recoverable defines the methods a FileBackedDockable must implement to have its unsaved edits periodically
+// snapshotted for crash recovery.
+type recoverable interface {
+	FileBackedDockable
+	Modified() bool
+	untitled() bool
+	snapshot(w io.Writer) error
+	adoptRecoveredSnapshot(info *model.RecoveryInfo)
+}
+
+var (
+	recoverySnapshots        = make(map[recoverable]*model.RecoveryInfo)
+	pendingRecoverySnapshots = make(map[*model.RecoveryInfo]bool)
+)
+
+func scheduleAutosave() {
+	unison.InvokeTaskAfter(func() {
+		autosave()
+		pruneBackingFileWatches()
+		scheduleAutosave()
+	}, time.Duration(model.GlobalSettings().General.AutosaveInterval)*time.Minute)
+}
+
+func autosave() {
+	open := make(map[recoverable]bool)
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, one := range dc.Dockables() {
+					if r, ok := one.(recoverable); ok && r.Modified() {
+						open[r] = true
+					}
+				}
+				return false
+			})
+		}
+	}
+	for r, info := range recoverySnapshots {
+		if !open[r] {
+			info.Remove()
+			delete(recoverySnapshots, r)
+		}
+	}
+	for r := range open {
+		info, exists := recoverySnapshots[r]
+		if !exists {
+			info = model.NewRecoveryInfo(r.Title(), r.BackingFilePath(), r.untitled())
+			recoverySnapshots[r] = info
+		}
+		if pendingRecoverySnapshots[info] {
+			continue
+		}
+		info.Update(r.Title(), r.BackingFilePath(), r.untitled())
+		// The data is captured here, on the UI thread, but written out in the background.
+		var buffer bytes.Buffer
+		if err := r.snapshot(&buffer); err != nil {
+			jot.Warn(err)
+			continue
+		}
+		pendingRecoverySnapshots[info] = true
+		go writeRecoverySnapshot(info, buffer.Bytes())
+	}
+}
+
+func writeRecoverySnapshot(info *model.RecoveryInfo, data []byte) {
+	if err := info.Snapshot(data); err != nil {
+		jot.Warn(err)
+	}
+	unison.InvokeTask(func() { delete(pendingRecoverySnapshots, info) })
+}
+
+// discardRecoverySnapshot removes any snapshot taken for the dockable, such as after it has been saved.
+func discardRecoverySnapshot(d FileBackedDockable) {
+	if r, ok := d.(recoverable); ok {
+		if info, exists := recoverySnapshots[r]; exists {
+			info.Remove()
+			delete(recoverySnapshots, r)
+		}
+	}
+}
+
+func discardAllRecoverySnapshots() {
+	for r, info := range recoverySnapshots {
+		info.Remove()
+		delete(recoverySnapshots, r)
+	}
+}
+
+// offerRecovery presents the documents that were left with unsaved edits the last time the application exited and
+// reopens the ones the user chooses.
+func offerRecovery(wnd *unison.Window) {
+	infos := model.RecoveryInfos()
+	if len(infos) == 0 {
+		return
+	}
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{
+		Columns:  3,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	label := unison.NewLabel()
+	label.Text = i18n.Text("The following documents had unsaved changes when GCS last exited. Documents that are not selected will be discarded.")
+	label.SetLayoutData(&unison.FlexLayoutData{HSpan: 3})
+	panel.AddChild(label)
+	checkboxes := make([]*unison.CheckBox, len(infos))
+	for i, info := range infos {
+		info := info
+		checkbox := unison.NewCheckBox()
+		checkbox.Text = info.Title
+		checkbox.State = unison.OnCheckState
+		checkboxes[i] = checkbox
+		panel.AddChild(checkbox)
+		detail := NewFieldTrailingLabel(recoveryDetail(info))
+		if !info.Untitled {
+			detail.Tooltip = unison.NewTooltipWithText(info.OriginalPath)
+		}
+		panel.AddChild(detail)
+		compareButton := unison.NewButton()
+		compareButton.Text = i18n.Text("Compare to Disk…")
+		compareButton.ClickCallback = func() { showRecoveryComparison(info) }
+		panel.AddChild(compareButton)
+	}
+	dialog, err := unison.NewDialog(unison.DefaultDialogTheme.QuestionIcon, unison.DefaultDialogTheme.QuestionIconInk,
+		panel, []*unison.DialogButtonInfo{
+			{
+				Title:        i18n.Text("Decide Later"),
+				ResponseCode: unison.ModalResponseCancel,
+				KeyCodes:     []unison.KeyCode{unison.KeyEscape},
+			},
+			unison.NewOKButtonInfoWithTitle(i18n.Text("Recover Selected")),
+		})
+	if err != nil {
+		jot.Error(err)
+		return
+	}
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	for i, info := range infos {
+		if checkboxes[i].State == unison.OnCheckState {
+			recoverDocument(wnd, info)
+		} else {
+			info.Remove()
+		}
+	}
+}
+
+func recoveryDetail(info *model.RecoveryInfo) string {
+	const format = "Jan 2, 2006 3:04 PM"
+	detail := fmt.Sprintf(i18n.Text("Snapshot taken %s"), info.Taken.Format(format))
+	if diskTime, exists := info.DiskVersionTime(); exists {
+		if diskTime.After(info.Taken) {
+			detail += fmt.Sprintf(i18n.Text("; the file on disk is newer (%s)"), diskTime.Format(format))
+		}
+	} else if !info.Untitled {
+		detail += i18n.Text("; the file no longer exists on disk")
+	}
+	return detail
+}
+
+func showRecoveryComparison(info *model.RecoveryInfo) {
+	diff, err := info.CompareToDisk()
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to compare to the file on disk"), err)
+		return
+	}
+	if strings.TrimSpace(diff) == "" {
+		diff = i18n.Text("The snapshot is identical to the file on disk.")
+	}
+	markdown := unison.NewMarkdown(true)
+	markdown.SetContent("```\n"+diff+"\n```", 800)
+	scroll := unison.NewScrollPanel()
+	scroll.SetBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.NewUniformInsets(1), false))
+	scroll.SetContent(markdown, unison.HintedFillBehavior, unison.FillBehavior)
+	scroll.SetLayoutData(&unison.FlexLayoutData{
+		SizeHint: unison.NewSize(820, 500),
+		HAlign:   unison.FillAlignment,
+		VAlign:   unison.FillAlignment,
+		HGrab:    true,
+		VGrab:    true,
+	})
+	panel := unison.NewPanel()
+	panel.SetLayout(&unison.FlexLayout{Columns: 1})
+	panel.AddChild(scroll)
+	dialog, err := unison.NewDialog(nil, nil, panel, []*unison.DialogButtonInfo{unison.NewOKButtonInfo()})
+	if err != nil {
+		jot.Error(err)
+		return
+	}
+	dialog.RunModal()
+}
+
+func recoverDocument(wnd *unison.Window, info *model.RecoveryInfo) {
+	snapshotPath := info.SnapshotPath()
+	d, err := model.FileInfoFor(snapshotPath).Load(snapshotPath)
+	if err != nil {
+		unison.ErrorDialogWithError(fmt.Sprintf(i18n.Text("Unable to recover %s"), info.Title), err)
+		return
+	}
+	r, ok := d.(recoverable)
+	if !ok {
+		info.Remove()
+		return
+	}
+	r.adoptRecoveredSnapshot(info)
+	recoverySnapshots[r] = info
+	DisplayNewDockable(wnd, r)
+}
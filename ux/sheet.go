@@ -15,6 +15,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -73,6 +74,8 @@ type Sheet struct {
 	CarriedEquipment     *PageList[*model.Equipment]
 	OtherEquipment       *PageList[*model.Equipment]
 	Notes                *PageList[*model.Note]
+	customBlocks         map[string]*CustomBlockPanel
+	columnState          string
 	dragReroutePanel     *unison.Panel
 	scale                int
 	awaitingUpdate       bool
@@ -214,6 +217,14 @@ func NewSheet(filePath string, entity *model.Entity) *Sheet {
 	calcButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Calculators (jumping, throwing, hiking, etc.)"))
 	calcButton.ClickCallback = func() { DisplayCalculator(s) }
 
+	complianceButton := unison.NewSVGButton(unison.TriangleExclamationSVG)
+	complianceButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Tech Level & Legality Report"))
+	complianceButton.ClickCallback = func() { ShowComplianceReport(s) }
+
+	expressionButton := unison.NewSVGButton(svg.Gears)
+	expressionButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Expression Tester & Variable Explorer"))
+	expressionButton.ClickCallback = func() { ShowExpressionTester(s) }
+
 	s.toolbar = unison.NewPanel()
 	s.toolbar.SetBorder(unison.NewCompoundBorder(unison.NewLineBorder(unison.DividerColor, 0, unison.Insets{Bottom: 1},
 		false), unison.NewEmptyBorder(unison.StdInsets())))
@@ -240,6 +251,8 @@ func NewSheet(filePath string, entity *model.Entity) *Sheet {
 	s.toolbar.AddChild(bodyTypeButton)
 	s.toolbar.AddChild(NewToolbarSeparator())
 	s.toolbar.AddChild(calcButton)
+	s.toolbar.AddChild(complianceButton)
+	s.toolbar.AddChild(expressionButton)
 	s.toolbar.AddChild(NewToolbarSeparator())
 	installSearchTracker(s.toolbar, func() {
 		s.Reactions.Table.ClearSelection()
@@ -432,6 +445,10 @@ func (s *Sheet) MarkModified(src unison.Paneler) {
 		s.targetMgr.ReacquireFocus(focusRefKey, s.toolbar, s.scroll.Content())
 		s.scroll.SetPosition(h, v)
 		UpdateCalculator(s)
+		UpdateExpressionTester(s)
+		refreshWikiLinks()
+		UpdateCampaignClock()
+		UpdateReactionRoll()
 	}
 }
 
@@ -478,6 +495,53 @@ func (s *Sheet) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (s *Sheet) untitled() bool {
+	return s.needsSaveAsPrompt
+}
+
+func (s *Sheet) snapshot(w io.Writer) error {
+	return s.entity.SaveTo(w)
+}
+
+func (s *Sheet) adoptRecoveredSnapshot(info *model.RecoveryInfo) {
+	s.path = info.OriginalPath
+	s.needsSaveAsPrompt = info.Untitled
+	s.crc = 0
+}
+
+func (s *Sheet) mergeFromDisk() {
+	disk, err := model.NewEntityFromFile(os.DirFS(filepath.Dir(s.path)), filepath.Base(s.path))
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to load ")+fs.BaseName(s.path), err)
+		return
+	}
+	e := s.entity
+	traits := model.DiffRows(e.Traits, disk.Traits)
+	skills := model.DiffRows(e.Skills, disk.Skills)
+	spells := model.DiffRows(e.Spells, disk.Spells)
+	carried := model.DiffRows(e.CarriedEquipment, disk.CarriedEquipment)
+	other := model.DiffRows(e.OtherEquipment, disk.OtherEquipment)
+	notes := model.DiffRows(e.Notes, disk.Notes)
+	var choices []*rowMergeChoice
+	choices = append(choices, rowMergeChoices(i18n.Text("Trait"), traits)...)
+	choices = append(choices, rowMergeChoices(i18n.Text("Skill"), skills)...)
+	choices = append(choices, rowMergeChoices(i18n.Text("Spell"), spells)...)
+	choices = append(choices, rowMergeChoices(i18n.Text("Carried Equipment"), carried)...)
+	choices = append(choices, rowMergeChoices(i18n.Text("Other Equipment"), other)...)
+	choices = append(choices, rowMergeChoices(i18n.Text("Note"), notes)...)
+	if !resolveRowMerge(s.Title(), choices) {
+		return
+	}
+	e.SetTraitList(model.MergeRows(e, e.Traits, disk.Traits, traits))
+	e.SetSkillList(model.MergeRows(e, e.Skills, disk.Skills, skills))
+	e.SetSpellList(model.MergeRows(e, e.Spells, disk.Spells, spells))
+	e.SetCarriedEquipmentList(model.MergeRows(e, e.CarriedEquipment, disk.CarriedEquipment, carried))
+	e.SetOtherEquipmentList(model.MergeRows(e, e.OtherEquipment, disk.OtherEquipment, other))
+	e.SetNoteList(model.MergeRows(e, e.Notes, disk.Notes, notes))
+	s.Rebuild(true)
+	s.MarkModified(nil)
+}
+
 func (s *Sheet) print() {
 	data, err := newPageExporter(s.entity).exportAsPDFBytes()
 	if err != nil {
@@ -574,6 +638,22 @@ func (s *Sheet) createLists() {
 	for i := len(children) - 1; i > 1; i-- {
 		page.RemoveChildAtIndex(i)
 	}
+	if state := fmt.Sprint(s.entity.SheetSettings.HiddenColumns,
+		model.HasToggleableTraits(s.entity.Traits)); state != s.columnState {
+		// The set of columns has changed, so the lists need to be recreated
+		s.columnState = state
+		s.Reactions = nil
+		s.ConditionalModifiers = nil
+		s.MeleeWeapons = nil
+		s.RangedWeapons = nil
+		s.Traits = nil
+		s.Skills = nil
+		s.Spells = nil
+		s.CarriedEquipment = nil
+		s.OtherEquipment = nil
+		s.Notes = nil
+	}
+	customBlocks := make(map[string]*CustomBlockPanel)
 	// Add the various blocks, based on the layout preference.
 	for _, col := range s.entity.SheetSettings.BlockLayout.ByRow() {
 		rowPanel := unison.NewPanel()
@@ -660,10 +740,22 @@ func (s *Sheet) createLists() {
 					s.Notes.Sync()
 				}
 				rowPanel.AddChild(s.Notes)
+			default:
+				if s.entity.SheetSettings.CustomBlockFor(c) != nil {
+					panel, exists := s.customBlocks[c]
+					if exists {
+						panel.Sync()
+					} else {
+						panel = NewCustomBlockPanel(s.entity, c)
+					}
+					customBlocks[c] = panel
+					rowPanel.AddChild(panel)
+				}
 			}
 		}
 		page.AddChild(rowPanel)
 	}
+	s.customBlocks = customBlocks
 	page.ApplyPreferredSize()
 }
 
@@ -755,6 +847,10 @@ func (s *Sheet) Rebuild(full bool) {
 	s.targetMgr.ReacquireFocus(focusRefKey, s.toolbar, s.scroll.Content())
 	s.scroll.SetPosition(h, v)
 	UpdateCalculator(s)
+	UpdateExpressionTester(s)
+	refreshWikiLinks()
+	UpdateCampaignClock()
+	UpdateReactionRoll()
 }
 
 func drawBandedBackground(p unison.Paneler, gc *unison.Canvas, rect unison.Rect, start, step int) {
@@ -0,0 +1,291 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/svg"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+func (d *sheetSettingsDockable) createCustomBlocks(content *unison.Panel) {
+	d.customBlocksPanel = unison.NewPanel()
+	d.customBlocksPanel.SetLayout(&unison.FlexLayout{
+		Columns:  5,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.customBlocksPanel.SetLayoutData(&unison.FlexLayoutData{HAlign: unison.FillAlignment})
+	d.rebuildCustomBlocks()
+	content.AddChild(d.customBlocksPanel)
+}
+
+func (d *sheetSettingsDockable) rebuildCustomBlocks() {
+	s := d.settings()
+	panel := d.customBlocksPanel
+	panel.RemoveAllChildren()
+	d.createHeader(panel, i18n.Text("Custom Blocks"), 5)
+	if len(s.CustomBlocks) != 0 {
+		for _, title := range []string{i18n.Text("ID"), i18n.Text("Title"), i18n.Text("Shows"), i18n.Text("Content")} {
+			label := unison.NewLabel()
+			label.Text = title
+			panel.AddChild(label)
+		}
+		panel.AddChild(unison.NewPanel())
+		for _, one := range s.CustomBlocks {
+			d.createCustomBlockRow(panel, one)
+		}
+	}
+	addButton := unison.NewSVGButton(svg.CircledAdd)
+	addButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Add Custom Block"))
+	addButton.ClickCallback = func() {
+		localSettings := d.settings()
+		block := &model.CustomBlock{ID: d.uniqueCustomBlockID()}
+		localSettings.CustomBlocks = append(localSettings.CustomBlocks, block)
+		localSettings.BlockLayout.SyncCustomKeys(localSettings.CustomBlockKeys())
+		d.customBlocksChanged()
+	}
+	panel.AddChild(addButton)
+	panel.MarkForLayoutAndRedraw()
+}
+
+func (d *sheetSettingsDockable) customBlocksChanged() {
+	d.rebuildCustomBlocks()
+	d.blockLayoutField.SetText(d.settings().BlockLayout.String())
+	d.syncSheet(true)
+}
+
+func (d *sheetSettingsDockable) uniqueCustomBlockID() string {
+	s := d.settings()
+	for i := 1; ; i++ {
+		id := fmt.Sprintf("b%d", i)
+		if s.CustomBlockFor(model.CustomBlockKeyPrefix+id) == nil {
+			return id
+		}
+	}
+}
+
+func (d *sheetSettingsDockable) createCustomBlockRow(panel *unison.Panel, block *model.CustomBlock) {
+	idField := d.createCurrencyTextField(panel, block.ID, func(text string) bool {
+		id := model.SanitizeCustomBlockID(text)
+		if id == "" || id != text {
+			return false
+		}
+		other := d.settings().CustomBlockFor(model.CustomBlockKeyPrefix + id)
+		return other == nil || other == block
+	}, func(text string) {
+		if text != block.ID {
+			s := d.settings()
+			oldKey := block.Key()
+			block.ID = text
+			s.BlockLayout.ReplaceKey(oldKey, block.Key())
+			d.blockLayoutField.SetText(s.BlockLayout.String())
+			d.syncSheet(true)
+		}
+	})
+	idField.Tooltip = unison.NewTooltipWithText(fmt.Sprintf(i18n.Text(`The key used to refer to this block in the block layout is "%s" followed by this ID`), model.CustomBlockKeyPrefix))
+	d.createCurrencyTextField(panel, block.Title, nil, func(text string) { block.Title = text })
+	typePopup := unison.NewPopupMenu[model.CustomBlockType]()
+	for _, one := range model.AllCustomBlockType {
+		typePopup.AddItem(one)
+	}
+	typePopup.Select(block.Type)
+	panel.AddChild(typePopup)
+	contentField := d.createCurrencyTextField(panel, customBlockContent(block), nil, func(text string) {
+		setCustomBlockContent(block, text)
+	})
+	contentField.SetLayoutData(&unison.FlexLayoutData{
+		HAlign: unison.FillAlignment,
+		HGrab:  true,
+	})
+	contentField.Tooltip = unison.NewTooltipWithText(customBlockContentTooltip(block.Type))
+	typePopup.SelectionChangedCallback = func(p *unison.PopupMenu[model.CustomBlockType]) {
+		if item, ok := p.Selected(); ok && item != block.Type {
+			content := contentField.Text()
+			block.Type = item
+			setCustomBlockContent(block, content)
+			contentField.Tooltip = unison.NewTooltipWithText(customBlockContentTooltip(item))
+			d.syncSheet(false)
+		}
+	}
+	deleteButton := unison.NewSVGButton(svg.Trash)
+	deleteButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove Custom Block"))
+	deleteButton.ClickCallback = func() {
+		s := d.settings()
+		list := make([]*model.CustomBlock, 0, len(s.CustomBlocks))
+		for _, one := range s.CustomBlocks {
+			if one != block {
+				list = append(list, one)
+			}
+		}
+		s.CustomBlocks = list
+		s.BlockLayout.SyncCustomKeys(s.CustomBlockKeys())
+		d.customBlocksChanged()
+	}
+	panel.AddChild(deleteButton)
+}
+
+func customBlockContent(block *model.CustomBlock) string {
+	if block.Type == model.AttributesCustomBlockType {
+		return strings.Join(block.Attributes, ", ")
+	}
+	return block.Filter
+}
+
+func setCustomBlockContent(block *model.CustomBlock, text string) {
+	if block.Type == model.AttributesCustomBlockType {
+		block.Attributes = nil
+		for _, one := range strings.Split(text, ",") {
+			if one = strings.TrimSpace(one); one != "" {
+				block.Attributes = append(block.Attributes, one)
+			}
+		}
+		block.Filter = ""
+	} else {
+		block.Attributes = nil
+		block.Filter = strings.TrimSpace(text)
+	}
+}
+
+func customBlockContentTooltip(blockType model.CustomBlockType) string {
+	switch blockType {
+	case model.SkillsCustomBlockType:
+		return i18n.Text("A comma-separated list of tags; skills with any of these tags will be shown")
+	case model.NotesCustomBlockType:
+		return i18n.Text("Notes containing this text will be shown; leave empty to show all notes")
+	default:
+		return i18n.Text("A comma-separated list of the IDs of the attributes and pools to show")
+	}
+}
+
+func (d *sheetSettingsDockable) createHiddenColumns(content *unison.Panel) {
+	d.hiddenColumnsPanel = unison.NewPanel()
+	d.hiddenColumnsPanel.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.hiddenColumnsPanel.SetLayoutData(&unison.FlexLayoutData{HAlign: unison.FillAlignment})
+	d.rebuildHiddenColumns()
+	content.AddChild(d.hiddenColumnsPanel)
+}
+
+func (d *sheetSettingsDockable) rebuildHiddenColumns() {
+	panel := d.hiddenColumnsPanel
+	panel.RemoveAllChildren()
+	d.createHeader(panel, i18n.Text("Hidden Columns"), 2)
+	entity := d.entity()
+	if entity == nil {
+		entity = model.NewEntity(model.PC)
+	}
+	for _, key := range []string{
+		model.BlockLayoutReactionsKey,
+		model.BlockLayoutConditionalModifiersKey,
+		model.BlockLayoutMeleeKey,
+		model.BlockLayoutRangedKey,
+		model.BlockLayoutTraitsKey,
+		model.BlockLayoutSkillsKey,
+		model.BlockLayoutSpellsKey,
+		model.BlockLayoutEquipmentKey,
+		model.BlockLayoutOtherEquipmentKey,
+		model.BlockLayoutNotesKey,
+	} {
+		choices := hideableColumns(entity, key)
+		if len(choices) == 0 {
+			continue
+		}
+		panel.AddChild(NewFieldLeadingLabel(blockLayoutKeyTitle(key)))
+		wrapper := unison.NewPanel()
+		wrapper.SetLayout(&unison.FlowLayout{
+			HSpacing: unison.StdHSpacing,
+			VSpacing: unison.StdVSpacing,
+		})
+		wrapper.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.FillAlignment,
+			HGrab:  true,
+		})
+		for _, choice := range choices {
+			d.createHiddenColumnCheckBox(wrapper, key, choice)
+		}
+		panel.AddChild(wrapper)
+	}
+	panel.MarkForLayoutAndRedraw()
+}
+
+func (d *sheetSettingsDockable) createHiddenColumnCheckBox(panel *unison.Panel, key string, choice columnChoice) {
+	checkbox := unison.NewCheckBox()
+	checkbox.Text = choice.Title
+	checkbox.State = unison.CheckStateFromBool(d.settings().ColumnHidden(key, choice.ID))
+	checkbox.ClickCallback = func() {
+		d.settings().SetColumnHidden(key, choice.ID, checkbox.State == unison.OnCheckState)
+		d.syncSheet(true)
+	}
+	panel.AddChild(checkbox)
+}
+
+func hideableColumns(entity *model.Entity, key string) []columnChoice {
+	switch key {
+	case model.BlockLayoutReactionsKey:
+		return columnChoices[*model.ConditionalModifier](NewReactionModifiersProvider(entity))
+	case model.BlockLayoutConditionalModifiersKey:
+		return columnChoices[*model.ConditionalModifier](NewConditionalModifiersProvider(entity))
+	case model.BlockLayoutMeleeKey:
+		return columnChoices[*model.Weapon](NewWeaponsProvider(entity, model.MeleeWeaponType, true))
+	case model.BlockLayoutRangedKey:
+		return columnChoices[*model.Weapon](NewWeaponsProvider(entity, model.RangedWeaponType, true))
+	case model.BlockLayoutTraitsKey:
+		return columnChoices[*model.Trait](NewTraitsProvider(entity, true))
+	case model.BlockLayoutSkillsKey:
+		return columnChoices[*model.Skill](NewSkillsProvider(entity, true))
+	case model.BlockLayoutSpellsKey:
+		return columnChoices[*model.Spell](NewSpellsProvider(entity, true))
+	case model.BlockLayoutEquipmentKey:
+		return columnChoices[*model.Equipment](NewEquipmentProvider(entity, true, true))
+	case model.BlockLayoutOtherEquipmentKey:
+		return columnChoices[*model.Equipment](NewEquipmentProvider(entity, true, false))
+	case model.BlockLayoutNotesKey:
+		return columnChoices[*model.Note](NewNotesProvider(entity, true))
+	default:
+		return nil
+	}
+}
+
+func blockLayoutKeyTitle(key string) string {
+	switch key {
+	case model.BlockLayoutReactionsKey:
+		return i18n.Text("Reactions")
+	case model.BlockLayoutConditionalModifiersKey:
+		return i18n.Text("Conditional Modifiers")
+	case model.BlockLayoutMeleeKey:
+		return i18n.Text("Melee Weapons")
+	case model.BlockLayoutRangedKey:
+		return i18n.Text("Ranged Weapons")
+	case model.BlockLayoutTraitsKey:
+		return i18n.Text("Traits")
+	case model.BlockLayoutSkillsKey:
+		return i18n.Text("Skills")
+	case model.BlockLayoutSpellsKey:
+		return i18n.Text("Spells")
+	case model.BlockLayoutEquipmentKey:
+		return i18n.Text("Carried Equipment")
+	case model.BlockLayoutOtherEquipmentKey:
+		return i18n.Text("Other Equipment")
+	case model.BlockLayoutNotesKey:
+		return i18n.Text("Notes")
+	default:
+		return key
+	}
+}
@@ -12,9 +12,11 @@
 package ux
 
 import (
+	"fmt"
 	"io/fs"
 
 	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/unison"
@@ -36,6 +38,7 @@ type sheetSettingsDockable struct {
 	showEquipmentModifier              *unison.CheckBox
 	showSpellAdjustments               *unison.CheckBox
 	showTitleInsteadOfNameInPageFooter *unison.CheckBox
+	showBodyDiagram                    *unison.CheckBox
 	useMultiplicativeModifiers         *unison.CheckBox
 	useModifyDicePlusAdds              *unison.CheckBox
 	excludeUnspentPointsFromTotal      *unison.CheckBox
@@ -53,6 +56,10 @@ type sheetSettingsDockable struct {
 	bottomMarginField                  *unison.Field
 	rightMarginField                   *unison.Field
 	blockLayoutField                   *unison.Field
+	currencyPanel                      *unison.Panel
+	customBlocksPanel                  *unison.Panel
+	hiddenColumnsPanel                 *unison.Panel
+	studyHoursPerPointField            *DecimalField
 }
 
 // ShowSheetSettings the Sheet Settings. Pass in nil to edit the defaults or a sheet to edit the sheet's.
@@ -98,6 +105,13 @@ func (d *sheetSettingsDockable) settings() *model.SheetSettings {
 	return model.GlobalSettings().Sheet
 }
 
+func (d *sheetSettingsDockable) entity() *model.Entity {
+	if d.owner != nil {
+		return d.owner.Entity()
+	}
+	return nil
+}
+
 func (d *sheetSettingsDockable) initContent(content *unison.Panel) {
 	content.SetLayout(&unison.FlexLayout{
 		Columns:  1,
@@ -109,6 +123,9 @@ func (d *sheetSettingsDockable) initContent(content *unison.Panel) {
 	d.createUnitsOfMeasurement(content)
 	d.createWhereToDisplay(content)
 	d.createPageSettings(content)
+	d.createCurrencies(content)
+	d.createCustomBlocks(content)
+	d.createHiddenColumns(content)
 	d.createBlockLayout(content)
 }
 
@@ -126,6 +143,17 @@ func (d *sheetSettingsDockable) createDamageProgression(content *unison.Panel) {
 			d.damageProgressionPopup.Tooltip = unison.NewTooltipWithText(item.Tooltip())
 			d.settings().DamageProgression = item
 		})
+	title := i18n.Text("Study Hours per Point")
+	label := NewFieldLeadingLabel(title)
+	label.Tooltip = unison.NewTooltipWithText(i18n.Text("The number of hours of study required to gain a point"))
+	panel.AddChild(label)
+	d.studyHoursPerPointField = NewDecimalField(nil, "", title,
+		func() fxp.Int { return model.StudyHoursPerPointFor(d.entity()) },
+		func(v fxp.Int) {
+			d.settings().StudyHoursPerPoint = v
+			d.syncSheet(false)
+		}, fxp.One, fxp.Thousand, false, false)
+	panel.AddChild(d.studyHoursPerPointField)
 	content.AddChild(panel)
 }
 
@@ -157,6 +185,11 @@ func (d *sheetSettingsDockable) createOptions(content *unison.Panel) {
 			d.settings().UseTitleInFooter = d.showTitleInsteadOfNameInPageFooter.State == unison.OnCheckState
 			d.syncSheet(false)
 		})
+	d.showBodyDiagram = d.addCheckBox(panel, i18n.Text("Show a diagram of the hit locations in the body block"),
+		s.ShowBodyDiagram, func() {
+			d.settings().ShowBodyDiagram = d.showBodyDiagram.State == unison.OnCheckState
+			d.syncSheet(false)
+		})
 	d.useMultiplicativeModifiers = d.addCheckBoxWithLink(panel,
 		i18n.Text("Use Multiplicative Modifiers"), "P102", s.UseMultiplicativeModifiers, func() {
 			d.settings().UseMultiplicativeModifiers = d.useMultiplicativeModifiers.State == unison.OnCheckState
@@ -275,6 +308,207 @@ func (d *sheetSettingsDockable) createPageSettings(content *unison.Panel) {
 	content.AddChild(panel)
 }
 
+func (d *sheetSettingsDockable) createCurrencies(content *unison.Panel) {
+	d.currencyPanel = unison.NewPanel()
+	d.currencyPanel.SetLayout(&unison.FlexLayout{
+		Columns:  7,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	d.currencyPanel.SetLayoutData(&unison.FlexLayoutData{HAlign: unison.FillAlignment})
+	d.rebuildCurrencies()
+	content.AddChild(d.currencyPanel)
+}
+
+func (d *sheetSettingsDockable) rebuildCurrencies() {
+	s := d.settings()
+	panel := d.currencyPanel
+	panel.RemoveAllChildren()
+	d.createHeader(panel, i18n.Text("Currencies"), 7)
+	if len(s.Currencies) != 0 {
+		panel.AddChild(NewFieldLeadingLabel(i18n.Text("Default Currency")))
+		popup := unison.NewPopupMenu[*model.Currency]()
+		for _, one := range s.Currencies {
+			popup.AddItem(one)
+		}
+		popup.Select(s.DefaultCurrencyDef())
+		popup.SelectionChangedCallback = func(p *unison.PopupMenu[*model.Currency]) {
+			if item, ok := p.Selected(); ok {
+				d.settings().DefaultCurrency = item.ID
+				d.syncSheet(false)
+			}
+		}
+		popup.SetLayoutData(&unison.FlexLayoutData{HSpan: 6})
+		panel.AddChild(popup)
+		for _, title := range []string{
+			i18n.Text("ID"), i18n.Text("Name"), i18n.Text("Symbol"), i18n.Text("Rate"),
+			i18n.Text("Coin Weight"), "",
+		} {
+			label := unison.NewLabel()
+			label.Text = title
+			panel.AddChild(label)
+		}
+		panel.AddChild(unison.NewPanel())
+		for _, one := range s.Currencies {
+			d.createCurrencyRow(panel, one)
+		}
+	}
+	addButton := unison.NewSVGButton(svg.CircledAdd)
+	addButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Add Currency"))
+	addButton.ClickCallback = func() {
+		localSettings := d.settings()
+		localSettings.Currencies = append(localSettings.Currencies, &model.Currency{
+			ID:   d.uniqueCurrencyID(),
+			Rate: fxp.One,
+		})
+		d.rebuildCurrencies()
+		d.syncSheet(false)
+	}
+	panel.AddChild(addButton)
+	panel.MarkForLayoutAndRedraw()
+}
+
+func (d *sheetSettingsDockable) uniqueCurrencyID() string {
+	s := d.settings()
+	for i := 1; ; i++ {
+		id := fmt.Sprintf("c%d", i)
+		if s.CurrencyFor(id) == nil {
+			return id
+		}
+	}
+}
+
+func (d *sheetSettingsDockable) createCurrencyRow(panel *unison.Panel, c *model.Currency) {
+	validateID := func(text string) bool {
+		if text == "" {
+			return false
+		}
+		other := d.settings().CurrencyFor(text)
+		return other == nil || other == c
+	}
+	idField := d.createCurrencyTextField(panel, c.ID, validateID, nil)
+	// The ID is only applied once editing is finished, since renaming it also updates the equipment that uses it.
+	commitID := func() {
+		if text := idField.Text(); text != c.ID {
+			if validateID(text) {
+				d.commitCurrencyRename(c, text)
+			} else {
+				idField.SetText(c.ID)
+			}
+		}
+	}
+	idField.LostFocusCallback = func() {
+		commitID()
+		idField.DefaultFocusLost()
+	}
+	idField.KeyDownCallback = func(keyCode unison.KeyCode, mod unison.Modifiers, repeat bool) bool {
+		if keyCode == unison.KeyReturn || keyCode == unison.KeyNumPadEnter {
+			commitID()
+			return true
+		}
+		return idField.DefaultKeyDown(keyCode, mod, repeat)
+	}
+	idField.Tooltip = unison.NewTooltipWithText(i18n.Text("The key used to refer to this currency"))
+	d.createCurrencyTextField(panel, c.Name, nil, func(text string) { c.Name = text })
+	d.createCurrencyTextField(panel, c.Symbol, nil, func(text string) { c.Symbol = text })
+	rateField := d.createCurrencyTextField(panel, c.Rate.String(), func(text string) bool {
+		value, err := fxp.FromString(text)
+		return err == nil && value > 0
+	}, func(text string) { c.Rate = fxp.FromStringForced(text) })
+	rateField.Tooltip = unison.NewTooltipWithText(i18n.Text("The value of one unit of this currency, relative to the others"))
+	units := d.settings().DefaultWeightUnits
+	weightField := d.createCurrencyTextField(panel, units.Format(c.CoinWeight), func(text string) bool {
+		_, err := model.WeightFromString(text, d.settings().DefaultWeightUnits)
+		return err == nil
+	}, func(text string) { c.CoinWeight = model.WeightFromStringForced(text, d.settings().DefaultWeightUnits) })
+	weightField.Tooltip = unison.NewTooltipWithText(i18n.Text("The weight of a single coin"))
+	checkbox := unison.NewCheckBox()
+	checkbox.Text = i18n.Text("Symbol after value")
+	checkbox.State = unison.CheckStateFromBool(c.SymbolAfter)
+	checkbox.ClickCallback = func() {
+		c.SymbolAfter = checkbox.State == unison.OnCheckState
+		d.syncSheet(false)
+	}
+	panel.AddChild(checkbox)
+	deleteButton := unison.NewSVGButton(svg.Trash)
+	deleteButton.Tooltip = unison.NewTooltipWithText(i18n.Text("Remove Currency"))
+	deleteButton.ClickCallback = func() {
+		if entity := d.entity(); entity != nil && entity.UsesCurrency(c.ID) {
+			unison.ErrorDialogWithMessage(i18n.Text("Unable to remove currency"),
+				fmt.Sprintf(i18n.Text("%s is still used by equipment on this sheet."), c.String()))
+			return
+		}
+		s := d.settings()
+		list := make([]*model.Currency, 0, len(s.Currencies))
+		for _, one := range s.Currencies {
+			if one != c {
+				list = append(list, one)
+			}
+		}
+		s.Currencies = list
+		if s.DefaultCurrency == c.ID {
+			s.DefaultCurrency = ""
+		}
+		d.rebuildCurrencies()
+		d.syncSheet(false)
+	}
+	panel.AddChild(deleteButton)
+}
+
+// commitCurrencyRename changes the ID of the currency, along with any references to it. When the settings belong to a
+// sheet, this is recorded as an undoable edit on that sheet.
+func (d *sheetSettingsDockable) commitCurrencyRename(c *model.Currency, id string) {
+	apply := func(to string) {
+		s := d.settings()
+		if s.DefaultCurrency == c.ID {
+			s.DefaultCurrency = to
+		}
+		if entity := d.entity(); entity != nil {
+			entity.RenameCurrency(c.ID, to)
+		}
+		c.ID = to
+		d.syncSheet(false)
+	}
+	if d.owner != nil {
+		if mgr := unison.UndoManagerFor(d.owner); mgr != nil {
+			mgr.Add(&unison.UndoEdit[string]{
+				ID:       unison.NextUndoID(),
+				EditName: i18n.Text("Rename Currency"),
+				UndoFunc: func(edit *unison.UndoEdit[string]) {
+					apply(edit.BeforeData)
+					d.rebuildCurrencies()
+				},
+				RedoFunc: func(edit *unison.UndoEdit[string]) {
+					apply(edit.AfterData)
+					d.rebuildCurrencies()
+				},
+				BeforeData: c.ID,
+				AfterData:  id,
+			})
+		}
+	}
+	apply(id)
+}
+
+func (d *sheetSettingsDockable) createCurrencyTextField(panel *unison.Panel, current string, validate func(text string) bool, set func(text string)) *unison.Field {
+	field := unison.NewField()
+	field.SetText(current)
+	field.SetMinimumTextWidthUsing("Currency")
+	if validate != nil {
+		field.ValidateCallback = func() bool { return validate(field.Text()) }
+	}
+	if set != nil {
+		field.ModifiedCallback = func(_, after *unison.FieldState) {
+			if validate == nil || validate(after.Text) {
+				set(after.Text)
+				d.syncSheet(false)
+			}
+		}
+	}
+	panel.AddChild(field)
+	return field
+}
+
 func (d *sheetSettingsDockable) createBlockLayout(content *unison.Panel) {
 	s := d.settings()
 	panel := unison.NewPanel()
@@ -300,6 +534,7 @@ func (d *sheetSettingsDockable) createBlockLayout(content *unison.Panel) {
 	d.blockLayoutField.ModifiedCallback = func(_, after *unison.FieldState) {
 		if blockLayout, valid := model.NewBlockLayoutFromString(after.Text); valid {
 			localSettings := d.settings()
+			blockLayout.SyncCustomKeys(localSettings.CustomBlockKeys())
 			currentBlockLayout := blockLayout.String()
 			if lastBlockLayout != currentBlockLayout {
 				lastBlockLayout = currentBlockLayout
@@ -385,10 +620,12 @@ func (d *sheetSettingsDockable) reset() {
 func (d *sheetSettingsDockable) sync() {
 	s := d.settings()
 	d.damageProgressionPopup.Select(s.DamageProgression)
+	d.studyHoursPerPointField.Sync()
 	d.showTraitModifier.State = unison.CheckStateFromBool(s.ShowTraitModifierAdj)
 	d.showEquipmentModifier.State = unison.CheckStateFromBool(s.ShowEquipmentModifierAdj)
 	d.showSpellAdjustments.State = unison.CheckStateFromBool(s.ShowSpellAdj)
 	d.showTitleInsteadOfNameInPageFooter.State = unison.CheckStateFromBool(s.UseTitleInFooter)
+	d.showBodyDiagram.State = unison.CheckStateFromBool(s.ShowBodyDiagram)
 	d.useMultiplicativeModifiers.State = unison.CheckStateFromBool(s.UseMultiplicativeModifiers)
 	d.useHalfStatDefaults.State = unison.CheckStateFromBool(s.UseHalfStatDefaults)
 	d.useModifyDicePlusAdds.State = unison.CheckStateFromBool(s.UseModifyingDicePlusAdds)
@@ -406,6 +643,9 @@ func (d *sheetSettingsDockable) sync() {
 	d.bottomMarginField.SetText(s.Page.BottomMargin.String())
 	d.rightMarginField.SetText(s.Page.RightMargin.String())
 	d.blockLayoutField.SetText(s.BlockLayout.String())
+	d.rebuildCurrencies()
+	d.rebuildCustomBlocks()
+	d.rebuildHiddenColumns()
 	d.MarkForRedraw()
 }
 
@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -51,5 +52,6 @@ func NewSkillTableDockable(filePath string, skills []*model.Skill) *TableDockabl
 	provider := &skillListProvider{skills: skills}
 	return NewTableDockable(filePath, model.SkillsExt, NewSkillsProvider(provider, false),
 		func(path string) error { return model.SaveSkills(provider.SkillList(), path) },
+		func(w io.Writer) error { return model.SaveSkillsTo(provider.SkillList(), w) },
 		NewSkillItemID, NewSkillContainerItemID, NewTechniqueItemID)
 }
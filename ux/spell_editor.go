@@ -85,6 +85,7 @@ func initSpellEditor(e *editor[*model.Spell, *model.SpellEditData], content *uni
 		addLabelAndStringField(content, i18n.Text("Maintenance Cost"), "", &e.editorData.MaintenanceCost)
 		addLabelAndStringField(content, i18n.Text("Casting Time"), "", &e.editorData.CastingTime)
 		addLabelAndStringField(content, i18n.Text("Casting Duration"), "", &e.editorData.Duration)
+		addEnergySourcePopup(content, e.target.Entity, &e.editorData.EnergySource)
 	}
 	addNotesLabelAndField(content, &e.editorData.LocalNotes)
 	addVTTNotesLabelAndField(content, &e.editorData.VTTNotes)
@@ -102,3 +103,24 @@ func initSpellEditor(e *editor[*model.Spell, *model.SpellEditData], content *uni
 	}
 	return nil
 }
+
+func addEnergySourcePopup(parent *unison.Panel, entity *model.Entity, fieldData *string) {
+	label := i18n.Text("Energy Source")
+	parent.AddChild(NewFieldLeadingLabel(label))
+	current := *fieldData
+	if current == "" {
+		current = model.DefaultSpellEnergySource
+	}
+	popup := addAttributeChoicePopup(parent, entity, "", &current, model.PoolsOnlyFlag)
+	popup.Tooltip = unison.NewTooltipWithText(i18n.Text("The pool that energy is drawn from when casting the spell"))
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[*model.AttributeChoice]) {
+		if choice, ok := p.Selected(); ok {
+			if choice.Key == model.DefaultSpellEnergySource {
+				*fieldData = ""
+			} else {
+				*fieldData = choice.Key
+			}
+			MarkModified(parent)
+		}
+	}
+}
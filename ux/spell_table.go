@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -51,5 +52,6 @@ func NewSpellTableDockable(filePath string, spells []*model.Spell) *TableDockabl
 	provider := &spellListProvider{spells: spells}
 	return NewTableDockable(filePath, model.SpellsExt, NewSpellsProvider(provider, false),
 		func(path string) error { return model.SaveSpells(provider.SpellList(), path) },
+		func(w io.Writer) error { return model.SaveSpellsTo(provider.SpellList(), w) },
 		NewSpellItemID, NewSpellContainerItemID, NewRitualMagicSpellItemID)
 }
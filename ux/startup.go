@@ -44,7 +44,9 @@ func Start(files []string) {
 			jot.FatalIfErr(err)
 			SetupMenuBar(wnd)
 			NewWorkspace(wnd)
+			offerRecovery(wnd)
 			OpenFiles(files)
+			scheduleAutosave()
 			go func() {
 				for paths := range pathsChan {
 					unison.InvokeTask(func() { OpenFiles(paths) })
@@ -59,6 +61,7 @@ func Start(files []string) {
 					return false
 				}
 			}
+			discardAllRecoverySnapshots()
 			return true
 		}),
 	) // Never returns
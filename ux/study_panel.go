@@ -12,6 +12,8 @@
 package ux
 
 import (
+	"strings"
+
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/svg"
@@ -66,7 +68,10 @@ func newStudyPanel(entity *model.Entity, study *[]*model.Study) *studyPanel {
 
 	addButton := unison.NewSVGButton(svg.CircledAdd)
 	addButton.ClickCallback = func() {
-		def := &model.Study{Type: lastStudyTypeUsed}
+		def := &model.Study{
+			Type: lastStudyTypeUsed,
+			Date: model.GlobalSettings().CurrentGameDate(p.entity),
+		}
 		*study = slices.Insert(*study, 0, def)
 		p.insertStudyEntry(1, def, true)
 		unison.Ancestor[*unison.DockContainer](p).MarkForLayoutRecursively()
@@ -132,6 +137,29 @@ func (p *studyPanel) insertStudyEntry(index int, entry *model.Study, requestFocu
 	adjustedHoursField.SetEnabled(false)
 	panel.AddChild(adjustedHoursField)
 
+	date := i18n.Text("Date")
+	cal := model.GlobalSettings().CalendarRefFor(p.entity).Calendar
+	dateField := NewStringField(nil, "", date, func() string { return entry.Date },
+		func(s string) { entry.Date = strings.TrimSpace(s) })
+	dateField.Watermark = date
+	dateField.Tooltip = unison.NewTooltipWithText(i18n.Text("The in-game date of the study session"))
+	dateField.ValidateCallback = func() bool {
+		text := strings.TrimSpace(dateField.Text())
+		if text == "" {
+			return true
+		}
+		_, err := cal.ParseDate(text)
+		return err == nil
+	}
+	dateField.SetMinimumTextWidthUsing(cal.NewDateByDays(0).String())
+	panel.AddChild(dateField)
+
+	teacher := i18n.Text("Teacher")
+	teacherField := NewStringField(nil, "", teacher, func() string { return entry.Teacher },
+		func(s string) { entry.Teacher = s })
+	teacherField.Watermark = teacher
+	panel.AddChild(teacherField)
+
 	note := i18n.Text("Note")
 	notesField := NewStringField(nil, "", note, func() string { return entry.Note },
 		func(s string) { entry.Note = s })
@@ -162,7 +190,8 @@ func updateLimitations(info *unison.Label, studyType model.StudyType) {
 }
 
 func (p *studyPanel) updateTotal() {
-	if text := model.StudyHoursProgressText(model.ResolveStudyHours(*p.study)); text != p.total.Text {
+	if text := model.StudyHoursProgressText(model.ResolveStudyHours(*p.study),
+		model.StudyHoursPerPointFor(p.entity)); text != p.total.Text {
 		p.total.Text = text
 		p.total.MarkForLayoutAndRedraw()
 	}
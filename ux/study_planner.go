@@ -0,0 +1,340 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
+	"github.com/richardwilkes/rpgtools/calendar"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type studyGoal string
+
+var (
+	studyGoalLevel  = studyGoal(i18n.Text("Target Level"))
+	studyGoalPoints = studyGoal(i18n.Text("Additional Points"))
+)
+
+type studyPlannerPanel struct {
+	unison.Panel
+	target         any
+	plan           *model.StudyPlan
+	goalType       studyGoal
+	goal           fxp.Int
+	pointsValue    *unison.Label
+	remainingValue *unison.Label
+	completedValue *unison.Label
+}
+
+type studyConversionUndoEdit = *unison.UndoEdit[*studyConversionState]
+
+type studyConversionState struct {
+	Owner  Rebuildable
+	Target any
+	Points fxp.Int
+	Levels fxp.Int
+	Study  []*model.Study
+}
+
+func newStudyConversionState(owner Rebuildable, target any) *studyConversionState {
+	s := &studyConversionState{
+		Owner:  owner,
+		Target: target,
+	}
+	switch t := target.(type) {
+	case *model.Skill:
+		s.Points = t.Points
+		s.Study = t.Study
+	case *model.Spell:
+		s.Points = t.Points
+		s.Study = t.Study
+	case *model.Trait:
+		s.Levels = t.Levels
+		s.Study = t.Study
+	}
+	return s
+}
+
+func (s *studyConversionState) Apply() {
+	switch t := s.Target.(type) {
+	case *model.Skill:
+		t.Points = s.Points
+		t.Study = s.Study
+	case *model.Spell:
+		t.Points = s.Points
+		t.Study = s.Study
+	case *model.Trait:
+		t.Levels = s.Levels
+		t.Study = s.Study
+	}
+	s.Finish()
+}
+
+func (s *studyConversionState) Finish() {
+	if entity := studyTargetEntity(s.Target); entity != nil {
+		entity.Recalculate()
+	}
+	MarkModified(s.Owner)
+}
+
+func canPlanStudy[T model.NodeTypes](table *unison.Table[*Node[T]]) bool {
+	rows := table.SelectedRows(false)
+	if len(rows) != 1 {
+		return false
+	}
+	switch t := any(rows[0].Data()).(type) {
+	case *model.Skill:
+		return !t.Container()
+	case *model.Spell:
+		return !t.Container()
+	case *model.Trait:
+		return !t.Container()
+	default:
+		return false
+	}
+}
+
+func planStudy[T model.NodeTypes](owner Rebuildable, table *unison.Table[*Node[T]]) {
+	if rows := table.SelectedRows(false); len(rows) == 1 {
+		ShowStudyPlanner(owner, table, rows[0].Data())
+	}
+}
+
+// ShowStudyPlanner displays the study planner for the skill, spell or trait. The undo for any conversion of study hours
+// into points will be registered with the undo manager associated with the undoTarget.
+func ShowStudyPlanner(owner Rebuildable, undoTarget unison.Paneler, target any) {
+	entity := studyTargetEntity(target)
+	cal := model.GlobalSettings().CalendarRefFor(entity).Calendar
+	plan, err := model.NewStudyPlan(target, cal, latestStudyDate(cal, target))
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to plan study"), err)
+		return
+	}
+	p := newStudyPlannerPanel(target, plan)
+	canConvert := plan.HoursStudied >= plan.HoursPerPoint
+	dialog, err := unison.NewDialog(nil, nil, p,
+		[]*unison.DialogButtonInfo{
+			{
+				Title:        i18n.Text("Close"),
+				ResponseCode: unison.ModalResponseCancel,
+				KeyCodes:     []unison.KeyCode{unison.KeyEscape},
+			},
+			{
+				Title:        i18n.Text("Convert Completed Study"),
+				ResponseCode: unison.ModalResponseOK,
+			},
+		})
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to create study planner dialog"), err)
+		return
+	}
+	dialog.Button(unison.ModalResponseOK).SetEnabled(canConvert)
+	if dialog.RunModal() != unison.ModalResponseOK {
+		return
+	}
+	before := newStudyConversionState(owner, target)
+	if model.ConvertStudyToPoints(target) <= 0 {
+		return
+	}
+	after := newStudyConversionState(owner, target)
+	if mgr := unison.UndoManagerFor(undoTarget); mgr != nil {
+		mgr.Add(&unison.UndoEdit[*studyConversionState]{
+			ID:         unison.NextUndoID(),
+			EditName:   i18n.Text("Convert Completed Study"),
+			UndoFunc:   func(edit studyConversionUndoEdit) { edit.BeforeData.Apply() },
+			RedoFunc:   func(edit studyConversionUndoEdit) { edit.AfterData.Apply() },
+			BeforeData: before,
+			AfterData:  after,
+		})
+	}
+	after.Finish()
+}
+
+func newStudyPlannerPanel(target any, plan *model.StudyPlan) *studyPlannerPanel {
+	p := &studyPlannerPanel{
+		target: target,
+		plan:   plan,
+	}
+	p.Self = p
+	p.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+		VSpacing: unison.StdVSpacing,
+	})
+	p.goalType, p.goal = defaultStudyGoal(target)
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Study")))
+	p.AddChild(NewFieldTrailingLabel(fmt.Sprintf("%v", target)))
+
+	wrapper := unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	goalPopup := unison.NewPopupMenu[studyGoal]()
+	goalPopup.AddItem(studyGoalLevel, studyGoalPoints)
+	goalPopup.Select(p.goalType)
+	goalPopup.SelectionChangedCallback = func(popup *unison.PopupMenu[studyGoal]) {
+		if item, ok := popup.Selected(); ok {
+			p.goalType = item
+			p.update()
+		}
+	}
+	wrapper.AddChild(goalPopup)
+	wrapper.AddChild(NewDecimalField(nil, "", i18n.Text("Goal"),
+		func() fxp.Int { return p.goal },
+		func(v fxp.Int) {
+			p.goal = v
+			p.update()
+		}, 0, fxp.MaxBasePoints, false, false))
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Goal")))
+	p.AddChild(wrapper)
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Study Type")))
+	wrapper = unison.NewPanel()
+	wrapper.SetLayout(&unison.FlexLayout{
+		Columns:  2,
+		HSpacing: unison.StdHSpacing,
+	})
+	info := NewInfoPop()
+	updateLimitations(info, plan.Type)
+	typePopup := unison.NewPopupMenu[model.StudyType]()
+	for _, one := range model.AllStudyType {
+		typePopup.AddItem(one)
+	}
+	typePopup.Select(plan.Type)
+	wrapper.AddChild(typePopup)
+	wrapper.AddChild(info)
+	p.AddChild(wrapper)
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Hours per Day")))
+	hoursField := NewDecimalField(nil, "", i18n.Text("Hours per Day"),
+		func() fxp.Int { return plan.HoursPerDay },
+		func(v fxp.Int) {
+			plan.HoursPerDay = v
+			p.update()
+		}, 0, plan.Type.MaxHoursPerDay(), false, false)
+	p.AddChild(hoursField)
+	typePopup.SelectionChangedCallback = func(popup *unison.PopupMenu[model.StudyType]) {
+		if item, ok := popup.Selected(); ok {
+			plan.Type = item
+			updateLimitations(info, item)
+			hoursField.SetMinMax(0, item.MaxHoursPerDay())
+			if plan.HoursPerDay > item.MaxHoursPerDay() {
+				plan.HoursPerDay = item.MaxHoursPerDay()
+				hoursField.Sync()
+			}
+			p.update()
+		}
+	}
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Start Date")))
+	startField := NewStringField(nil, "", i18n.Text("Start Date"),
+		func() string { return plan.Start.String() },
+		func(s string) {
+			if date, err := plan.Calendar.ParseDate(strings.TrimSpace(s)); err == nil {
+				plan.Start = date
+				p.update()
+			}
+		})
+	startField.ValidateCallback = func() bool {
+		_, err := plan.Calendar.ParseDate(strings.TrimSpace(startField.Text()))
+		return err == nil
+	}
+	p.AddChild(startField)
+
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Points Needed")))
+	p.pointsValue = unison.NewLabel()
+	p.AddChild(p.pointsValue)
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Hours Remaining")))
+	p.remainingValue = unison.NewLabel()
+	p.AddChild(p.remainingValue)
+	p.AddChild(NewFieldLeadingLabel(i18n.Text("Completion")))
+	p.completedValue = unison.NewLabel()
+	p.AddChild(p.completedValue)
+	p.update()
+	return p
+}
+
+func (p *studyPlannerPanel) update() {
+	if p.goalType == studyGoalLevel {
+		points, err := model.StudyPointsForLevel(p.target, p.goal)
+		if err != nil {
+			p.setResults(err.Error(), "", "")
+			return
+		}
+		p.plan.PointsNeeded = points
+	} else {
+		p.plan.PointsNeeded = p.goal
+	}
+	proj := p.plan.Project()
+	var completion string
+	switch {
+	case proj.HoursRemaining <= 0:
+		completion = i18n.Text("Already reached")
+	case proj.Days < 0:
+		completion = i18n.Text("Never, at the current schedule")
+	default:
+		completion = fmt.Sprintf(i18n.Text("%s (%d days)"), proj.Completion.String(), proj.Days)
+	}
+	p.setResults(p.plan.PointsNeeded.String(), fmt.Sprintf(i18n.Text("%v of %v"), proj.HoursRemaining,
+		p.plan.PointsNeeded.Mul(p.plan.HoursPerPoint)), completion)
+}
+
+func (p *studyPlannerPanel) setResults(points, remaining, completion string) {
+	p.pointsValue.Text = points
+	p.remainingValue.Text = remaining
+	p.completedValue.Text = completion
+	p.MarkForLayoutAndRedraw()
+	if w := p.Window(); w != nil {
+		w.Pack()
+	}
+}
+
+func defaultStudyGoal(target any) (studyGoal, fxp.Int) {
+	switch t := target.(type) {
+	case *model.Skill:
+		return studyGoalLevel, t.LevelData.Level.Trunc().Max(0) + fxp.One
+	case *model.Spell:
+		return studyGoalLevel, t.LevelData.Level.Trunc().Max(0) + fxp.One
+	case *model.Trait:
+		if t.IsLeveled() {
+			return studyGoalLevel, t.Levels.Trunc() + fxp.One
+		}
+	}
+	return studyGoalPoints, fxp.One
+}
+
+// latestStudyDate returns the most recent date recorded in the target's study, or the first day of the calendar if no
+// dates have been recorded.
+func latestStudyDate(cal *calendar.Calendar, target any) calendar.Date {
+	result := cal.NewDateByDays(0)
+	found := false
+	study, _, _ := model.StudyTargetData(target)
+	for _, one := range study {
+		if date, err := cal.ParseDate(one.Date); err == nil && (!found || date.Days > result.Days) {
+			result = date
+			found = true
+		}
+	}
+	return result
+}
+
+func studyTargetEntity(target any) *model.Entity {
+	_, entity, _ := model.StudyTargetData(target)
+	return entity
+}
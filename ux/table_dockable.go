@@ -15,9 +15,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/model/jio"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/i18n"
@@ -43,11 +46,16 @@ type TableDockable[T model.NodeTypes] struct {
 	undoMgr           *unison.UndoManager
 	provider          TableProvider[T]
 	saver             func(path string) error
+	snapshotter       func(w io.Writer) error
 	canCreateIDs      map[int]bool
 	hierarchyButton   *unison.Button
 	sizeToFitButton   *unison.Button
 	filterPopup       *unison.PopupMenu[string]
 	filterField       *unison.Field
+	minTLPopup        *unison.PopupMenu[filterBound]
+	maxTLPopup        *unison.PopupMenu[filterBound]
+	minLCPopup        *unison.PopupMenu[filterBound]
+	maxLCPopup        *unison.PopupMenu[filterBound]
 	scroll            *unison.ScrollPanel
 	tableHeader       *unison.TableHeader[*Node[T]]
 	table             *unison.Table[*Node[T]]
@@ -57,7 +65,7 @@ type TableDockable[T model.NodeTypes] struct {
 }
 
 // NewTableDockable creates a new TableDockable for list data files.
-func NewTableDockable[T model.NodeTypes](filePath, extension string, provider TableProvider[T], saver func(path string) error, canCreateIDs ...int) *TableDockable[T] {
+func NewTableDockable[T model.NodeTypes](filePath, extension string, provider TableProvider[T], saver func(path string) error, snapshotter func(w io.Writer) error, canCreateIDs ...int) *TableDockable[T] {
 	header, table := NewNodeTable[T](provider, nil)
 	d := &TableDockable[T]{
 		path:              filePath,
@@ -65,6 +73,7 @@ func NewTableDockable[T model.NodeTypes](filePath, extension string, provider Ta
 		undoMgr:           unison.NewUndoManager(200, func(err error) { jot.Error(err) }),
 		provider:          provider,
 		saver:             saver,
+		snapshotter:       snapshotter,
 		canCreateIDs:      make(map[int]bool),
 		scroll:            unison.NewScrollPanel(),
 		tableHeader:       header,
@@ -104,6 +113,9 @@ func NewTableDockable[T model.NodeTypes](filePath, extension string, provider Ta
 	d.InstallCmdHandlers(OpenEachPageReferenceItemID,
 		func(_ any) bool { return CanOpenPageRef(d.table) },
 		func(_ any) { OpenEachPageRef(d.table) })
+	d.InstallCmdHandlers(ExportCardsItemID,
+		func(_ any) bool { return canExportCards(d.table) },
+		func(_ any) { exportCards(d.provider.Entity(), d.table) })
 	d.InstallCmdHandlers(SaveItemID,
 		func(_ any) bool { return d.Modified() },
 		func(_ any) { d.save(false) })
@@ -237,6 +249,15 @@ func (d *TableDockable[T]) createToolbar() *unison.Panel {
 	toolbar.AddChild(d.sizeToFitButton)
 	toolbar.AddChild(d.filterField)
 	toolbar.AddChild(d.filterPopup)
+	var zero T
+	if _, ok := any(zero).(interface{ TL() string }); ok {
+		d.minTLPopup, d.maxTLPopup = d.addFilterBoundPopups(toolbar, i18n.Text("TL"), 12,
+			i18n.Text("Minimum tech level"), i18n.Text("Maximum tech level"))
+	}
+	if _, ok := any(zero).(*model.Equipment); ok {
+		d.minLCPopup, d.maxLCPopup = d.addFilterBoundPopups(toolbar, i18n.Text("LC"), 4,
+			i18n.Text("Minimum legality class"), i18n.Text("Maximum legality class"))
+	}
 	toolbar.SetLayoutData(&unison.FlexLayoutData{
 		HAlign: unison.FillAlignment,
 		HGrab:  true,
@@ -353,6 +374,42 @@ func (d *TableDockable[T]) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (d *TableDockable[T]) untitled() bool {
+	return d.needsSaveAsPrompt
+}
+
+func (d *TableDockable[T]) snapshot(w io.Writer) error {
+	return d.snapshotter(w)
+}
+
+func (d *TableDockable[T]) adoptRecoveredSnapshot(info *model.RecoveryInfo) {
+	d.path = info.OriginalPath
+	d.needsSaveAsPrompt = info.Untitled
+	d.crc = 0
+}
+
+func (d *TableDockable[T]) mergeFromDisk() {
+	loaded, err := model.FileInfoFor(d.path).Load(d.path)
+	if err != nil {
+		unison.ErrorDialogWithError(i18n.Text("Unable to load ")+fs.BaseName(d.path), err)
+		return
+	}
+	disk, ok := loaded.(*TableDockable[T])
+	if !ok {
+		return
+	}
+	mine := d.provider.RootData()
+	theirs := disk.provider.RootData()
+	diffs := model.DiffRows(mine, theirs)
+	singular, _ := d.provider.ItemNames()
+	if !resolveRowMerge(d.Title(), rowMergeChoices(singular, diffs)) {
+		return
+	}
+	d.provider.SetRootData(model.MergeRows(d.provider.Entity(), mine, theirs, diffs))
+	d.Rebuild(true)
+	d.MarkModified(nil)
+}
+
 func (d *TableDockable[T]) toggleHierarchy() {
 	first := true
 	open := false
@@ -382,6 +439,31 @@ func (d *TableDockable[T]) sizeToFit() {
 	d.table.MarkForRedraw()
 }
 
+func (d *TableDockable[T]) addFilterBoundPopups(toolbar *unison.Panel, title string, maximum int, minTooltip, maxTooltip string) (minPopup, maxPopup *unison.PopupMenu[filterBound]) {
+	toolbar.AddChild(NewFieldLeadingLabel(title))
+	minPopup = d.newFilterBoundPopup(maximum, minTooltip)
+	toolbar.AddChild(minPopup)
+	toolbar.AddChild(NewFieldInteriorLeadingLabel(i18n.Text("to")))
+	maxPopup = d.newFilterBoundPopup(maximum, maxTooltip)
+	toolbar.AddChild(maxPopup)
+	return minPopup, maxPopup
+}
+
+func (d *TableDockable[T]) newFilterBoundPopup(maximum int, tooltip string) *unison.PopupMenu[filterBound] {
+	popup := unison.NewPopupMenu[filterBound]()
+	popup.AddItem(unboundedFilter)
+	for i := 0; i <= maximum; i++ {
+		popup.AddItem(filterBound(i))
+	}
+	popup.SelectIndex(0)
+	popup.Tooltip = unison.NewTooltipWithText(tooltip)
+	popup.SelectionChangedCallback = func(_ *unison.PopupMenu[filterBound]) {
+		d.applyFilter(nil, d.filterField.GetFieldState())
+	}
+	popup.SetLayoutData(&unison.FlexLayoutData{VAlign: unison.MiddleAlignment})
+	return popup
+}
+
 func (d *TableDockable[T]) applyFilter(_, after *unison.FieldState) {
 	tags := make(map[string]bool)
 	for _, i := range d.filterPopup.SelectedIndexes() {
@@ -392,10 +474,17 @@ func (d *TableDockable[T]) applyFilter(_, after *unison.FieldState) {
 		}
 	}
 	text := strings.TrimSpace(after.Text)
-	if len(tags) == 0 && text == "" {
+	minTL, maxTL := filterBounds(d.minTLPopup, d.maxTLPopup)
+	minLC, maxLC := filterBounds(d.minLCPopup, d.maxLCPopup)
+	bounded := minTL != unboundedFilter || maxTL != unboundedFilter || minLC != unboundedFilter ||
+		maxLC != unboundedFilter
+	if len(tags) == 0 && text == "" && !bounded {
 		d.table.ApplyFilter(nil)
 	} else {
 		d.table.ApplyFilter(func(row *Node[T]) bool {
+			if bounded && !rowWithinFilterBounds(row.Data(), minTL, maxTL, minLC, maxLC) {
+				return true
+			}
 			if row.PartialMatchExceptTag(text) {
 				for tag := range tags {
 					if !row.HasTag(tag) {
@@ -433,3 +522,62 @@ func (d *TableDockable[T]) crc64() uint64 {
 	}
 	return model.CRCBytes(0, buffer.Bytes())
 }
+
+// filterBound holds one end of a numeric range filter.
+type filterBound int
+
+const unboundedFilter filterBound = -1
+
+func (b filterBound) String() string {
+	if b == unboundedFilter {
+		return i18n.Text("Any")
+	}
+	return strconv.Itoa(int(b))
+}
+
+func filterBounds(minPopup, maxPopup *unison.PopupMenu[filterBound]) (minimum, maximum filterBound) {
+	minimum = unboundedFilter
+	maximum = unboundedFilter
+	if minPopup != nil {
+		if b, ok := minPopup.Selected(); ok {
+			minimum = b
+		}
+	}
+	if maxPopup != nil {
+		if b, ok := maxPopup.Selected(); ok {
+			maximum = b
+		}
+	}
+	return minimum, maximum
+}
+
+func (b filterBound) admits(value fxp.Int, isMax bool) bool {
+	switch {
+	case b == unboundedFilter:
+		return true
+	case isMax:
+		return value <= fxp.From(int(b))
+	default:
+		return value >= fxp.From(int(b))
+	}
+}
+
+// rowWithinFilterBounds returns true if the row's tech level and legality class fall within the bounds. Rows that
+// don't specify a value are not excluded by that value's bounds.
+func rowWithinFilterBounds(data any, minTL, maxTL, minLC, maxLC filterBound) bool {
+	if p, ok := data.(interface{ TL() string }); ok {
+		if tl, start, _ := model.ExtractTechLevel(p.TL()); start != -1 &&
+			(!minTL.admits(tl, false) || !maxTL.admits(tl, true)) {
+			return false
+		}
+	}
+	if eqp, ok := data.(*model.Equipment); ok {
+		if lc, found := model.ExtractLegalityClass(eqp.LegalityClass); found {
+			value := fxp.From(lc)
+			if !minLC.admits(value, false) || !maxLC.admits(value, true) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/gcs/v5/model/fxp"
 	"github.com/richardwilkes/gcs/v5/svg"
 	"github.com/richardwilkes/toolbox/i18n"
 	"github.com/richardwilkes/toolbox/log/jot"
@@ -309,6 +310,60 @@ func (n *Node[T]) createLabelCell(c *model.CellData, width float32, foreground u
 		p.AddChild(label)
 		tooltip = c.UnsatisfiedReason
 	}
+	if c.Warning != "" {
+		label := unison.NewLabel()
+		label.Font = n.secondaryFieldFont()
+		height := label.Font.LineHeight()
+		label.Drawable = &unison.DrawableSVG{
+			SVG:  unison.TriangleExclamationSVG,
+			Size: unison.NewSize(height, height),
+		}
+		label.Text = i18n.Text("Over capacity")
+		label.HAlign = c.Alignment
+		label.VAlign = unison.MiddleAlignment
+		label.ClientData()[invertColorsMarker] = true
+		label.OnBackgroundInk = model.OnOverloadedColor
+		label.SetBorder(unison.NewEmptyBorder(unison.Insets{
+			Left:  4,
+			Right: 4,
+		}))
+		label.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+			gc.DrawRect(rect, model.OverloadedColor.Paint(gc, rect, unison.Fill))
+			label.DefaultDraw(gc, rect)
+		}
+		p.AddChild(label)
+		if tooltip != "" {
+			tooltip += "\n\n"
+		}
+		tooltip += c.Warning
+	}
+	if c.ComplianceWarning != "" {
+		label := unison.NewLabel()
+		label.Font = n.secondaryFieldFont()
+		height := label.Font.LineHeight()
+		label.Drawable = &unison.DrawableSVG{
+			SVG:  unison.TriangleExclamationSVG,
+			Size: unison.NewSize(height, height),
+		}
+		label.Text = i18n.Text("Noncompliant")
+		label.HAlign = c.Alignment
+		label.VAlign = unison.MiddleAlignment
+		label.ClientData()[invertColorsMarker] = true
+		label.OnBackgroundInk = unison.OnWarningColor
+		label.SetBorder(unison.NewEmptyBorder(unison.Insets{
+			Left:  4,
+			Right: 4,
+		}))
+		label.DrawCallback = func(gc *unison.Canvas, rect unison.Rect) {
+			gc.DrawRect(rect, unison.WarningColor.Paint(gc, rect, unison.Fill))
+			label.DefaultDraw(gc, rect)
+		}
+		p.AddChild(label)
+		if tooltip != "" {
+			tooltip += "\n\n"
+		}
+		tooltip += c.ComplianceWarning
+	}
 	if c.TemplateInfo != "" {
 		label := unison.NewLabel()
 		label.Font = n.secondaryFieldFont()
@@ -425,6 +480,25 @@ func handleCheck(data any, check unison.Paneler, checked bool) {
 		if item.Entity != nil {
 			item.Entity.Recalculate()
 		}
+	case *model.Trait:
+		before := newTraitActivationAdjuster(check, item)
+		if err := item.SetActive(checked); err != nil {
+			unison.ErrorDialogWithError(i18n.Text("Unable to change the trait's active state"), err)
+			return
+		}
+		if mgr := unison.UndoManagerFor(check); mgr != nil {
+			mgr.Add(&unison.UndoEdit[*traitActivationAdjuster]{
+				ID:         unison.NextUndoID(),
+				EditName:   i18n.Text("Toggle Active"),
+				UndoFunc:   func(edit *unison.UndoEdit[*traitActivationAdjuster]) { edit.BeforeData.Apply() },
+				RedoFunc:   func(edit *unison.UndoEdit[*traitActivationAdjuster]) { edit.AfterData.Apply() },
+				BeforeData: before,
+				AfterData:  newTraitActivationAdjuster(check, item),
+			})
+		}
+		if item.Entity != nil {
+			item.Entity.Recalculate()
+		}
 	case *model.TraitModifier:
 		item.Disabled = !checked
 		if mgr := unison.UndoManagerFor(check); mgr != nil {
@@ -504,6 +578,51 @@ func (a *equipmentModifierAdjuster) Apply() {
 	MarkModified(a.Owner)
 }
 
+type traitActivationState struct {
+	Active bool
+	InUse  bool
+}
+
+type traitActivationAdjuster struct {
+	Owner      Rebuildable
+	Target     *model.Trait
+	States     map[*model.Trait]traitActivationState
+	PoolDamage fxp.Int
+}
+
+func newTraitActivationAdjuster(check unison.Paneler, target *model.Trait) *traitActivationAdjuster {
+	a := &traitActivationAdjuster{
+		Owner:  unison.AncestorOrSelf[Rebuildable](check),
+		Target: target,
+		States: map[*model.Trait]traitActivationState{target: {Active: target.Active, InUse: target.InUse}},
+	}
+	if target.IsAlternative() {
+		for _, one := range target.Parent().Children {
+			a.States[one] = traitActivationState{Active: one.Active, InUse: one.InUse}
+		}
+	}
+	if target.Entity != nil {
+		if attr, ok := target.Entity.Attributes.Set[target.ActivationPoolAttribute()]; ok {
+			a.PoolDamage = attr.Damage
+		}
+	}
+	return a
+}
+
+func (a *traitActivationAdjuster) Apply() {
+	for t, state := range a.States {
+		t.Active = state.Active
+		t.InUse = state.InUse
+	}
+	if a.Target.Entity != nil {
+		if attr, ok := a.Target.Entity.Attributes.Set[a.Target.ActivationPoolAttribute()]; ok {
+			attr.Damage = a.PoolDamage
+		}
+		a.Target.Entity.Recalculate()
+	}
+	MarkModified(a.Owner)
+}
+
 type traitModifierAdjuster struct {
 	Owner    Rebuildable
 	Target   *model.TraitModifier
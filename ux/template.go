@@ -13,6 +13,7 @@ package ux
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -406,7 +407,7 @@ func processPickerRow[T model.NodeTypes](row T) (revised []T, abort bool) {
 				case model.CountTemplatePickerType:
 					total += fxp.One
 				case model.PointsTemplatePickerType:
-					total += rawPoints(children[i])
+					total += model.TemplatePickerPoints(children[i])
 				}
 			}
 		}
@@ -416,7 +417,7 @@ func processPickerRow[T model.NodeTypes](row T) (revised []T, abort bool) {
 		checkBox := unison.NewCheckBox()
 		checkBox.Text = fmt.Sprintf("%v", child)
 		if tp.Type == model.PointsTemplatePickerType {
-			points := rawPoints(child)
+			points := model.TemplatePickerPoints(child)
 			pointsLabel := i18n.Text("points")
 			if points == fxp.One {
 				pointsLabel = i18n.Text("point")
@@ -492,31 +493,6 @@ func processPickerRow[T model.NodeTypes](row T) (revised []T, abort bool) {
 	return rowChildren, false
 }
 
-func rawPoints(child any) fxp.Int {
-	switch nc := child.(type) {
-	case *model.Skill:
-		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == model.PointsTemplatePickerType &&
-			nc.TemplatePicker.Qualifier.Compare == model.EqualsNumber {
-			return nc.TemplatePicker.Qualifier.Qualifier
-		}
-		return nc.RawPoints()
-	case *model.Spell:
-		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == model.PointsTemplatePickerType &&
-			nc.TemplatePicker.Qualifier.Compare == model.EqualsNumber {
-			return nc.TemplatePicker.Qualifier.Qualifier
-		}
-		return nc.RawPoints()
-	case *model.Trait:
-		if nc.Container() && nc.TemplatePicker != nil && nc.TemplatePicker.Type == model.PointsTemplatePickerType &&
-			nc.TemplatePicker.Qualifier.Compare == model.EqualsNumber {
-			return nc.TemplatePicker.Qualifier.Qualifier
-		}
-		return nc.AdjustedPoints()
-	default:
-		return 0
-	}
-}
-
 func (d *Template) installNewItemCmdHandlers(itemID, containerID int, creator itemCreator) {
 	variant := NoItemVariant
 	if containerID == -1 {
@@ -639,6 +615,20 @@ func (d *Template) save(forceSaveAs bool) bool {
 	return success
 }
 
+func (d *Template) untitled() bool {
+	return d.needsSaveAsPrompt
+}
+
+func (d *Template) snapshot(w io.Writer) error {
+	return d.template.SaveTo(w)
+}
+
+func (d *Template) adoptRecoveredSnapshot(info *model.RecoveryInfo) {
+	d.path = info.OriginalPath
+	d.needsSaveAsPrompt = info.Untitled
+	d.crc = 0
+}
+
 func (d *Template) createLists() {
 	h, v := d.scroll.Position()
 	var refocusOnKey string
@@ -128,6 +128,29 @@ func (p *thresholdSettingsPanel) createContent() *unison.Panel {
 	field.Tooltip = unison.NewTooltipWithText(i18n.Text("A explanation of the effects of the threshold state"))
 	content.AddChild(field)
 
+	text = i18n.Text("Required Roll")
+	content.AddChild(NewFieldLeadingLabel(text))
+	field = NewStringField(p.pool.dockable.targetMgr, p.threshold.KeyPrefix+"roll", text,
+		func() string { return p.threshold.RequiredRoll },
+		func(s string) { p.threshold.RequiredRoll = s })
+	field.SetMinimumTextWidthUsing(prototypeMinNameWidth)
+	field.Tooltip = unison.NewTooltipWithText(i18n.Text("A roll the character must make upon entering the threshold state"))
+	content.AddChild(field)
+
+	text = i18n.Text("Disabled Tags")
+	label := NewFieldLeadingLabel(text)
+	tooltip := i18n.Text("Traits with any of these tags are disabled while in the threshold state. Separate multiple tags with commas")
+	label.Tooltip = unison.NewTooltipWithText(tooltip)
+	content.AddChild(label)
+	field = NewStringField(p.pool.dockable.targetMgr, p.threshold.KeyPrefix+"disabled_tags", text,
+		func() string { return model.CombineTags(p.threshold.DisabledTags) },
+		func(s string) { p.threshold.DisabledTags = model.ExtractTags(s) })
+	field.SetMinimumTextWidthUsing(prototypeMinNameWidth)
+	field.Tooltip = unison.NewTooltipWithText(tooltip)
+	content.AddChild(field)
+
+	content.AddChild(newFeaturesPanel(p.pool.dockable.Entity(), p.threshold, &p.threshold.Features))
+
 	return content
 }
 
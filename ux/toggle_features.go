@@ -0,0 +1,99 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/unison"
+)
+
+type toggleFeaturesUndoEdit = *unison.UndoEdit[*toggleFeaturesList]
+
+type toggleFeaturesList struct {
+	Owner  Rebuildable
+	Entity *model.Entity
+	List   []*featureToggleAdjuster
+}
+
+func (a *toggleFeaturesList) Apply() {
+	for _, one := range a.List {
+		one.Apply()
+	}
+	a.Finish()
+}
+
+func (a *toggleFeaturesList) Finish() {
+	if a.Entity != nil {
+		a.Entity.Recalculate()
+	}
+	MarkModified(a.Owner)
+}
+
+type featureToggleAdjuster struct {
+	Target *model.FeatureCondition
+	Active bool
+}
+
+func newFeatureToggleAdjuster(target *model.FeatureCondition) *featureToggleAdjuster {
+	return &featureToggleAdjuster{
+		Target: target,
+		Active: target.Active,
+	}
+}
+
+func (a *featureToggleAdjuster) Apply() {
+	a.Target.Active = a.Active
+}
+
+func canToggleFeatures[T model.NodeTypes](table *unison.Table[*Node[T]]) bool {
+	for _, row := range table.SelectedRows(false) {
+		if len(model.ToggleableConditions(row.Data())) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func toggleFeatures[T model.NodeTypes](owner Rebuildable, table *unison.Table[*Node[T]]) {
+	before := &toggleFeaturesList{Owner: owner}
+	after := &toggleFeaturesList{Owner: owner}
+	for _, row := range table.SelectedRows(false) {
+		data := row.Data()
+		conditions := model.ToggleableConditions(data)
+		if len(conditions) == 0 {
+			continue
+		}
+		if before.Entity == nil {
+			before.Entity = model.AsNode(data).OwningEntity()
+			after.Entity = before.Entity
+		}
+		for _, c := range conditions {
+			before.List = append(before.List, newFeatureToggleAdjuster(c))
+			c.Active = !c.Active
+			after.List = append(after.List, newFeatureToggleAdjuster(c))
+		}
+	}
+	if len(before.List) > 0 {
+		if mgr := unison.UndoManagerFor(table); mgr != nil {
+			mgr.Add(&unison.UndoEdit[*toggleFeaturesList]{
+				ID:         unison.NextUndoID(),
+				EditName:   i18n.Text("Toggle Situational Features"),
+				UndoFunc:   func(edit toggleFeaturesUndoEdit) { edit.BeforeData.Apply() },
+				RedoFunc:   func(edit toggleFeaturesUndoEdit) { edit.AfterData.Apply() },
+				BeforeData: before,
+				AfterData:  after,
+			})
+		}
+		before.Finish()
+	}
+}
@@ -33,7 +33,8 @@ func initTraitEditor(e *editor[*model.Trait, *model.TraitEditData], content *uni
 	addTagsLabelAndField(content, &e.editorData.Tags)
 	content.AddChild(unison.NewPanel())
 	addInvertedCheckBox(content, i18n.Text("Enabled"), &e.editorData.Disabled)
-	var perLevelField, levelField *DecimalField
+	var perLevelField, levelField, activationCostField *DecimalField
+	var activationPoolPopup *unison.PopupMenu[*model.AttributeChoice]
 	if !e.target.Container() {
 		wrapper := addFlowWrapper(content, i18n.Text("Point Cost"), 2)
 		costField := NewNonEditableField(func(field *NonEditableField) {
@@ -71,6 +72,27 @@ func initTraitEditor(e *editor[*model.Trait, *model.TraitEditData], content *uni
 			&e.editorData.PointsPerLevel, -fxp.MaxBasePoints, fxp.MaxBasePoints)
 		adjustFieldBlank(perLevelField, !e.editorData.CanLevel)
 		adjustFieldBlank(levelField, !e.editorData.CanLevel)
+
+		switchableCheckBox := addCheckBox(content, i18n.Text("Switchable"), &e.editorData.Switchable)
+		switchableCheckBox.Tooltip = unison.NewTooltipWithText(i18n.Text("Whether the trait can be switched on and off during play. Its features and weapons only apply while it is active."))
+		switchableCheckBox.SetLayoutData(&unison.FlexLayoutData{
+			HAlign: unison.EndAlignment,
+			VAlign: unison.MiddleAlignment,
+		})
+		wrapper = unison.NewPanel()
+		wrapper.SetLayout(&unison.FlexLayout{
+			Columns:  4,
+			HSpacing: unison.StdHSpacing,
+			VSpacing: unison.StdVSpacing,
+			VAlign:   unison.MiddleAlignment,
+		})
+		content.AddChild(wrapper)
+		activationCostField = addLabelAndDecimalField(wrapper, nil, "", i18n.Text("Activation Cost"),
+			i18n.Text("The amount deducted from the pool each time the trait is activated"),
+			&e.editorData.ActivationCost, 0, fxp.Thousand)
+		activationPoolPopup = addActivationPoolPopup(wrapper, e.target.Entity, &e.editorData.ActivationPool)
+		adjustFieldBlank(activationCostField, !e.editorData.Switchable)
+		adjustPopupBlank(activationPoolPopup, !e.editorData.Switchable)
 	}
 	addLabelAndPopup(content, i18n.Text("Self-Control Roll"), "", model.AllSelfControlRolls, &e.editorData.CR)
 	crAdjPopup := addLabelAndPopup(content, i18n.Text("CR Adjustment"), i18n.Text("Self-Control Roll Adjustment"),
@@ -79,9 +101,14 @@ func initTraitEditor(e *editor[*model.Trait, *model.TraitEditData], content *uni
 		crAdjPopup.SetEnabled(false)
 	}
 	var ancestryPopup *unison.PopupMenu[string]
+	var activeSlotsField *IntegerField
 	if e.target.Container() {
 		addLabelAndPopup(content, i18n.Text("Container Type"), "", model.AllContainerType,
 			&e.editorData.ContainerType)
+		activeSlotsField = addLabelAndIntegerField(content, nil, "", i18n.Text("Active Alternatives"),
+			i18n.Text("The number of alternative abilities that may be in use at the same time"),
+			&e.editorData.ActiveSlots, 0, 99)
+		adjustFieldBlank(activeSlotsField, e.editorData.ContainerType != model.AlternativeAbilitiesContainerType)
 		var choices []string
 		for _, lib := range model.AvailableAncestries(model.GlobalSettings().Libraries()) {
 			for _, one := range lib.List {
@@ -116,12 +143,21 @@ func initTraitEditor(e *editor[*model.Trait, *model.TraitEditData], content *uni
 		if levelField != nil {
 			adjustFieldBlank(levelField, !e.editorData.CanLevel)
 		}
+		if activationCostField != nil {
+			adjustFieldBlank(activationCostField, !e.editorData.Switchable)
+		}
+		if activationPoolPopup != nil {
+			adjustPopupBlank(activationPoolPopup, !e.editorData.Switchable)
+		}
 		if e.editorData.CR == model.NoCR {
 			crAdjPopup.SetEnabled(false)
 			crAdjPopup.Select(model.NoCRAdj)
 		} else {
 			crAdjPopup.SetEnabled(true)
 		}
+		if activeSlotsField != nil {
+			adjustFieldBlank(activeSlotsField, e.editorData.ContainerType != model.AlternativeAbilitiesContainerType)
+		}
 		if ancestryPopup != nil {
 			if e.editorData.ContainerType == model.RaceContainerType {
 				if !ancestryPopup.Enabled() {
@@ -137,3 +173,23 @@ func initTraitEditor(e *editor[*model.Trait, *model.TraitEditData], content *uni
 		}
 	}
 }
+
+func addActivationPoolPopup(parent *unison.Panel, entity *model.Entity, fieldData *string) *unison.PopupMenu[*model.AttributeChoice] {
+	current := *fieldData
+	if current == "" {
+		current = model.DefaultTraitActivationPool
+	}
+	popup := addAttributeChoicePopup(parent, entity, "", &current, model.PoolsOnlyFlag)
+	popup.Tooltip = unison.NewTooltipWithText(i18n.Text("The pool that the activation cost is drawn from"))
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[*model.AttributeChoice]) {
+		if choice, ok := p.Selected(); ok {
+			if choice.Key == model.DefaultTraitActivationPool {
+				*fieldData = ""
+			} else {
+				*fieldData = choice.Key
+			}
+			MarkModified(parent)
+		}
+	}
+	return popup
+}
@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -53,5 +54,6 @@ func NewTraitModifierTableDockable(filePath string, modifiers []*model.TraitModi
 	return NewTableDockable(filePath, model.TraitModifiersExt,
 		NewTraitModifiersProvider(provider, false),
 		func(path string) error { return model.SaveTraitModifiers(provider.TraitModifierList(), path) },
+		func(w io.Writer) error { return model.SaveTraitModifiersTo(provider.TraitModifierList(), w) },
 		NewTraitModifierItemID, NewTraitContainerModifierItemID)
 }
@@ -12,6 +12,7 @@
 package ux
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 
@@ -51,5 +52,6 @@ func NewTraitTableDockable(filePath string, traits []*model.Trait) *TableDockabl
 	provider := &traitListProvider{traits: traits}
 	return NewTableDockable(filePath, model.TraitsExt, NewTraitsProvider(provider, false),
 		func(path string) error { return model.SaveTraits(provider.TraitList(), path) },
+		func(w io.Writer) error { return model.SaveTraitsTo(provider.TraitList(), w) },
 		NewTraitItemID, NewTraitContainerItemID)
 }
@@ -138,6 +138,8 @@ func (p *traitsProvider) Headers() []unison.TableColumnHeader[*Node[*model.Trait
 	headers := make([]unison.TableColumnHeader[*Node[*model.Trait]], 0, len(ids))
 	for _, id := range ids {
 		switch id {
+		case model.TraitActiveColumn:
+			headers = append(headers, NewTraitActiveHeader[*model.Trait](p.forPage))
 		case model.TraitDescriptionColumn:
 			headers = append(headers, NewEditorListHeader[*model.Trait](i18n.Text("Trait"), "", p.forPage))
 		case model.TraitPointsColumn:
@@ -155,7 +157,11 @@ func (p *traitsProvider) SyncHeader(_ []unison.TableColumnHeader[*Node[*model.Tr
 }
 
 func (p *traitsProvider) ColumnIDs() []int {
-	columnIDs := append(make([]int, 0, 4),
+	columnIDs := make([]int, 0, 5)
+	if p.forPage && model.HasToggleableTraits(p.provider.TraitList()) {
+		columnIDs = append(columnIDs, model.TraitActiveColumn)
+	}
+	columnIDs = append(columnIDs,
 		model.TraitDescriptionColumn,
 		model.TraitPointsColumn,
 	)
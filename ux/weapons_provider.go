@@ -19,7 +19,10 @@ import (
 	"github.com/richardwilkes/unison"
 )
 
-var _ TableProvider[*model.Weapon] = &weaponsProvider{}
+var (
+	_ TableProvider[*model.Weapon] = &weaponsProvider{}
+	_ blockLayoutKeyer             = &weaponsProvider{}
+)
 
 type weaponsProvider struct {
 	table      *unison.Table[*Node[*model.Weapon]]
@@ -41,6 +44,10 @@ func (p *weaponsProvider) RefKey() string {
 	return p.weaponType.Key()
 }
 
+func (p *weaponsProvider) BlockLayoutKey() string {
+	return p.weaponType.BlockLayoutKey()
+}
+
 func (p *weaponsProvider) AllTags() []string {
 	return nil
 }
@@ -13,6 +13,7 @@ package ux
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/richardwilkes/gcs/v5/model"
 	"github.com/richardwilkes/gcs/v5/model/fxp"
@@ -240,6 +241,15 @@ func addLabelAndMultiLineStringField(parent *unison.Panel, labelText, tooltip st
 	parent.AddChild(field)
 }
 
+func addLabelAndIntegerField(parent *unison.Panel, targetMgr *TargetMgr, targetKey, labelText, tooltip string, fieldData *int, min, max int) *IntegerField {
+	label := NewFieldLeadingLabel(labelText)
+	if tooltip != "" {
+		label.Tooltip = unison.NewTooltipWithText(tooltip)
+	}
+	parent.AddChild(label)
+	return addIntegerField(parent, targetMgr, targetKey, labelText, tooltip, fieldData, min, max)
+}
+
 func addIntegerField(parent *unison.Panel, targetMgr *TargetMgr, targetKey, labelText, tooltip string, fieldData *int, min, max int) *IntegerField {
 	field := NewIntegerField(targetMgr, targetKey, labelText,
 		func() int { return *fieldData },
@@ -369,6 +379,30 @@ func addPopup[T comparable](parent *unison.Panel, choices []T, fieldData *T) *un
 	return popup
 }
 
+func addCurrencyPopup(parent *unison.Panel, entity *model.Entity, fieldData *string) *unison.PopupMenu[string] {
+	settings := model.SheetSettingsFor(entity)
+	popup := unison.NewPopupMenu[string]()
+	popup.AddItem(i18n.Text("Default Currency"))
+	selected := 0
+	for i, one := range settings.Currencies {
+		popup.AddItem(one.String())
+		if strings.EqualFold(one.ID, *fieldData) {
+			selected = i + 1
+		}
+	}
+	popup.SelectIndex(selected)
+	popup.SelectionChangedCallback = func(p *unison.PopupMenu[string]) {
+		if i := p.SelectedIndex(); i > 0 {
+			*fieldData = settings.Currencies[i-1].ID
+		} else {
+			*fieldData = ""
+		}
+		MarkModified(parent)
+	}
+	parent.AddChild(popup)
+	return popup
+}
+
 func addBoolPopup(parent *unison.Panel, trueChoice, falseChoice string, fieldData *bool) *unison.PopupMenu[string] {
 	popup := unison.NewPopupMenu[string]()
 	popup.AddItem(trueChoice)
@@ -0,0 +1,383 @@
+/*
+ * Copyright ©1998-2022 by Richard A. Wilkes. All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public
+ * License, version 2.0. If a copy of the MPL was not distributed with
+ * this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+ *
+ * This Source Code Form is "Incompatible With Secondary Licenses", as
+ * defined by the Mozilla Public License, version 2.0.
+ */
+
+package ux
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/richardwilkes/gcs/v5/model"
+	"github.com/richardwilkes/toolbox/i18n"
+	"github.com/richardwilkes/toolbox/xio/fs"
+	"github.com/richardwilkes/unison"
+)
+
+const (
+	// wikiSheetIndexLifetime is how long the index of sheet files found on disk is reused before being rebuilt.
+	wikiSheetIndexLifetime = 10 * time.Second
+	// wikiEntityCheckInterval is how long a sheet loaded from disk is reused before checking whether its file changed.
+	wikiEntityCheckInterval = 2 * time.Second
+)
+
+var (
+	wikiSheetIndexes = make(map[string]*wikiSheetIndex)
+	wikiEntityCache  = make(map[string]*wikiCachedEntity)
+)
+
+type wikiSheetIndex struct {
+	built    time.Time
+	paths    map[string]string
+	building bool
+}
+
+type wikiCachedEntity struct {
+	signature model.FileSignature
+	checked   time.Time
+	entity    *model.Entity
+}
+
+// wikiLinkText returns the text to display for a wiki link found in markdown located in the working directory.
+func wikiLinkText(workingDir string, link *model.WikiLink) string {
+	if link.Label != "" {
+		return link.Label
+	}
+	switch link.Kind {
+	case model.WikiPageRef:
+		return link.Target
+	case model.WikiLibrary:
+		_, item := splitWikiLibraryItem(link.Item())
+		if item != "" {
+			return item
+		}
+	default:
+		if entity, _ := wikiEntity(workingDir, link.Owner()); entity != nil {
+			if text, _, ok := entity.WikiText(link); ok {
+				return text
+			}
+		}
+	}
+	return fmt.Sprintf(i18n.Text("%s (not found)"), link.Target)
+}
+
+// wikiEntity returns the entity with the given name, preferring an open sheet over one found on disk. If the entity
+// belongs to an open sheet, that sheet is also returned.
+func wikiEntity(workingDir, name string) (*model.Entity, *Sheet) {
+	if sheet := openSheetNamed(name); sheet != nil {
+		return sheet.entity, sheet
+	}
+	filePath := wikiSheetPath(workingDir, name)
+	if filePath == "" {
+		return nil, nil
+	}
+	cached, exists := wikiEntityCache[filePath]
+	if exists && time.Since(cached.checked) < wikiEntityCheckInterval {
+		return cached.entity, nil
+	}
+	sig := model.NewFileSignature(filePath)
+	if exists && cached.signature.Equal(sig) {
+		cached.checked = time.Now()
+		return cached.entity, nil
+	}
+	entity, err := model.NewEntityFromFile(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath))
+	if err != nil {
+		delete(wikiEntityCache, filePath)
+		return nil, nil
+	}
+	wikiEntityCache[filePath] = &wikiCachedEntity{
+		signature: sig,
+		checked:   time.Now(),
+		entity:    entity,
+	}
+	return entity, nil
+}
+
+func openSheetNamed(name string) *Sheet {
+	var found *Sheet
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, one := range dc.Dockables() {
+					if s, ok := one.(*Sheet); ok && (strings.EqualFold(s.entity.Profile.Name, name) ||
+						strings.EqualFold(fs.TrimExtension(filepath.Base(s.path)), name)) {
+						found = s
+						return true
+					}
+				}
+				return false
+			})
+			if found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// wikiSheetPath looks for a sheet file with the given name, first within the working directory and then within the
+// libraries. The index of sheet files is built in the background whenever it is missing or stale, with any markdown
+// being displayed refreshed once it is ready.
+func wikiSheetPath(workingDir, name string) string {
+	index, exists := wikiSheetIndexes[workingDir]
+	if !exists {
+		index = &wikiSheetIndex{}
+		wikiSheetIndexes[workingDir] = index
+	}
+	if !index.building && (!exists || time.Since(index.built) > wikiSheetIndexLifetime) {
+		index.building = true
+		roots := wikiSheetIndexRoots(workingDir)
+		go func() {
+			paths := buildWikiSheetIndex(roots)
+			unison.InvokeTask(func() {
+				wikiSheetIndexes[workingDir] = &wikiSheetIndex{
+					built: time.Now(),
+					paths: paths,
+				}
+				refreshWikiLinks()
+			})
+		}()
+	}
+	return index.paths[strings.ToLower(strings.TrimSpace(name))]
+}
+
+func wikiSheetIndexRoots(workingDir string) []string {
+	roots := make([]string, 0, 8)
+	if workingDir != "" {
+		roots = append(roots, workingDir)
+	}
+	for _, lib := range model.GlobalSettings().Libraries().List() {
+		roots = append(roots, lib.PathOnDisk)
+	}
+	return roots
+}
+
+func buildWikiSheetIndex(roots []string) map[string]string {
+	paths := make(map[string]string)
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(p string, entry iofs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.EqualFold(filepath.Ext(p), model.SheetExt) {
+				return nil
+			}
+			key := strings.ToLower(fs.TrimExtension(entry.Name()))
+			if _, dup := paths[key]; !dup {
+				paths[key] = p
+			}
+			return nil
+		})
+	}
+	return paths
+}
+
+// handleWikiLink navigates to the target of a wiki link.
+func handleWikiLink(wnd *unison.Window, workingDir string, link *model.WikiLink) {
+	switch link.Kind {
+	case model.WikiPageRef:
+		OpenPageReference(wnd, link.Target, "", nil)
+	case model.WikiLibrary:
+		openWikiLibraryItem(wnd, link)
+	default:
+		entity, sheet := wikiEntity(workingDir, link.Owner())
+		if entity == nil {
+			showUnresolvedWikiLink(link)
+			return
+		}
+		_, rowID, ok := entity.WikiText(link)
+		if !ok {
+			showUnresolvedWikiLink(link)
+			return
+		}
+		if sheet == nil {
+			d, _ := OpenFile(wnd, wikiSheetPath(workingDir, link.Owner()))
+			if sheet, ok = d.(*Sheet); !ok {
+				return
+			}
+			// The freshly opened sheet has its own copy of the data, so locate the row again.
+			_, rowID, _ = sheet.entity.WikiText(link)
+		} else if dc := unison.Ancestor[*unison.DockContainer](sheet); dc != nil {
+			dc.SetCurrentDockable(sheet)
+			dc.AcquireFocus()
+		}
+		if rowID != uuid.Nil {
+			revealWikiRowInSheet(sheet, link.Kind, rowID)
+		}
+	}
+}
+
+func revealWikiRowInSheet(sheet *Sheet, kind string, rowID uuid.UUID) {
+	switch kind {
+	case model.WikiTrait:
+		revealWikiRow(sheet.Traits, rowID)
+	case model.WikiSkill:
+		revealWikiRow(sheet.Skills, rowID)
+	case model.WikiSpell:
+		revealWikiRow(sheet.Spells, rowID)
+	case model.WikiEquipment:
+		if !revealWikiRow(sheet.CarriedEquipment, rowID) {
+			revealWikiRow(sheet.OtherEquipment, rowID)
+		}
+	case model.WikiNote:
+		revealWikiRow(sheet.Notes, rowID)
+	}
+}
+
+func revealWikiRow[T model.NodeTypes](list *PageList[T], rowID uuid.UUID) bool {
+	if list == nil {
+		return false
+	}
+	return revealWikiRowInTable(list.Table, rowID)
+}
+
+func revealWikiRowInTable[T model.NodeTypes](table *unison.Table[*Node[T]], rowID uuid.UUID) bool {
+	if row := findNodeByID(table.RootRows(), rowID); row != nil {
+		showSearchResolvedRef(table, row)
+		table.RequestFocus()
+		return true
+	}
+	return false
+}
+
+func findNodeByID[T model.NodeTypes](rows []*Node[T], rowID uuid.UUID) *Node[T] {
+	for _, row := range rows {
+		if row.UUID() == rowID {
+			return row
+		}
+		if found := findNodeByID(row.Children(), rowID); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// splitWikiLibraryItem splits the portion of a library link after the library name into the sub-path to search and the
+// name of the item.
+func splitWikiLibraryItem(target string) (subPath, item string) {
+	if i := strings.LastIndex(target, "/"); i != -1 {
+		return strings.Trim(target[:i], "/ "), strings.TrimSpace(target[i+1:])
+	}
+	return "", strings.TrimSpace(target)
+}
+
+func wikiLibraryNamed(name string) *model.Library {
+	for _, lib := range model.GlobalSettings().Libraries().List() {
+		if strings.EqualFold(lib.Title, name) || strings.EqualFold(strings.TrimSuffix(lib.Title, " Library"), name) {
+			return lib
+		}
+	}
+	return nil
+}
+
+// openWikiLibraryItem locates the first list file within the library whose relative path starts with the link's
+// sub-path and which contains an item with the link's name, then opens it with that item selected.
+func openWikiLibraryItem(wnd *unison.Window, link *model.WikiLink) {
+	lib := wikiLibraryNamed(link.Owner())
+	if lib == nil {
+		showUnresolvedWikiLink(link)
+		return
+	}
+	subPath, name := splitWikiLibraryItem(link.Item())
+	subPath = strings.ToLower(filepath.FromSlash(subPath))
+	root := lib.Path()
+	var foundPath string
+	var foundID uuid.UUID
+	_ = filepath.WalkDir(root, func(p string, entry iofs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return nil
+		}
+		rel = strings.ToLower(fs.TrimExtension(rel))
+		if subPath != "" && rel != subPath && !strings.HasPrefix(rel, subPath+string(filepath.Separator)) {
+			return nil
+		}
+		if id, ok := findWikiLibraryRow(p, name); ok {
+			foundPath = p
+			foundID = id
+			return iofs.SkipAll
+		}
+		return nil
+	})
+	if foundPath == "" {
+		showUnresolvedWikiLink(link)
+		return
+	}
+	d, _ := OpenFile(wnd, foundPath)
+	switch td := d.(type) {
+	case *TableDockable[*model.Trait]:
+		revealWikiRowInTable(td.table, foundID)
+	case *TableDockable[*model.Skill]:
+		revealWikiRowInTable(td.table, foundID)
+	case *TableDockable[*model.Spell]:
+		revealWikiRowInTable(td.table, foundID)
+	case *TableDockable[*model.Equipment]:
+		revealWikiRowInTable(td.table, foundID)
+	case *TableDockable[*model.Note]:
+		revealWikiRowInTable(td.table, foundID)
+	}
+}
+
+func findWikiLibraryRow(filePath, name string) (uuid.UUID, bool) {
+	fileSystem := os.DirFS(filepath.Dir(filePath))
+	base := filepath.Base(filePath)
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case model.TraitsExt:
+		return findWikiRowInFile(fileSystem, base, name, model.NewTraitsFromFile)
+	case model.SkillsExt:
+		return findWikiRowInFile(fileSystem, base, name, model.NewSkillsFromFile)
+	case model.SpellsExt:
+		return findWikiRowInFile(fileSystem, base, name, model.NewSpellsFromFile)
+	case model.EquipmentExt:
+		return findWikiRowInFile(fileSystem, base, name, model.NewEquipmentFromFile)
+	case model.NotesExt:
+		return findWikiRowInFile(fileSystem, base, name, model.NewNotesFromFile)
+	default:
+		return uuid.Nil, false
+	}
+}
+
+func findWikiRowInFile[T model.NodeTypes](fileSystem iofs.FS, filePath, name string, loader func(iofs.FS, string) ([]T, error)) (uuid.UUID, bool) {
+	list, err := loader(fileSystem, filePath)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	var zero T
+	if row := model.FindWikiRow(name, list); row != zero {
+		return model.AsNode(row).UUID(), true
+	}
+	return uuid.Nil, false
+}
+
+func showUnresolvedWikiLink(link *model.WikiLink) {
+	unison.ErrorDialogWithMessage(i18n.Text("Unable to resolve link"),
+		fmt.Sprintf(i18n.Text("Nothing matching \"%s:%s\" could be found."), link.Kind, link.Target))
+}
+
+// refreshWikiLinks updates the live values shown by wiki links in any markdown being displayed.
+func refreshWikiLinks() {
+	for _, wnd := range unison.Windows() {
+		if ws := WorkspaceFromWindow(wnd); ws != nil {
+			ws.DocumentDock.RootDockLayout().ForEachDockContainer(func(dc *unison.DockContainer) bool {
+				for _, one := range dc.Dockables() {
+					if d, ok := one.(*MarkdownDockable); ok && strings.Contains(d.content, "[[") {
+						d.eventuallyRenderMarkdown()
+					}
+				}
+				return false
+			})
+		}
+	}
+}
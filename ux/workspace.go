@@ -398,6 +398,8 @@ func SaveDockable(d FileBackedDockable, saver func(filePath string) error, setUn
 		return false
 	}
 	setUnmodified()
+	discardRecoverySnapshot(d)
+	watchBackingFile(d)
 	if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
 		dc.UpdateTitle(d)
 	}
@@ -425,6 +427,8 @@ func SaveDockableAs(d FileBackedDockable, extension string, saver func(filePath
 			return false
 		}
 		setUnmodifiedAndNewPath(filePath)
+		discardRecoverySnapshot(d)
+		watchBackingFile(d)
 		model.GlobalSettings().AddRecentFile(filePath)
 		if dc := unison.Ancestor[*unison.DockContainer](d); dc != nil {
 			dc.UpdateTitle(d)